@@ -0,0 +1,359 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TheCoolRobot/asana-cli/internal/asana"
+	"github.com/TheCoolRobot/asana-cli/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	downloadDir     string
+	downloadMaxSize string
+	downloadForce   bool
+)
+
+// downloadedFile is the --json record of a saved attachment
+type downloadedFile struct {
+	GID         string `json:"gid"`
+	Name        string `json:"name"`
+	Path        string `json:"path"`
+	Size        int64  `json:"size"`
+	ContentType string `json:"content_type,omitempty"`
+}
+
+var attachCmd = &cobra.Command{
+	Use:   "attach [task-id] <file...>",
+	Short: "Attach files to a task",
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		taskGID := args[0]
+		files := args[1:]
+
+		// Check every file before uploading any, so a typo doesn't leave
+		// the task half-attached
+		for _, path := range files {
+			info, err := os.Stat(path)
+			if err != nil {
+				return reportError(err)
+			}
+			if !info.Mode().IsRegular() {
+				return reportError(fmt.Errorf("%s is not a regular file", path))
+			}
+			if info.Size() > asana.MaxAttachmentSize {
+				return reportError(fmt.Errorf("%s: %w (%s, limit is %s)", path, asana.ErrAttachmentTooLarge, humanSize(info.Size()), humanSize(asana.MaxAttachmentSize)))
+			}
+		}
+
+		client := newClient()
+		var attached []asana.Attachment
+		for _, path := range files {
+			f, err := os.Open(path)
+			if err != nil {
+				return reportError(err)
+			}
+			att, err := client.UploadAttachment(cmd.Context(), taskGID, filepath.Base(path), f)
+			f.Close()
+			if err != nil {
+				return reportError(err)
+			}
+			attached = append(attached, *att)
+
+			if !jsonOutput {
+				fmt.Printf("📎 Attached %s (%s)\n", att.Name, humanSize(att.Size))
+			}
+		}
+
+		if jsonOutput {
+			meta := map[string]interface{}{
+				"action":   "attached",
+				"task_gid": taskGID,
+				"count":    len(attached),
+			}
+			ui.PrintJSONWithMeta(attached, meta, nil)
+		}
+
+		return nil
+	},
+}
+
+var attachmentsCmd = &cobra.Command{
+	Use:   "attachments [task-id]",
+	Short: "List a task's attachments",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		taskGID := args[0]
+		client := newClient()
+
+		attachments, err := client.GetAttachments(cmd.Context(), taskGID)
+		if err != nil {
+			return reportError(err)
+		}
+
+		if jsonOutput {
+			meta := map[string]interface{}{
+				"task_gid": taskGID,
+				"count":    len(attachments),
+			}
+			ui.PrintJSONWithMeta(attachments, meta, nil)
+		} else if len(attachments) == 0 {
+			fmt.Println("No attachments")
+		} else {
+			for _, att := range attachments {
+				fmt.Printf("📎 %s\n", att.Name)
+				fmt.Printf("   GID: %s  Size: %s  Added: %s\n", att.GID, humanSize(att.Size), att.CreatedAt.Local().Format("2006-01-02 15:04"))
+				if att.Host != "" && att.Host != "asana" {
+					fmt.Printf("   Hosted on %s: %s\n", att.Host, att.ViewURL)
+				}
+			}
+		}
+
+		return nil
+	},
+}
+
+var attachmentsDownloadCmd = &cobra.Command{
+	Use:   "download [task-id] [attachment...]",
+	Short: "Download a task's attachments",
+	Long: `Download a task's attachments into the current directory, or --dir.
+
+Attachments can be picked by name or GID; without any, all of them are
+downloaded. Existing files are only replaced with --force.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		taskGID := args[0]
+
+		maxSize, err := parseSize(downloadMaxSize)
+		if err != nil {
+			return reportError(err)
+		}
+
+		client := newClient()
+		attachments, err := client.GetAttachments(cmd.Context(), taskGID)
+		if err != nil {
+			return reportError(err)
+		}
+		selected, err := selectAttachments(attachments, args[1:])
+		if err != nil {
+			return reportError(err)
+		}
+
+		var saved []downloadedFile
+		for i := range selected {
+			file, err := downloadAttachment(cmd, client, &selected[i], maxSize)
+			if err != nil {
+				return reportError(err)
+			}
+			saved = append(saved, *file)
+
+			if !jsonOutput {
+				fmt.Printf("✓ Saved %s (%s, %s)\n", file.Path, humanSize(file.Size), file.ContentType)
+			}
+		}
+
+		if jsonOutput {
+			meta := map[string]interface{}{
+				"action":   "downloaded",
+				"task_gid": taskGID,
+				"count":    len(saved),
+			}
+			ui.PrintJSONWithMeta(saved, meta, nil)
+		} else if len(saved) == 0 {
+			fmt.Println("No attachments")
+		}
+
+		return nil
+	},
+}
+
+// selectAttachments picks attachments by name or GID, or all of them when
+// no names are given
+func selectAttachments(attachments []asana.Attachment, names []string) ([]asana.Attachment, error) {
+	if len(names) == 0 {
+		return attachments, nil
+	}
+
+	var selected []asana.Attachment
+	for _, name := range names {
+		found := false
+		for _, att := range attachments {
+			if att.GID == name || att.Name == name {
+				selected = append(selected, att)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("no attachment %q on this task", name)
+		}
+	}
+	return selected, nil
+}
+
+// downloadAttachment saves one attachment under --dir, writing to a
+// temporary file first so an interrupted or oversized download never
+// leaves a partial file behind
+func downloadAttachment(cmd *cobra.Command, client *asana.Client, att *asana.Attachment, maxSize int64) (*downloadedFile, error) {
+	if maxSize > 0 && att.Size > maxSize {
+		return nil, fmt.Errorf("%s: %w (%s, limit is %s; raise it with --max-size)", att.Name, asana.ErrAttachmentTooLarge, humanSize(att.Size), humanSize(maxSize))
+	}
+
+	name := filepath.Base(att.Name)
+	if name == "." || name == ".." || name == string(filepath.Separator) {
+		name = att.GID
+	}
+
+	if err := os.MkdirAll(downloadDir, 0o755); err != nil {
+		return nil, err
+	}
+	tmp, err := os.CreateTemp(downloadDir, ".asana-download-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+
+	w := &limitedWriter{w: tmp, limit: maxSize}
+	var out io.Writer = w
+	if !jsonOutput {
+		progress := &progressWriter{w: w, out: os.Stderr, name: name, total: att.Size}
+		defer progress.finish()
+		out = progress
+	}
+
+	contentType, err := client.DownloadAttachment(cmd.Context(), att, out)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		if errors.Is(err, asana.ErrAttachmentTooLarge) {
+			return nil, fmt.Errorf("%s: %w (limit is %s; raise it with --max-size)", att.Name, err, humanSize(maxSize))
+		}
+		return nil, err
+	}
+
+	if contentType == "" || contentType == "application/octet-stream" {
+		contentType = asana.DetectContentType(name, w.head)
+	}
+	// Give extension-less files one matching their content
+	if filepath.Ext(name) == "" {
+		if exts, _ := mime.ExtensionsByType(contentType); len(exts) > 0 {
+			name += exts[0]
+		}
+	}
+
+	path := filepath.Join(downloadDir, name)
+	if _, err := os.Stat(path); err == nil && !downloadForce {
+		return nil, fmt.Errorf("%s already exists (use --force to overwrite)", path)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return nil, err
+	}
+
+	return &downloadedFile{GID: att.GID, Name: att.Name, Path: path, Size: w.written, ContentType: contentType}, nil
+}
+
+// limitedWriter fails once more than limit bytes are written (0 = no
+// limit) and keeps the first bytes for content sniffing
+type limitedWriter struct {
+	w       io.Writer
+	limit   int64
+	written int64
+	head    []byte
+}
+
+func (l *limitedWriter) Write(p []byte) (int, error) {
+	if l.limit > 0 && l.written+int64(len(p)) > l.limit {
+		return 0, asana.ErrAttachmentTooLarge
+	}
+	if len(l.head) < 512 {
+		n := 512 - len(l.head)
+		if n > len(p) {
+			n = len(p)
+		}
+		l.head = append(l.head, p[:n]...)
+	}
+	n, err := l.w.Write(p)
+	l.written += int64(n)
+	return n, err
+}
+
+// progressWriter reports download progress on a single, rewritten line
+type progressWriter struct {
+	w       io.Writer
+	out     io.Writer
+	name    string
+	total   int64
+	written int64
+	last    time.Time
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	p.written += int64(n)
+	if time.Since(p.last) >= 100*time.Millisecond {
+		p.print()
+		p.last = time.Now()
+	}
+	return n, err
+}
+
+func (p *progressWriter) print() {
+	if p.total > 0 {
+		fmt.Fprintf(p.out, "\r⬇ %s  %s / %s (%d%%)", p.name, humanSize(p.written), humanSize(p.total), p.written*100/p.total)
+	} else {
+		fmt.Fprintf(p.out, "\r⬇ %s  %s", p.name, humanSize(p.written))
+	}
+}
+
+func (p *progressWriter) finish() {
+	p.print()
+	fmt.Fprintln(p.out)
+}
+
+// humanSize formats a byte count, e.g. 1536 as "1.5 KB"
+func humanSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGT"[exp])
+}
+
+// parseSize reads a size such as "500KB", "10MB" or "2G"; plain numbers
+// are bytes and 0 means no limit
+func parseSize(s string) (int64, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	num := strings.TrimRight(strings.TrimSuffix(s, "B"), "KMGT")
+	unit := strings.TrimPrefix(strings.TrimSuffix(s, "B"), num)
+
+	n, err := strconv.ParseFloat(strings.TrimSpace(num), 64)
+	if err != nil || n < 0 || len(unit) > 1 {
+		return 0, fmt.Errorf("invalid size %q (e.g. 500KB, 10MB, 1GB)", s)
+	}
+	if unit != "" {
+		n *= float64(int64(1) << (10 * (strings.Index("KMGT", unit) + 1)))
+	}
+	return int64(n), nil
+}
+
+func init() {
+	attachmentsDownloadCmd.Flags().StringVar(&downloadDir, "dir", ".", "Directory to save files in")
+	attachmentsDownloadCmd.Flags().StringVar(&downloadMaxSize, "max-size", "100MB", "Refuse files larger than this (0 = no limit)")
+	attachmentsDownloadCmd.Flags().BoolVar(&downloadForce, "force", false, "Overwrite existing files")
+
+	attachmentsCmd.AddCommand(attachmentsDownloadCmd)
+}
@@ -0,0 +1,264 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/TheCoolRobot/asana-cli/internal/asana"
+	"github.com/TheCoolRobot/asana-cli/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	bulkStdin       bool
+	bulkConcurrency int
+)
+
+// bulkResult is the outcome of a bulk command for one task
+type bulkResult struct {
+	GID     string `json:"gid"`
+	Name    string `json:"name,omitempty"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+	Code    string `json:"code,omitempty"`
+}
+
+// addBulkFlags adds --stdin and --concurrency to a command that takes one
+// or more task GIDs
+func addBulkFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&bulkStdin, "stdin", false, "Also read task GIDs from standard input, one per line or NDJSON records with a gid")
+	cmd.Flags().IntVar(&bulkConcurrency, "concurrency", 4, "Number of tasks to work on at once")
+}
+
+// taskGIDArgs requires at least one task GID, unless they come from --stdin
+func taskGIDArgs(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 && !bulkStdin {
+		return errors.New("requires at least one task GID, or --stdin")
+	}
+	return nil
+}
+
+// bulkMode reports whether a command works on a batch of tasks and so
+// prints a per-task summary, rather than the single task it was given
+func bulkMode(args []string) bool {
+	return len(args) != 1 || bulkStdin
+}
+
+// taskGIDs collects the task GIDs of a bulk command from its arguments and,
+// with --stdin, from standard input, dropping duplicates
+func taskGIDs(cmd *cobra.Command, args []string) ([]string, error) {
+	if bulkConcurrency < 1 {
+		return nil, errors.New("--concurrency must be at least 1")
+	}
+
+	gids := append([]string(nil), args...)
+	if bulkStdin {
+		read, err := readTaskGIDs(cmd.InOrStdin())
+		if err != nil {
+			return nil, err
+		}
+		gids = append(gids, read...)
+	}
+	if len(gids) == 0 {
+		return nil, errors.New("no task GIDs on standard input")
+	}
+
+	seen := make(map[string]bool, len(gids))
+	unique := gids[:0]
+	for _, gid := range gids {
+		if !seen[gid] {
+			seen[gid] = true
+			unique = append(unique, gid)
+		}
+	}
+	return unique, nil
+}
+
+// readTaskGIDs reads one task per line, either a bare GID or a JSON object
+// with a gid such as the records of list --ndjson. Blank lines are skipped.
+func readTaskGIDs(r io.Reader) ([]string, error) {
+	var gids []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "{") {
+			var record struct {
+				GID string `json:"gid"`
+			}
+			if err := json.Unmarshal([]byte(line), &record); err != nil {
+				return nil, fmt.Errorf("stdin line %d: %v", n, err)
+			}
+			line = record.GID
+		}
+		if !isGID(line) {
+			return nil, fmt.Errorf("stdin line %d: %q is not a task GID", n, line)
+		}
+		gids = append(gids, line)
+	}
+	return gids, scanner.Err()
+}
+
+// batchThreshold is the number of tasks above which bulk commands that
+// can send their changes through the Batch API do so, ten actions to a
+// request, rather than making a request per task
+const batchThreshold = 5
+
+// bulkOp is the change a bulk command makes to each task
+type bulkOp struct {
+	// call makes the change to one task and returns the task's name, if it
+	// knows it
+	call func(ctx context.Context, gid string) (string, error)
+	// actions, if set, returns the batch actions that make the same change,
+	// used instead of call above batchThreshold tasks
+	actions func(gid string) ([]asana.BatchAction, error)
+	// prepare, if set, runs before the actions are built, to fetch what
+	// they need for all the tasks at once
+	prepare func(ctx context.Context, gids []string)
+}
+
+// runBulk makes op's change to every task on --concurrency workers and
+// returns the results in the order of gids
+func runBulk(ctx context.Context, client *asana.Client, gids []string, op bulkOp) []bulkResult {
+	if op.actions != nil && len(gids) > batchThreshold {
+		return runBatched(ctx, client, gids, op)
+	}
+
+	results := make([]bulkResult, len(gids))
+	runWorkers(len(gids), func(i int) {
+		name, err := op.call(ctx, gids[i])
+		results[i] = bulkResult{GID: gids[i], Name: name, Success: true}
+		results[i].fail(err)
+	})
+	return results
+}
+
+// runBatched sends the actions of every task through the Batch API, the
+// requests spread over --concurrency workers. A task fails if any of its
+// actions did.
+func runBatched(ctx context.Context, client *asana.Client, gids []string, op bulkOp) []bulkResult {
+	results := make([]bulkResult, len(gids))
+	for i, gid := range gids {
+		results[i] = bulkResult{GID: gid, Success: true}
+	}
+	if op.prepare != nil {
+		op.prepare(ctx, gids)
+	}
+
+	var actions []asana.BatchAction
+	var owners []int
+	for i, gid := range gids {
+		taskActions, err := op.actions(gid)
+		if err != nil {
+			results[i].fail(err)
+			continue
+		}
+		for _, action := range taskActions {
+			actions = append(actions, action)
+			owners = append(owners, i)
+		}
+	}
+
+	batched := make([]asana.BatchResult, len(actions))
+	requests := (len(actions) + asana.MaxBatchActions - 1) / asana.MaxBatchActions
+	runWorkers(requests, func(n int) {
+		start := n * asana.MaxBatchActions
+		end := min(start+asana.MaxBatchActions, len(actions))
+		// Failed requests are reported on each of their actions
+		chunk, _ := client.Batch(ctx, actions[start:end])
+		copy(batched[start:end], chunk)
+	})
+
+	for j, r := range batched {
+		result := &results[owners[j]]
+		if !result.Success {
+			continue
+		}
+		if r.Err != nil {
+			result.fail(r.Err)
+		} else if task, err := r.Task(); err == nil && result.Name == "" {
+			result.Name = task.Name
+		}
+	}
+	return results
+}
+
+// fail records err, if any, as the reason the task failed
+func (r *bulkResult) fail(err error) {
+	if err == nil {
+		return
+	}
+	r.Success = false
+	r.Error = err.Error()
+	r.Code = asana.ErrorCode(err)
+}
+
+// runWorkers calls fn for 0..n-1 on --concurrency goroutines
+func runWorkers(n int, fn func(i int)) {
+	jobs := make(chan int)
+	workers := min(bulkConcurrency, n)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// printBulk prints the results of a bulk command as a summary table, or a
+// JSON array with --json, and returns an error if any task failed so the
+// exit code is non-zero. meta adds to the counts in the JSON meta.
+func printBulk(action string, results []bulkResult, meta map[string]interface{}) error {
+	failed := 0
+	for _, r := range results {
+		if !r.Success {
+			failed++
+		}
+	}
+	var err error
+	if failed > 0 {
+		err = fmt.Errorf("%d of %d tasks failed", failed, len(results))
+	}
+
+	if jsonOutput {
+		counts := map[string]interface{}{
+			"action":    action,
+			"count":     len(results),
+			"succeeded": len(results) - failed,
+			"failed":    failed,
+		}
+		for k, v := range meta {
+			counts[k] = v
+		}
+		ui.PrintJSONWithMeta(results, counts, err)
+		return err
+	}
+
+	for _, r := range results {
+		if r.Success {
+			fmt.Printf("✓ %-20s %s\n", r.GID, r.Name)
+		} else {
+			fmt.Printf("✗ %-20s %s\n", r.GID, r.Error)
+		}
+	}
+	fmt.Printf("\n%d %s, %d failed\n", len(results)-failed, action, failed)
+	return err
+}
@@ -0,0 +1,1632 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TheCoolRobot/asana-cli/internal/asana"
+	"github.com/TheCoolRobot/asana-cli/internal/asanatest"
+	"github.com/TheCoolRobot/asana-cli/internal/syncdaemon"
+	"github.com/TheCoolRobot/asana-cli/internal/ui"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// newTestServer starts a fake Asana API and points the CLI at it, with an
+// isolated HOME so config and cache files never touch the real ones
+func newTestServer(t *testing.T) *asanatest.Server {
+	t.Helper()
+
+	srv := asanatest.NewServer(t)
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("ASANA_TOKEN", "test-token")
+	t.Setenv("ASANA_BASE_URL", srv.URL)
+
+	return srv
+}
+
+// resetFlags restores every flag in the command tree to its default, since
+// cobra keeps parsed values in package-level variables between runs
+func resetFlags(c *cobra.Command) {
+	reset := func(f *pflag.Flag) {
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			sv.Replace(nil)
+		} else {
+			f.Value.Set(f.DefValue)
+		}
+		f.Changed = false
+	}
+	c.Flags().VisitAll(reset)
+	c.PersistentFlags().VisitAll(reset)
+	for _, sub := range c.Commands() {
+		resetFlags(sub)
+	}
+}
+
+// runCLI executes the root command with args and returns what it printed
+// to stdout
+func runCLI(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+
+	resetFlags(rootCmd)
+	token = ""
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	out := make(chan string)
+	go func() {
+		b, _ := io.ReadAll(r)
+		out <- string(b)
+	}()
+
+	rootCmd.SetArgs(args)
+	runErr := execute(context.Background())
+	cancelTimeout()
+
+	w.Close()
+	return <-out, runErr
+}
+
+// decodeEnvelope parses the --json output of a command
+func decodeEnvelope(t *testing.T, out string, data interface{}) map[string]interface{} {
+	t.Helper()
+
+	var envelope struct {
+		Success bool                   `json:"success"`
+		Data    json.RawMessage        `json:"data"`
+		Error   string                 `json:"error"`
+		Code    string                 `json:"code"`
+		Meta    map[string]interface{} `json:"meta"`
+	}
+	if err := json.Unmarshal([]byte(out), &envelope); err != nil {
+		t.Fatalf("output is not a JSON envelope: %v\n%s", err, out)
+	}
+	if data != nil {
+		if !envelope.Success {
+			t.Fatalf("command failed: %s (%s)", envelope.Error, envelope.Code)
+		}
+		if err := json.Unmarshal(envelope.Data, data); err != nil {
+			t.Fatalf("unexpected data: %v\n%s", err, out)
+		}
+	}
+	if envelope.Meta == nil {
+		envelope.Meta = map[string]interface{}{}
+	}
+	envelope.Meta["_code"] = envelope.Code
+	return envelope.Meta
+}
+
+func TestListCommand(t *testing.T) {
+	srv := newTestServer(t)
+	ws := srv.AddWorkspace("Acme")
+	proj := srv.AddProject(ws.GID, "Roadmap")
+	for _, name := range []string{"One", "Two", "Three"} {
+		srv.AddTask(proj.GID, asana.Task{Name: name, Completed: name == "Two"})
+	}
+
+	out, err := runCLI(t, "list", proj.GID, "--json")
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	var tasks []asana.Task
+	meta := decodeEnvelope(t, out, &tasks)
+	if len(tasks) != 3 || meta["count"] != float64(3) {
+		t.Errorf("expected 3 tasks, got %d (meta %v)", len(tasks), meta)
+	}
+
+	out, err = runCLI(t, "list", proj.GID, "--json", "--limit", "2")
+	if err != nil {
+		t.Fatalf("list --limit failed: %v", err)
+	}
+	decodeEnvelope(t, out, &tasks)
+	if len(tasks) != 2 {
+		t.Errorf("--limit not applied: got %d tasks", len(tasks))
+	}
+}
+
+func TestViewCommandNotFound(t *testing.T) {
+	newTestServer(t)
+
+	out, err := runCLI(t, "view", "404", "--json")
+	if !asana.IsNotFound(err) {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+	if meta := decodeEnvelope(t, out, nil); meta["_code"] != "not_found" {
+		t.Errorf("expected not_found code, got %v", meta["_code"])
+	}
+}
+
+func TestTaskLifecycleCommands(t *testing.T) {
+	srv := newTestServer(t)
+	ws := srv.AddWorkspace("Acme")
+	proj := srv.AddProject(ws.GID, "Roadmap")
+
+	out, err := runCLI(t, "create", proj.GID, "--name", "Ship it", "--due", "2026-05-01", "--json")
+	if err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	var created asana.Task
+	decodeEnvelope(t, out, &created)
+	if created.GID == "" || created.Name != "Ship it" {
+		t.Fatalf("unexpected created task: %+v", created)
+	}
+
+	if _, err := runCLI(t, "update", created.GID, "--name", "Ship it now", "--json"); err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+	if _, err := runCLI(t, "complete", created.GID, "--json"); err != nil {
+		t.Fatalf("complete failed: %v", err)
+	}
+
+	stored, _ := srv.Task(created.GID)
+	if stored.Name != "Ship it now" || !stored.Completed {
+		t.Errorf("unexpected stored task: %+v", stored)
+	}
+
+	out, err = runCLI(t, "view", created.GID)
+	if err != nil {
+		t.Fatalf("view failed: %v", err)
+	}
+	if !strings.Contains(out, "Ship it now") || !strings.Contains(out, "2026-05-01") {
+		t.Errorf("unexpected view output:\n%s", out)
+	}
+
+	if _, err := runCLI(t, "delete", created.GID, "--json"); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+	if _, ok := srv.Task(created.GID); ok {
+		t.Error("task still exists after delete")
+	}
+}
+
+func TestSearchAndMeCommands(t *testing.T) {
+	srv := newTestServer(t)
+	ws := srv.AddWorkspace("Acme")
+	proj := srv.AddProject(ws.GID, "Roadmap")
+	srv.AddTask(proj.GID, asana.Task{Name: "Fix login bug"})
+	srv.AddTask(proj.GID, asana.Task{Name: "Write docs"})
+
+	out, err := runCLI(t, "search", ws.GID, "login", "--json")
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	var tasks []asana.Task
+	decodeEnvelope(t, out, &tasks)
+	if len(tasks) != 1 || tasks[0].Name != "Fix login bug" {
+		t.Errorf("unexpected search results: %+v", tasks)
+	}
+
+	out, err = runCLI(t, "me")
+	if err != nil {
+		t.Fatalf("me failed: %v", err)
+	}
+	if !strings.Contains(out, srv.Me().Name) {
+		t.Errorf("unexpected me output:\n%s", out)
+	}
+}
+
+func TestAuthErrorCode(t *testing.T) {
+	srv := newTestServer(t)
+	srv.Fail(asanatest.Failure{Path: "/users/me", Status: http.StatusUnauthorized})
+
+	out, err := runCLI(t, "me", "--json")
+	if !asana.IsAuth(err) {
+		t.Fatalf("expected auth error, got %v", err)
+	}
+	if meta := decodeEnvelope(t, out, nil); meta["_code"] != "unauthorized" {
+		t.Errorf("expected unauthorized code, got %v", meta["_code"])
+	}
+}
+
+func TestListRequestsFieldsAndProjectsOutput(t *testing.T) {
+	srv := newTestServer(t)
+	ws := srv.AddWorkspace("Acme")
+	proj := srv.AddProject(ws.GID, "Roadmap")
+	me := srv.Me()
+	due := asana.CustomTime{}
+	due.Time, _ = time.Parse("2006-01-02", "2026-04-01")
+	srv.AddTask(proj.GID, asana.Task{Name: "Assigned", Assignee: &me, DueDate: &due})
+
+	// Default fields populate the records the API would otherwise compact
+	out, err := runCLI(t, "list", proj.GID, "--json")
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	var tasks []asana.Task
+	decodeEnvelope(t, out, &tasks)
+	if len(tasks) != 1 || tasks[0].Assignee == nil || tasks[0].DueDate == nil {
+		t.Fatalf("default fields not populated: %+v", tasks)
+	}
+
+	out, err = runCLI(t, "list", proj.GID, "--json", "--fields", "name,assignee.name")
+	if err != nil {
+		t.Fatalf("list --fields failed: %v", err)
+	}
+	var records []map[string]interface{}
+	decodeEnvelope(t, out, &records)
+	if len(records) != 1 || len(records[0]) != 3 || records[0]["due_on"] != nil {
+		t.Errorf("expected only gid, name and assignee: %v", records)
+	}
+
+	reqs := srv.Requests()
+	if got := reqs[len(reqs)-1].Query.Get("opt_fields"); got != "name,assignee.name" {
+		t.Errorf("unexpected opt_fields sent: %s", got)
+	}
+}
+
+func TestCommentAndViewTimeline(t *testing.T) {
+	srv := newTestServer(t)
+	task := srv.AddTask("", asana.Task{Name: "Review PR"})
+	srv.AddStory(task.GID, asana.Story{Type: "system", ResourceSubtype: "assigned", Text: "assigned to Test User"})
+
+	out, err := runCLI(t, "comment", task.GID, "Ship it", "--json")
+	if err != nil {
+		t.Fatalf("comment failed: %v", err)
+	}
+	var story asana.Story
+	if meta := decodeEnvelope(t, out, &story); meta["action"] != "commented" || story.Text != "Ship it" {
+		t.Errorf("unexpected comment output: %v %+v", meta, story)
+	}
+
+	// The editor is used when no text is given; a fake one writes the file
+	editor := t.TempDir() + "/editor.sh"
+	os.WriteFile(editor, []byte("#!/bin/sh\nprintf 'From the editor\\n# ignored\\n' > \"$1\"\n"), 0o755)
+	t.Setenv("VISUAL", editor)
+	if _, err := runCLI(t, "comment", task.GID); err != nil {
+		t.Fatalf("comment via editor failed: %v", err)
+	}
+	if stories := srv.Stories(task.GID); len(stories) != 3 || stories[2].Text != "From the editor" {
+		t.Errorf("unexpected stories: %+v", stories)
+	}
+
+	out, err = runCLI(t, "view", task.GID)
+	if err != nil {
+		t.Fatalf("view failed: %v", err)
+	}
+	for _, want := range []string{"Activity:", "• ", "💬 ", "Test User", "Ship it", "From the editor"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("view output missing %q:\n%s", want, out)
+		}
+	}
+
+	out, err = runCLI(t, "view", task.GID, "--json")
+	if err != nil {
+		t.Fatalf("view --json failed: %v", err)
+	}
+	var detail struct {
+		Name    string        `json:"name"`
+		Stories []asana.Story `json:"stories"`
+	}
+	decodeEnvelope(t, out, &detail)
+	if detail.Name != "Review PR" || len(detail.Stories) != 3 {
+		t.Errorf("unexpected view JSON: %+v", detail)
+	}
+}
+
+func TestSubtaskCommands(t *testing.T) {
+	srv := newTestServer(t)
+	parent := srv.AddTask("", asana.Task{Name: "Launch"})
+	srv.AddSubtask(parent.GID, asana.Task{Name: "Write copy", Completed: true})
+
+	out, err := runCLI(t, "subtask", "add", parent.GID, "--name", "Design banner", "--json")
+	if err != nil {
+		t.Fatalf("subtask add failed: %v", err)
+	}
+	var banner asana.Task
+	decodeEnvelope(t, out, &banner)
+
+	if _, err := runCLI(t, "create", "--parent", banner.GID, "--name", "Pick colours"); err != nil {
+		t.Fatalf("create --parent failed: %v", err)
+	}
+	if _, err := runCLI(t, "create", "--name", "Orphan"); err == nil {
+		t.Error("expected create without project or parent to fail")
+	}
+
+	out, err = runCLI(t, "view", parent.GID)
+	if err != nil {
+		t.Fatalf("view failed: %v", err)
+	}
+	for _, want := range []string{"Subtasks (1/2 complete)", "✓ Write copy", "○ Design banner (0/1)", "Pick colours"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("view output missing %q:\n%s", want, out)
+		}
+	}
+
+	out, err = runCLI(t, "subtask", "list", parent.GID, "--recursive", "--json")
+	if err != nil {
+		t.Fatalf("subtask list failed: %v", err)
+	}
+	var tree []subtaskNode
+	meta := decodeEnvelope(t, out, &tree)
+	if len(tree) != 2 || len(tree[1].Subtasks) != 1 || meta["completed"] != float64(1) {
+		t.Errorf("unexpected subtask tree: %+v (meta %v)", tree, meta)
+	}
+
+	if _, err := runCLI(t, "subtask", "move", banner.GID, "--top-level"); err != nil {
+		t.Fatalf("subtask move failed: %v", err)
+	}
+	if subs := srv.Subtasks(parent.GID); len(subs) != 1 {
+		t.Errorf("expected 1 subtask after move, got %+v", subs)
+	}
+	if _, err := runCLI(t, "subtask", "move", banner.GID); err == nil {
+		t.Error("expected move without --parent or --top-level to fail")
+	}
+}
+
+func TestDependCommandsAndGraph(t *testing.T) {
+	srv := newTestServer(t)
+	ws := srv.AddWorkspace("Acme")
+	proj := srv.AddProject(ws.GID, "Roadmap")
+	design := srv.AddTask(proj.GID, asana.Task{Name: "Design", Completed: true})
+	build := srv.AddTask(proj.GID, asana.Task{Name: "Build"})
+	ship := srv.AddTask(proj.GID, asana.Task{Name: `Ship "v1"`})
+	legal := srv.AddTask("", asana.Task{Name: "Legal review"})
+
+	if _, err := runCLI(t, "depend", "add", build.GID, "--on", design.GID); err != nil {
+		t.Fatalf("depend add failed: %v", err)
+	}
+	if _, err := runCLI(t, "depend", "add", ship.GID, "--on", build.GID+","+legal.GID); err != nil {
+		t.Fatalf("depend add failed: %v", err)
+	}
+
+	// design <- build <- ship, so design depending on ship is a loop
+	out, err := runCLI(t, "depend", "add", design.GID, "--on", ship.GID)
+	if err == nil || !strings.Contains(out, design.GID+" → "+ship.GID+" → "+build.GID+" → "+design.GID) {
+		t.Errorf("expected cycle to be refused, got %v:\n%s", err, out)
+	}
+	for _, req := range srv.Requests() {
+		if strings.HasSuffix(req.Path, "/addDependencies") && strings.Contains(req.Path, design.GID) {
+			t.Error("cyclic dependency was sent to the API")
+		}
+	}
+
+	out, err = runCLI(t, "view", build.GID)
+	if err != nil {
+		t.Fatalf("view failed: %v", err)
+	}
+	if !strings.Contains(out, "Blocked by: Design") || !strings.Contains(out, `Blocking: Ship "v1"`) {
+		t.Errorf("view output missing dependencies:\n%s", out)
+	}
+
+	out, err = runCLI(t, "graph", proj.GID)
+	if err != nil {
+		t.Fatalf("graph failed: %v", err)
+	}
+	for _, want := range []string{
+		"digraph dependencies {",
+		fmt.Sprintf(`"%s" -> "%s";`, design.GID, build.GID),
+		fmt.Sprintf(`"%s" [label="Ship \"v1\""];`, ship.GID),
+		fmt.Sprintf(`"%s" [label="Legal review", style="rounded,dashed"];`, legal.GID),
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("DOT output missing %q:\n%s", want, out)
+		}
+	}
+
+	out, err = runCLI(t, "graph", proj.GID, "--syntax", "mermaid")
+	if err != nil {
+		t.Fatalf("graph --syntax mermaid failed: %v", err)
+	}
+	for _, want := range []string{"flowchart LR", fmt.Sprintf("t%s --> t%s", build.GID, ship.GID), "#quot;v1#quot;", "class t" + design.GID + " done"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Mermaid output missing %q:\n%s", want, out)
+		}
+	}
+
+	if _, err := runCLI(t, "depend", "remove", build.GID, "--on", design.GID); err != nil {
+		t.Fatalf("depend remove failed: %v", err)
+	}
+	if got, _ := srv.Task(build.GID); len(got.Dependencies) != 0 {
+		t.Errorf("dependency not removed: %+v", got.Dependencies)
+	}
+}
+
+func TestCustomFieldCommands(t *testing.T) {
+	srv := newTestServer(t)
+	ws := srv.AddWorkspace("Acme")
+	proj := srv.AddProject(ws.GID, "Roadmap")
+	srv.AddCustomField(proj.GID, asana.CustomField{Name: "Story Points", Type: asana.CustomFieldNumber})
+	srv.AddCustomField(proj.GID, asana.CustomField{Name: "Priority", Type: asana.CustomFieldEnum, EnumOptions: []asana.EnumOption{{Name: "High"}, {Name: "Low"}}})
+	srv.AddTask(proj.GID, asana.Task{Name: "Unestimated"})
+
+	out, err := runCLI(t, "create", proj.GID, "--name", "Estimate", "--field", "Story Points=5", "--priority", "low", "--json")
+	if err != nil {
+		t.Fatalf("create --field failed: %v\n%s", err, out)
+	}
+	var created asana.Task
+	decodeEnvelope(t, out, &created)
+
+	if _, err := runCLI(t, "update", created.GID, "--field", "Priority=High"); err != nil {
+		t.Fatalf("update --field failed: %v", err)
+	}
+	out, err = runCLI(t, "update", created.GID, "--field", "Priority=Urgent")
+	if err == nil || !strings.Contains(out, "options: High, Low") {
+		t.Errorf("expected unknown option error, got %v:\n%s", err, out)
+	}
+
+	out, err = runCLI(t, "view", created.GID)
+	if err != nil {
+		t.Fatalf("view failed: %v", err)
+	}
+	if !strings.Contains(out, "Story Points: 5") || !strings.Contains(out, "Priority: High") {
+		t.Errorf("view output missing custom fields:\n%s", out)
+	}
+
+	out, err = runCLI(t, "list", proj.GID, "--field", "priority=high", "--fields", "name", "--json")
+	if err != nil {
+		t.Fatalf("list --field failed: %v", err)
+	}
+	var records []map[string]interface{}
+	decodeEnvelope(t, out, &records)
+	if len(records) != 1 || records[0]["name"] != "Estimate" || records[0]["custom_fields"] != nil {
+		t.Errorf("unexpected filtered list: %v", records)
+	}
+
+	out, err = runCLI(t, "list", proj.GID, "--field", "Story Points=", "--json")
+	if err != nil {
+		t.Fatalf("list --field failed: %v", err)
+	}
+	var tasks []asana.Task
+	decodeEnvelope(t, out, &tasks)
+	if len(tasks) != 1 || tasks[0].Name != "Unestimated" {
+		t.Errorf("expected only the unestimated task, got %+v", tasks)
+	}
+}
+
+func TestAttachmentCommands(t *testing.T) {
+	srv := newTestServer(t)
+	task := srv.AddTask("", asana.Task{Name: "Bug report"})
+	png := append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 64)...)
+	srv.AddAttachment(task.GID, "screenshot", png)
+
+	dir := t.TempDir()
+	logFile := dir + "/app.log"
+	os.WriteFile(logFile, []byte("line 1\nline 2\n"), 0o644)
+
+	out, err := runCLI(t, "attach", task.GID, logFile, "--json")
+	if err != nil {
+		t.Fatalf("attach failed: %v", err)
+	}
+	var attached []asana.Attachment
+	decodeEnvelope(t, out, &attached)
+	if len(attached) != 1 || attached[0].Name != "app.log" {
+		t.Fatalf("unexpected attach output: %+v", attached)
+	}
+	if _, err := runCLI(t, "attach", task.GID, dir+"/missing.txt"); err == nil {
+		t.Error("expected attaching a missing file to fail")
+	}
+
+	out, err = runCLI(t, "attachments", task.GID)
+	if err != nil {
+		t.Fatalf("attachments failed: %v", err)
+	}
+	if !strings.Contains(out, "screenshot") || !strings.Contains(out, "app.log") {
+		t.Errorf("unexpected attachments output:\n%s", out)
+	}
+
+	downloads := t.TempDir()
+	out, err = runCLI(t, "attachments", "download", task.GID, "--dir", downloads, "--json")
+	if err != nil {
+		t.Fatalf("attachments download failed: %v\n%s", err, out)
+	}
+	var saved []downloadedFile
+	decodeEnvelope(t, out, &saved)
+	if len(saved) != 2 || saved[0].ContentType != "image/png" || !strings.HasSuffix(saved[0].Path, "screenshot.png") {
+		t.Errorf("unexpected downloads: %+v", saved)
+	}
+	if data, _ := os.ReadFile(downloads + "/app.log"); string(data) != "line 1\nline 2\n" {
+		t.Errorf("unexpected downloaded contents %q", data)
+	}
+
+	if _, err := runCLI(t, "attachments", "download", task.GID, "app.log", "--dir", downloads); err == nil {
+		t.Error("expected existing file to be kept without --force")
+	}
+	if _, err := runCLI(t, "attachments", "download", task.GID, "app.log", "--dir", downloads, "--force"); err != nil {
+		t.Errorf("download --force failed: %v", err)
+	}
+
+	out, err = runCLI(t, "attachments", "download", task.GID, "screenshot", "--dir", t.TempDir(), "--max-size", "10B")
+	if !errors.Is(err, asana.ErrAttachmentTooLarge) {
+		t.Errorf("expected size limit error, got %v:\n%s", err, out)
+	}
+}
+
+func TestSectionCommands(t *testing.T) {
+	srv := newTestServer(t)
+	ws := srv.AddWorkspace("Acme")
+	proj := srv.AddProject(ws.GID, "Roadmap")
+	todo := srv.AddSection(proj.GID, "To do")
+	done := srv.AddSection(proj.GID, "Done")
+	task := srv.AddTask(proj.GID, asana.Task{Name: "Write docs"})
+	srv.MoveToSection(task.GID, todo.GID)
+
+	// Without a project the error still comes as an envelope
+	out, err := runCLI(t, "section", "list", "--json")
+	if err == nil || !strings.Contains(out, `"success": false`) {
+		t.Errorf("expected an error envelope, got %v:\n%s", err, out)
+	}
+
+	if _, err := runCLI(t, "section", "create", "In progress", "--project", proj.GID, "--after", "to do"); err != nil {
+		t.Fatalf("section create failed: %v", err)
+	}
+	out, err = runCLI(t, "section", "list", proj.GID)
+	if err != nil {
+		t.Fatalf("section list failed: %v", err)
+	}
+	if strings.Index(out, "To do") > strings.Index(out, "In progress") || strings.Index(out, "In progress") > strings.Index(out, "Done") {
+		t.Errorf("sections out of order:\n%s", out)
+	}
+
+	if _, err := runCLI(t, "move", task.GID, "--section", "In Progress", "--project", proj.GID); err != nil {
+		t.Fatalf("move failed: %v", err)
+	}
+	out, err = runCLI(t, "view", task.GID)
+	if err != nil {
+		t.Fatalf("view failed: %v", err)
+	}
+	if !strings.Contains(out, "Section: In progress (Roadmap)") {
+		t.Errorf("view output missing section:\n%s", out)
+	}
+
+	// Without --project, names are looked up in the task's project
+	if _, err := runCLI(t, "move", task.GID, "--section", "done"); err != nil {
+		t.Fatalf("move without project failed: %v", err)
+	}
+	if tasks := srv.SectionTasks(done.GID); len(tasks) != 1 || tasks[0] != task.GID {
+		t.Errorf("task not moved to Done: %v", tasks)
+	}
+
+	if _, err := runCLI(t, "section", "reorder", "Done", "--before", "To do", "--project", proj.GID); err != nil {
+		t.Fatalf("section reorder failed: %v", err)
+	}
+	if _, err := runCLI(t, "section", "rename", todo.GID, "Backlog", "--project", proj.GID); err != nil {
+		t.Fatalf("section rename failed: %v", err)
+	}
+	if _, err := runCLI(t, "section", "delete", "in progress", "--project", proj.GID); err != nil {
+		t.Fatalf("section delete failed: %v", err)
+	}
+	names := make([]string, 0)
+	for _, sec := range srv.Sections(proj.GID) {
+		names = append(names, sec.Name)
+	}
+	if got := strings.Join(names, ","); got != "Done,Backlog" {
+		t.Errorf("sections = %s", got)
+	}
+
+	out, err = runCLI(t, "move", task.GID, "--section", "Nowhere", "--project", proj.GID)
+	if err == nil || !strings.Contains(out, `no section "Nowhere"`) {
+		t.Errorf("expected unknown section error, got %v:\n%s", err, out)
+	}
+
+	out, err = runCLI(t, "create", proj.GID, "--name", "Release", "--section", "backlog")
+	if err != nil {
+		t.Fatalf("create --section failed: %v\n%s", err, out)
+	}
+	if tasks := srv.SectionTasks(todo.GID); len(tasks) != 1 {
+		t.Errorf("created task not placed in Backlog: %v", tasks)
+	}
+}
+
+func TestProjectCommands(t *testing.T) {
+	srv := newTestServer(t)
+	ws := srv.AddWorkspace("Acme")
+	srv.AddTeam(ws.GID, "Engineering")
+	jobPollInterval = time.Millisecond
+
+	out, err := runCLI(t, "project", "create", "Launch", "--team", "engineering", "--color", "dark-green", "--view", "board", "--due", "2026-12-01", "--private", "--json")
+	if err != nil {
+		t.Fatalf("project create failed: %v\n%s", err, out)
+	}
+	var created asana.Project
+	decodeEnvelope(t, out, &created)
+	if created.Team == nil || created.Team.Name != "Engineering" || created.Color != "dark-green" || created.Public {
+		t.Errorf("unexpected project: %+v", created)
+	}
+
+	out, err = runCLI(t, "project", "create", "Bad", "--view", "gantt")
+	if err == nil || !strings.Contains(out, "invalid view") {
+		t.Errorf("expected invalid view error, got %v:\n%s", err, out)
+	}
+
+	if _, err := runCLI(t, "project", "update", created.GID, "--name", "Launch 2026", "--public"); err != nil {
+		t.Fatalf("project update failed: %v", err)
+	}
+	if p, _ := srv.Project(created.GID); p.Name != "Launch 2026" || !p.Public {
+		t.Errorf("project not updated: %+v", p)
+	}
+
+	out, err = runCLI(t, "project", "duplicate", created.GID)
+	if err != nil {
+		t.Fatalf("project duplicate failed: %v", err)
+	}
+	if !strings.Contains(out, "Project duplicated: Copy of Launch 2026") {
+		t.Errorf("unexpected duplicate output:\n%s", out)
+	}
+
+	if _, err := runCLI(t, "project", "archive", created.GID); err != nil {
+		t.Fatalf("project archive failed: %v", err)
+	}
+	out, err = runCLI(t, "project", "list")
+	if err != nil {
+		t.Fatalf("project list failed: %v", err)
+	}
+	if strings.Contains(out, created.GID) || !strings.Contains(out, "Copy of Launch 2026 (Engineering") {
+		t.Errorf("unexpected active projects:\n%s", out)
+	}
+	out, _ = runCLI(t, "project", "list", "--archived")
+	if !strings.Contains(out, "Launch 2026 (Engineering, due 2026-12-01, archived)") {
+		t.Errorf("archived project not listed:\n%s", out)
+	}
+	if _, err := runCLI(t, "project", "unarchive", created.GID); err != nil {
+		t.Fatalf("project unarchive failed: %v", err)
+	}
+
+	// Pick the second project interactively, after one bad answer
+	rootCmd.SetIn(strings.NewReader("7\n2\n"))
+	defer rootCmd.SetIn(nil)
+	out, err = runCLI(t, "config", "project", "add")
+	if err != nil {
+		t.Fatalf("config project add failed: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "Please enter a number") || !strings.Contains(out, "✓ Project added: Copy of Launch 2026") {
+		t.Errorf("unexpected pick output:\n%s", out)
+	}
+	out, _ = runCLI(t, "config", "get", "--json")
+	if !strings.Contains(out, `"workspace_id": "`+ws.GID+`"`) {
+		t.Errorf("picked project saved without its workspace:\n%s", out)
+	}
+
+	if _, err := runCLI(t, "project", "delete", created.GID); err != nil {
+		t.Fatalf("project delete failed: %v", err)
+	}
+	if _, ok := srv.Project(created.GID); ok {
+		t.Error("project not deleted")
+	}
+
+	// An all-digit team name is a name first, a GID only when nothing matches
+	year := srv.AddTeam(ws.GID, "2024")
+	out, err = runCLI(t, "project", "create", "Budget", "--team", "2024", "--json")
+	if err != nil {
+		t.Fatalf("project create with a numeric team name failed: %v\n%s", err, out)
+	}
+	var budget asana.Project
+	decodeEnvelope(t, out, &budget)
+	if budget.Team == nil || budget.Team.GID != year.GID {
+		t.Errorf("numeric team name resolved to %+v", budget.Team)
+	}
+}
+
+func TestTagCommands(t *testing.T) {
+	srv := newTestServer(t)
+	ws := srv.AddWorkspace("Acme")
+	proj := srv.AddProject(ws.GID, "Roadmap")
+	login := srv.AddTask(proj.GID, asana.Task{Name: "Fix login"})
+	docs := srv.AddTask(proj.GID, asana.Task{Name: "Write docs"})
+	srv.AddTask(proj.GID, asana.Task{Name: "Plan offsite"})
+	bug := srv.AddTag(ws.GID, "bug")
+
+	out, err := runCLI(t, "tag", "add", login.GID, "Bug", "urgent")
+	if err == nil || !strings.Contains(out, `no tag "urgent"`) {
+		t.Errorf("expected unknown tag error, got %v:\n%s", err, out)
+	}
+	out, err = runCLI(t, "tag", "add", login.GID, "Bug", "urgent", "--create")
+	if err != nil {
+		t.Fatalf("tag add --create failed: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "Tag created: urgent") || !strings.Contains(out, "Tagged task "+login.GID+" with bug, urgent") {
+		t.Errorf("unexpected tag add output:\n%s", out)
+	}
+	if _, err := runCLI(t, "tag", "add", docs.GID, bug.GID); err != nil {
+		t.Fatalf("tag add by GID failed: %v", err)
+	}
+
+	// All-digit names are tag names first, GIDs only when nothing matches
+	year := srv.AddTag(ws.GID, "2025")
+	if out, err := runCLI(t, "tag", "add", docs.GID, "2025", "42", "--create"); err != nil {
+		t.Fatalf("tag add with numeric names failed: %v\n%s", err, out)
+	}
+	if got, _ := srv.Task(docs.GID); len(got.Tags) != 3 || got.Tags[1].GID != year.GID || got.Tags[2].Name != "42" {
+		t.Errorf("numeric tag names resolved to %+v", got.Tags)
+	}
+	if _, err := runCLI(t, "tag", "remove", docs.GID, "2025", "42"); err != nil {
+		t.Fatalf("tag remove by numeric name failed: %v", err)
+	}
+
+	var tasks []asana.Task
+	out, _ = runCLI(t, "list", proj.GID, "--tag", "bug", "--tag", "URGENT", "--json")
+	meta := decodeEnvelope(t, out, &tasks)
+	if len(tasks) != 1 || tasks[0].GID != login.GID || meta["filter_tag_mode"] != "all" {
+		t.Errorf("--tag all matched %+v (meta %v)", tasks, meta)
+	}
+	out, _ = runCLI(t, "list", proj.GID, "--tag", "bug", "--tag", "urgent", "--tag-mode", "any", "--fields", "name", "--json")
+	decodeEnvelope(t, out, &tasks)
+	if len(tasks) != 2 {
+		t.Errorf("--tag-mode any matched %+v", tasks)
+	}
+
+	if _, err := runCLI(t, "tag", "remove", login.GID, "urgent"); err != nil {
+		t.Fatalf("tag remove failed: %v", err)
+	}
+	if _, err := runCLI(t, "tag", "rename", "bug", "defect"); err != nil {
+		t.Fatalf("tag rename failed: %v", err)
+	}
+	if got, _ := srv.Task(login.GID); len(got.Tags) != 1 || got.Tags[0].Name != "defect" {
+		t.Errorf("unexpected tags after remove/rename: %+v", got.Tags)
+	}
+	if _, err := runCLI(t, "tag", "delete", "urgent"); err != nil {
+		t.Fatalf("tag delete failed: %v", err)
+	}
+	out, err = runCLI(t, "tag", "list")
+	if err != nil {
+		t.Fatalf("tag list failed: %v", err)
+	}
+	if !strings.Contains(out, "defect") || strings.Contains(out, "urgent") {
+		t.Errorf("unexpected tag list:\n%s", out)
+	}
+}
+
+func TestAdvancedSearch(t *testing.T) {
+	srv := newTestServer(t)
+	ws := srv.AddWorkspace("Acme")
+	proj := srv.AddProject(ws.GID, "Roadmap")
+	other := srv.AddProject(ws.GID, "Ops")
+	year := srv.AddProject(ws.GID, "2024")
+	doing := srv.AddSection(proj.GID, "Doing")
+	points := srv.AddCustomField(proj.GID, asana.CustomField{Name: "Points", Type: asana.CustomFieldNumber})
+	bug := srv.AddTag(ws.GID, "bug")
+	me := srv.Me()
+
+	due := func(days int) *asana.CustomTime {
+		return &asana.CustomTime{Time: time.Now().AddDate(0, 0, days)}
+	}
+	login := srv.AddTask(proj.GID, asana.Task{Name: "Fix login", Assignee: &asana.User{GID: me.GID, Name: me.Name}, DueDate: due(2)})
+	docs := srv.AddTask(proj.GID, asana.Task{Name: "Write docs", DueDate: due(30), Completed: true})
+	deploy := srv.AddTask(other.GID, asana.Task{Name: "Deploy login service", DueDate: due(1)})
+	srv.AddSubtask(login.GID, asana.Task{Name: "Reproduce login issue"})
+	srv.AddTask(year.GID, asana.Task{Name: "Close books"})
+	srv.TagTask(login.GID, bug.GID)
+	srv.TagTask(deploy.GID, bug.GID)
+	srv.MoveToSection(docs.GID, doing.GID)
+	srv.AddAttachment(deploy.GID, "log.txt", []byte("boom"))
+	if _, err := srv.Client().UpdateTask(context.Background(), login.GID, &asana.TaskUpdateRequest{
+		CustomFields: map[string]interface{}{points.GID: 5},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	search := func(args ...string) []string {
+		t.Helper()
+		out, err := runCLI(t, append([]string{"search", "--json"}, args...)...)
+		if err != nil {
+			t.Fatalf("search %v failed: %v\n%s", args, err, out)
+		}
+		var tasks []asana.Task
+		decodeEnvelope(t, out, &tasks)
+		names := make([]string, len(tasks))
+		for i, task := range tasks {
+			names[i] = task.Name
+		}
+		return names
+	}
+
+	for _, c := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"login", "--subtask=false"}, "Deploy login service,Fix login"},
+		{[]string{"--assignee", "me"}, "Fix login"},
+		{[]string{"--projects", "roadmap", "--completed=false", "--subtask=false"}, "Fix login"},
+		{[]string{"--sections", "Doing", "--projects", proj.GID}, "Write docs"},
+		{[]string{"--tag", "bug", "--has-attachment"}, "Deploy login service"},
+		{[]string{"--due-before", "+7d", "--sort-by", "due_date", "--ascending"}, "Deploy login service,Fix login"},
+		{[]string{"--due-after", "today", "--due-before", "+3d", "--tag", "bug", "--projects", "Roadmap,Ops"}, "Deploy login service,Fix login"},
+		{[]string{"--field", "Points>3"}, "Fix login"},
+		{[]string{"--field", "Points=", "--projects", "Roadmap", "--subtask=false"}, "Write docs"},
+		{[]string{ws.GID, "docs"}, "Write docs"},
+		{[]string{"--projects", "2024"}, "Close books"},
+	} {
+		got := search(c.args...)
+		sorted := append([]string(nil), got...)
+		if !strings.Contains(strings.Join(c.args, " "), "--sort-by") {
+			sort.Strings(sorted)
+		}
+		if strings.Join(sorted, ",") != c.want {
+			t.Errorf("search %v = %v, want %s", c.args, got, c.want)
+		}
+	}
+
+	out, err := runCLI(t, "search", "--json", "--assignee", "me", "--due-before", "2026-13-01")
+	if err == nil || !strings.Contains(out, "--due-before: invalid date") {
+		t.Errorf("expected a bad date to be rejected, got %v:\n%s", err, out)
+	}
+
+	out, _ = runCLI(t, "search", "--json", "--tag", "bug", "--completed=false")
+	meta := decodeEnvelope(t, out, nil)
+	filters, _ := meta["filters"].(map[string]interface{})
+	if filters["tags.all"] != bug.GID || filters["completed"] != "false" || meta["workspace_id"] != ws.GID {
+		t.Errorf("unexpected search meta: %v", meta)
+	}
+}
+
+func TestWhere(t *testing.T) {
+	srv := newTestServer(t)
+	ws := srv.AddWorkspace("Acme")
+	proj := srv.AddProject(ws.GID, "Roadmap")
+	backend := srv.AddTag(ws.GID, "backend")
+	infra := srv.AddTag(ws.GID, "infra")
+	me := srv.Me()
+
+	yesterday := &asana.CustomTime{Time: time.Now().AddDate(0, 0, -1)}
+	overdue := srv.AddTask(proj.GID, asana.Task{Name: "Fix login", Assignee: &asana.User{GID: me.GID, Name: me.Name}, DueDate: yesterday})
+	orphan := srv.AddTask(proj.GID, asana.Task{Name: "Rotate keys", DueDate: yesterday})
+	srv.AddTask(proj.GID, asana.Task{Name: "Old work", DueDate: yesterday, Completed: true})
+	srv.TagTask(overdue.GID, backend.GID)
+	srv.TagTask(orphan.GID, infra.GID)
+
+	names := func(out string) string {
+		t.Helper()
+		var tasks []asana.Task
+		decodeEnvelope(t, out, &tasks)
+		var names []string
+		for _, task := range tasks {
+			names = append(names, task.Name)
+		}
+		sort.Strings(names)
+		return strings.Join(names, ",")
+	}
+
+	where := "due < today and assignee = me and not completed and tag in (backend, infra)"
+	out, err := runCLI(t, "list", proj.GID, "--json", "--where", where)
+	if err != nil {
+		t.Fatalf("list --where failed: %v\n%s", err, out)
+	}
+	if got := names(out); got != "Fix login" {
+		t.Errorf("list --where %q = %s", where, got)
+	}
+	if meta := decodeEnvelope(t, out, nil); meta["filter_where"] != where {
+		t.Errorf("meta filter_where = %v", meta["filter_where"])
+	}
+
+	// The fields the expression needs are requested even when --fields
+	// narrows the output
+	out, _ = runCLI(t, "list", proj.GID, "--json", "--fields", "name", "--where", "not assignee and tag = infra")
+	if got := names(out); got != "Rotate keys" {
+		t.Errorf("list --fields name --where = %s", got)
+	}
+
+	out, _ = runCLI(t, "search", "--json", "--where", "overdue")
+	if got := names(out); got != "Fix login,Rotate keys" {
+		t.Errorf("search --where overdue = %s", got)
+	}
+
+	out, err = runCLI(t, "list", proj.GID, "--where", "due < tomorow")
+	if err == nil || !strings.Contains(out, `invalid --where expression at column 7: invalid date "tomorow"`) || !strings.Contains(out, "\n        ^^^^^^^") {
+		t.Errorf("expected a parse error pointing at the date, got %v:\n%s", err, out)
+	}
+
+	// --offline reads the sync daemon's cache
+	out, err = runCLI(t, "list", proj.GID, "--offline", "--json")
+	if err == nil || !strings.Contains(out, "cache not found") {
+		t.Errorf("expected a missing cache error, got %v:\n%s", err, out)
+	}
+	tasks, err := srv.Client().GetTasks(context.Background(), proj.GID, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cacheDir := filepath.Join(os.Getenv("HOME"), ".asana-cache")
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	syncedAt := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	cache, _ := json.Marshal(syncdaemon.CacheFile{
+		Metadata: syncdaemon.CacheMetadata{ProjectID: proj.GID, SyncedAt: syncedAt, TaskCount: len(tasks)},
+		Tasks:    tasks,
+	})
+	if err := os.WriteFile(filepath.Join(cacheDir, "project-"+proj.GID+".json"), cache, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	srv.ResetRequests()
+	out, err = runCLI(t, "list", proj.GID, "--offline", "--json", "--where", "completed or tag = backend")
+	if err != nil {
+		t.Fatalf("list --offline failed: %v\n%s", err, out)
+	}
+	if reqs := srv.Requests(); len(reqs) > 0 {
+		t.Errorf("list --offline called the API: %v", reqs)
+	}
+	if got := names(out); got != "Fix login,Old work" {
+		t.Errorf("list --offline --where = %s", got)
+	}
+	meta := decodeEnvelope(t, out, nil)
+	if meta["source"] != "cache" || meta["synced_at"] != syncedAt.Format(time.RFC3339) {
+		t.Errorf("unexpected offline meta: %v", meta)
+	}
+
+	out, err = runCLI(t, "list", proj.GID, "--offline", "--assignee", "me")
+	if err == nil || !strings.Contains(out, "use --where with --offline") {
+		t.Errorf("expected --assignee to be rejected offline, got %v:\n%s", err, out)
+	}
+}
+
+func TestOutputFormats(t *testing.T) {
+	srv := newTestServer(t)
+	ws := srv.AddWorkspace("Acme")
+	proj := srv.AddProject(ws.GID, "Roadmap")
+	me := srv.Me()
+	due := &asana.CustomTime{Time: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)}
+	fix := srv.AddTask(proj.GID, asana.Task{Name: "Fix login, again", DueDate: due, Assignee: &asana.User{GID: me.GID, Name: me.Name}})
+	docs := srv.AddTask(proj.GID, asana.Task{Name: "Write | docs"})
+	bug := srv.AddTag(ws.GID, "bug")
+	srv.TagTask(fix.GID, bug.GID)
+
+	run := func(args ...string) string {
+		t.Helper()
+		out, err := runCLI(t, args...)
+		if err != nil {
+			t.Fatalf("%v failed: %v\n%s", args, err, out)
+		}
+		return out
+	}
+
+	out := run("list", proj.GID, "--output", "table")
+	wantTable := fmt.Sprintf("GID%sNAME              DUE         ASSIGNEE%sCOMPLETED\n", strings.Repeat(" ", len(fix.GID)-1), strings.Repeat(" ", len(me.Name)-6))
+	if !strings.HasPrefix(out, wantTable) || !strings.Contains(out, "2026-03-01") {
+		t.Errorf("unexpected table:\n%s\nwant header:\n%s", out, wantTable)
+	}
+
+	out = run("list", proj.GID, "-o", "csv", "--columns", "name,due,tags")
+	wantCSV := "name,due,tags\n\"Fix login, again\",2026-03-01,bug\nWrite | docs,,\n"
+	if out != wantCSV {
+		t.Errorf("csv =\n%s\nwant\n%s", out, wantCSV)
+	}
+
+	out = run("list", proj.GID, "-o", "tsv", "--columns", "gid,name")
+	if want := fmt.Sprintf("gid\tname\n%s\tFix login, again\n%s\tWrite | docs\n", fix.GID, docs.GID); out != want {
+		t.Errorf("tsv =\n%s\nwant\n%s", out, want)
+	}
+
+	out = run("list", proj.GID, "-o", "markdown", "--columns", "name,assignee")
+	if want := fmt.Sprintf("| name | assignee |\n| --- | --- |\n| Fix login, again | %s |\n| Write \\| docs |  |\n", me.Name); out != want {
+		t.Errorf("markdown =\n%s\nwant\n%s", out, want)
+	}
+
+	out = run("list", proj.GID, "-o", "ndjson", "--columns", "gid,assignee.gid")
+	if want := fmt.Sprintf("{\"gid\":%q,\"assignee.gid\":%q}\n{\"gid\":%q,\"assignee.gid\":null}\n", fix.GID, me.GID, docs.GID); out != want {
+		t.Errorf("ndjson =\n%s\nwant\n%s", out, want)
+	}
+
+	out = run("list", proj.GID, "-o", "yaml", "--columns", "name,due,tags")
+	if want := "- name: Fix login, again\n  due: \"2026-03-01T00:00:00Z\"\n  tags:\n    - bug\n- name: Write | docs\n  due: null\n  tags: null\n"; out != want {
+		t.Errorf("yaml =\n%s\nwant\n%s", out, want)
+	}
+
+	// --columns with --json (or --output json) keeps the envelope and meta
+	out = run("search", "--json", "--columns", "name")
+	var rows []map[string]interface{}
+	meta := decodeEnvelope(t, out, &rows)
+	if len(rows) != 2 || len(rows[0]) != 1 || meta["count"] != float64(2) {
+		t.Errorf("unexpected --json --columns output: %v %v", rows, meta)
+	}
+
+	out = run("view", fix.GID, "-o", "table")
+	if !strings.Contains(out, "NAME       Fix login, again\n") || !strings.Contains(out, "TAGS       bug\n") {
+		t.Errorf("unexpected view table:\n%s", out)
+	}
+
+	out = run("me", "-o", "yaml")
+	if want := fmt.Sprintf("gid: %q\n", me.GID); !strings.HasPrefix(out, want) || !strings.Contains(out, "name: "+me.Name) {
+		t.Errorf("unexpected me yaml:\n%s", out)
+	}
+
+	run("config", "project", "add", "roadmap", proj.GID)
+	out = run("config", "project", "list", "-o", "csv")
+	if want := fmt.Sprintf("current,name,project_id,description\ntrue,roadmap,%s,\n", proj.GID); out != want {
+		t.Errorf("config project list csv =\n%s\nwant\n%s", out, want)
+	}
+
+	for _, c := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"list", proj.GID, "-o", "xml"}, `invalid --output "xml"`},
+		{[]string{"list", proj.GID, "--columns", "nmae"}, `unknown column "nmae"`},
+		{[]string{"complete", fix.GID, "-o", "csv"}, "only supports --json output"},
+	} {
+		out, err := runCLI(t, c.args...)
+		if err == nil || !strings.Contains(out, c.want) {
+			t.Errorf("%v: expected error %q, got %v:\n%s", c.args, c.want, err, out)
+		}
+	}
+}
+
+func TestFormatTemplates(t *testing.T) {
+	srv := newTestServer(t)
+	ws := srv.AddWorkspace("Acme")
+	proj := srv.AddProject(ws.GID, "Roadmap")
+	me := srv.Me()
+	due := &asana.CustomTime{Time: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)}
+	fix := srv.AddTask(proj.GID, asana.Task{Name: "Fix the login page", DueDate: due, Assignee: &asana.User{GID: me.GID, Name: me.Name}})
+	srv.AddTask(proj.GID, asana.Task{Name: "Write docs"})
+	bug := srv.AddTag(ws.GID, "bug")
+	urgent := srv.AddTag(ws.GID, "urgent")
+	srv.TagTask(fix.GID, bug.GID)
+	srv.TagTask(fix.GID, urgent.GID)
+
+	run := func(args ...string) string {
+		t.Helper()
+		out, err := runCLI(t, args...)
+		if err != nil {
+			t.Fatalf("%v failed: %v\n%s", args, err, out)
+		}
+		return out
+	}
+
+	out := run("list", proj.GID, "--format", `{{.Name}} ({{.DueDate | date "Jan 2"}})`)
+	if want := "Fix the login page (Mar 1)\nWrite docs ()\n"; out != want {
+		t.Errorf("list --format =\n%s\nwant\n%s", out, want)
+	}
+
+	out = run("list", proj.GID, "--format", `{{.Name | truncate 8}}|{{.Assignee | default "-"}}|{{.Tags | join ","}}`)
+	if want := fmt.Sprintf("Fix the…|%s|bug,urgent\nWrite d…|-|\n", me.Name); out != want {
+		t.Errorf("helpers =\n%s\nwant\n%s", out, want)
+	}
+
+	// --fields only trims the request, templates still see tasks
+	out = run("view", fix.GID, "--fields", "name", "--format", "{{.GID}} {{.Name}}")
+	if want := fmt.Sprintf("%s Fix the login page\n", fix.GID); out != want {
+		t.Errorf("view --format = %q, want %q", out, want)
+	}
+
+	out = run("me", "--format", "{{.Name}} <{{.Email}}>")
+	if want := fmt.Sprintf("%s <%s>\n", me.Name, me.Email); out != want {
+		t.Errorf("me --format = %q, want %q", out, want)
+	}
+
+	out = run("project", "list", "--workspace", ws.GID, "--format", "{{.GID}}:{{.Name}}")
+	if want := fmt.Sprintf("%s:Roadmap\n", proj.GID); out != want {
+		t.Errorf("project list --format = %q, want %q", out, want)
+	}
+
+	run("config", "template", "set", "prompt", "{{.Name | upper}}")
+	out = run("config", "template", "list")
+	if !strings.Contains(out, "prompt\n    {{.Name | upper}}") {
+		t.Errorf("unexpected template list:\n%s", out)
+	}
+	out = run("list", proj.GID, "--format", "prompt")
+	if out != "FIX THE LOGIN PAGE\nWRITE DOCS\n" {
+		t.Errorf("saved template output =\n%s", out)
+	}
+	run("config", "template", "remove", "prompt")
+
+	for _, c := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"list", proj.GID, "--format", "prompt"}, `no saved template "prompt"`},
+		{[]string{"list", proj.GID, "--format", "{{.Name"}, "invalid --format template"},
+		{[]string{"list", proj.GID, "--format", "{{.Nmae}}"}, "can't evaluate field Nmae"},
+		{[]string{"list", proj.GID, "--format", "{{.Name}}", "--json"}, "--format cannot be combined"},
+		{[]string{"complete", fix.GID, "--format", "{{.Name}}"}, "does not support --format"},
+		{[]string{"config", "template", "set", "bad", "{{end}}"}, "invalid template"},
+	} {
+		out, err := runCLI(t, c.args...)
+		if err == nil || !strings.Contains(out, c.want) {
+			t.Errorf("%v: expected error %q, got %v:\n%s", c.args, c.want, err, out)
+		}
+	}
+}
+
+func TestNonInteractiveOutput(t *testing.T) {
+	srv := newTestServer(t)
+	ws := srv.AddWorkspace("Acme")
+	proj := srv.AddProject(ws.GID, "Roadmap")
+	task := srv.AddTask(proj.GID, asana.Task{Name: "Write docs"})
+	empty := srv.AddProject(ws.GID, "Empty")
+
+	// Tests run with stdout on a pipe, so the TUI gives way to a table
+	for _, args := range [][]string{
+		{"list", proj.GID},
+		{"list", proj.GID, "--no-tui"},
+		{"search", "--workspace", ws.GID},
+	} {
+		out, err := runCLI(t, args...)
+		if err != nil {
+			t.Fatalf("%v failed: %v\n%s", args, err, out)
+		}
+		if !strings.HasPrefix(out, "GID") || !strings.Contains(out, task.GID+"  Write docs") {
+			t.Errorf("%v: expected a table, got:\n%s", args, out)
+		}
+	}
+
+	out, err := runCLI(t, "list", empty.GID)
+	if err != nil || out != "No tasks\n" {
+		t.Errorf("empty list = %q, %v", out, err)
+	}
+
+	for _, c := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"list", proj.GID, "--interactive", "--json"}, "--interactive cannot be combined"},
+		{[]string{"list", proj.GID, "--interactive", "--no-tui"}, "none of the others can be"},
+	} {
+		out, err := runCLI(t, c.args...)
+		if err == nil || !strings.Contains(out+err.Error(), c.want) {
+			t.Errorf("%v: expected error %q, got %v:\n%s", c.args, c.want, err, out)
+		}
+	}
+}
+
+func TestNDJSONStreaming(t *testing.T) {
+	srv := newTestServer(t)
+	ws := srv.AddWorkspace("Acme")
+	proj := srv.AddProject(ws.GID, "Roadmap")
+	var gids []string
+	for i := 0; i < 150; i++ {
+		task := srv.AddTask(proj.GID, asana.Task{Name: fmt.Sprintf("Task %03d", i), Completed: i%2 == 1})
+		gids = append(gids, task.GID)
+	}
+
+	lines := func(args ...string) []string {
+		t.Helper()
+		out, err := runCLI(t, args...)
+		if err != nil {
+			t.Fatalf("%v failed: %v\n%s", args, err, out)
+		}
+		return strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	}
+
+	// Two pages of tasks come out as one record per line
+	got := lines("list", proj.GID, "--ndjson")
+	if len(got) != 150 {
+		t.Fatalf("expected 150 lines, got %d", len(got))
+	}
+	var task asana.Task
+	if err := json.Unmarshal([]byte(got[149]), &task); err != nil || task.GID != gids[149] {
+		t.Errorf("unexpected last record %s: %v", got[149], err)
+	}
+
+	got = lines("list", proj.GID, "--ndjson", "--where", "not completed", "--columns", "gid,name")
+	if len(got) != 75 || got[0] != fmt.Sprintf(`{"gid":%q,"name":"Task 000"}`, gids[0]) {
+		t.Errorf("unexpected filtered stream (%d lines): %s", len(got), got[0])
+	}
+
+	got = lines("list", proj.GID, "-o", "ndjson", "--fields", "name", "--limit", "3")
+	if len(got) != 3 || got[2] != fmt.Sprintf(`{"gid":%q,"name":"Task 002"}`, gids[2]) {
+		t.Errorf("unexpected --fields stream: %v", got)
+	}
+
+	got = lines("search", "--workspace", ws.GID, "--ndjson", "--columns", "name", "--where", `name ~ "149"`)
+	if len(got) != 1 || got[0] != `{"name":"Task 149"}` {
+		t.Errorf("unexpected search stream: %v", got)
+	}
+
+	for _, args := range [][]string{
+		{"list", proj.GID, "--ndjson", "--json"},
+		{"list", proj.GID, "--ndjson", "-o", "csv"},
+	} {
+		out, err := runCLI(t, args...)
+		if err == nil || !strings.Contains(out, "--ndjson cannot be combined") {
+			t.Errorf("%v: expected an error, got %v:\n%s", args, err, out)
+		}
+	}
+}
+
+func TestQuery(t *testing.T) {
+	srv := newTestServer(t)
+	ws := srv.AddWorkspace("Acme")
+	proj := srv.AddProject(ws.GID, "Roadmap")
+	me := srv.Me()
+	fix := srv.AddTask(proj.GID, asana.Task{Name: "Fix login", Assignee: &asana.User{GID: me.GID, Name: me.Name}})
+	docs := srv.AddTask(proj.GID, asana.Task{Name: "Write docs", Completed: true})
+
+	run := func(args ...string) string {
+		t.Helper()
+		out, err := runCLI(t, args...)
+		if err != nil {
+			t.Fatalf("%v failed: %v\n%s", args, err, out)
+		}
+		return out
+	}
+
+	// Strings print raw, one per line, for xargs
+	out := run("list", proj.GID, "--query", ".data[] | select(.completed | not) | .gid")
+	if out != fix.GID+"\n" {
+		t.Errorf("raw gids = %q, want %q", out, fix.GID+"\n")
+	}
+
+	out = run("list", proj.GID, "-q", ".data[].gid")
+	if want := fix.GID + "\n" + docs.GID + "\n"; out != want {
+		t.Errorf("gids = %q, want %q", out, want)
+	}
+
+	out = run("list", proj.GID, "-q", ".meta.count, .meta.schema_version, .success")
+	if out != "2\n1\ntrue\n" {
+		t.Errorf("scalars = %q", out)
+	}
+
+	out = run("list", proj.GID, "-q", `[.data[] | {name, who: (.assignee.name // "nobody")}]`)
+	want := fmt.Sprintf("[\n  {\n    \"name\": \"Fix login\",\n    \"who\": %q\n  },\n  {\n    \"name\": \"Write docs\",\n    \"who\": \"nobody\"\n  }\n]\n", me.Name)
+	if out != want {
+		t.Errorf("objects =\n%s\nwant\n%s", out, want)
+	}
+
+	// The same envelope whichever printer a command uses
+	if out = run("me", "-q", ".data.gid"); out != me.GID+"\n" {
+		t.Errorf("me query = %q", out)
+	}
+	if out = run("list", proj.GID, "--columns", "name", "-q", ".data[0]"); out != "{\n  \"name\": \"Fix login\"\n}\n" {
+		t.Errorf("--columns query = %q", out)
+	}
+
+	// Commands that print documents of their own put them in the envelope
+	if out = run("schema", "tag", "-q", ".data.title, .meta.type"); out != "tag\ntag\n" {
+		t.Errorf("schema query = %q", out)
+	}
+	if out = run("graph", proj.GID, "-q", ".meta.nodes"); out != "2\n" {
+		t.Errorf("graph query = %q", out)
+	}
+
+	// Failures print the usual error envelope
+	out, err := runCLI(t, "view", "404404", "-q", ".data.name")
+	if err == nil || !strings.Contains(out, `"success": false`) {
+		t.Errorf("expected an error envelope, got %v:\n%s", err, out)
+	}
+
+	for _, c := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"list", proj.GID, "-q", ".data[] | selct(.x)"}, "invalid --query at column 11: unknown function selct\n  .data[] | selct(.x)\n            ^^^^^"},
+		{[]string{"list", proj.GID, "-q", ".data.name"}, `cannot index array with string \"name\"`},
+		{[]string{"list", proj.GID, "-q", ".data", "--ndjson"}, "cannot be combined"},
+		{[]string{"graph", proj.GID, "--syntax", "mermaid", "-q", ".data"}, "--syntax draws the graph as text"},
+	} {
+		out, err := runCLI(t, c.args...)
+		if err == nil || !strings.Contains(out, c.want) {
+			t.Errorf("%v: expected error %q, got %v:\n%s", c.args, c.want, err, out)
+		}
+	}
+}
+
+func TestBulkCommands(t *testing.T) {
+	srv := newTestServer(t)
+	ws := srv.AddWorkspace("Acme")
+	proj := srv.AddProject(ws.GID, "Roadmap")
+	fix := srv.AddTask(proj.GID, asana.Task{Name: "Fix login"})
+	docs := srv.AddTask(proj.GID, asana.Task{Name: "Write docs"})
+	ship := srv.AddTask(proj.GID, asana.Task{Name: "Ship it"})
+
+	stdin := func(s string) {
+		rootCmd.SetIn(strings.NewReader(s))
+	}
+	defer rootCmd.SetIn(nil)
+
+	// A missing task fails on its own; the rest still complete
+	out, err := runCLI(t, "complete", fix.GID, "404404", docs.GID, fix.GID, "--json")
+	if err == nil || err.Error() != "1 of 3 tasks failed" {
+		t.Errorf("expected a partial failure, got %v", err)
+	}
+	var envelope struct {
+		Success bool                   `json:"success"`
+		Data    []bulkResult           `json:"data"`
+		Meta    map[string]interface{} `json:"meta"`
+	}
+	if err := json.Unmarshal([]byte(out), &envelope); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if envelope.Success || envelope.Meta["succeeded"] != 2.0 || envelope.Meta["failed"] != 1.0 {
+		t.Errorf("unexpected envelope: %+v", envelope)
+	}
+	if len(envelope.Data) != 3 {
+		t.Fatalf("expected 3 results, got %+v", envelope.Data)
+	}
+	if r := envelope.Data[0]; r.GID != fix.GID || !r.Success || r.Name != "Fix login" {
+		t.Errorf("unexpected first result: %+v", r)
+	}
+	if r := envelope.Data[1]; r.GID != "404404" || r.Success || r.Code != "not_found" {
+		t.Errorf("unexpected failed result: %+v", r)
+	}
+	for _, gid := range []string{fix.GID, docs.GID} {
+		if stored, _ := srv.Task(gid); !stored.Completed {
+			t.Errorf("task %s not completed", gid)
+		}
+	}
+
+	// --query still works on the partial results
+	out, _ = runCLI(t, "complete", ship.GID, "404404", "-q", ".data[] | select(.success | not) | .gid")
+	if out != "404404\n" {
+		t.Errorf("failed gids = %q", out)
+	}
+
+	// GIDs and list --ndjson records from stdin
+	stdin(fmt.Sprintf("%s\n\n{\"gid\":%q,\"name\":\"Write docs\"}\n", fix.GID, docs.GID))
+	out, err = runCLI(t, "update", "--stdin", "--name", "Renamed", "--concurrency", "2")
+	if err != nil {
+		t.Fatalf("bulk update failed: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "2 updated, 0 failed") {
+		t.Errorf("unexpected summary:\n%s", out)
+	}
+	for _, gid := range []string{fix.GID, docs.GID} {
+		if stored, _ := srv.Task(gid); stored.Name != "Renamed" {
+			t.Errorf("task %s not renamed: %q", gid, stored.Name)
+		}
+	}
+
+	out, err = runCLI(t, "delete", fix.GID, docs.GID)
+	if err != nil || !strings.Contains(out, "2 deleted, 0 failed") {
+		t.Errorf("bulk delete: %v\n%s", err, out)
+	}
+	if _, ok := srv.Task(fix.GID); ok {
+		t.Error("task still exists after bulk delete")
+	}
+
+	for _, c := range []struct {
+		args  []string
+		stdin string
+		want  string
+	}{
+		{[]string{"complete"}, "", "requires at least one task GID"},
+		{[]string{"complete", "--stdin"}, "\n", "no task GIDs on standard input"},
+		{[]string{"complete", "--stdin"}, "GID  NAME\n", `stdin line 1: "GID  NAME" is not a task GID`},
+		{[]string{"complete", ship.GID, "--concurrency", "0"}, "", "--concurrency must be at least 1"},
+	} {
+		stdin(c.stdin)
+		out, err := runCLI(t, c.args...)
+		if err == nil || !strings.Contains(err.Error(), c.want) {
+			t.Errorf("%v: expected error %q, got %v:\n%s", c.args, c.want, err, out)
+		}
+	}
+}
+
+func TestBatchedBulkCommands(t *testing.T) {
+	srv := newTestServer(t)
+	ws := srv.AddWorkspace("Acme")
+	proj := srv.AddProject(ws.GID, "Roadmap")
+	srv.AddCustomField(proj.GID, asana.CustomField{Name: "Story Points", Type: asana.CustomFieldNumber})
+	done := srv.AddSection(proj.GID, "Done")
+	var gids []string
+	for i := 0; i < 12; i++ {
+		task := srv.AddTask(proj.GID, asana.Task{Name: fmt.Sprintf("Task %d", i)})
+		gids = append(gids, task.GID)
+	}
+	defer rootCmd.SetIn(nil)
+
+	// onlyBatches checks that every request went through /batch
+	onlyBatches := func(what string, want int) {
+		t.Helper()
+		reqs := srv.Requests()
+		for _, r := range reqs {
+			if r.Path != "/batch" {
+				t.Errorf("%s: unexpected %s %s outside a batch", what, r.Method, r.Path)
+			}
+		}
+		if len(reqs) != want {
+			t.Errorf("%s: expected %d batch requests, got %d", what, want, len(reqs))
+		}
+		srv.ResetRequests()
+	}
+
+	srv.ResetRequests()
+	out, err := runCLI(t, append([]string{"complete", "--json"}, gids...)...)
+	if err != nil {
+		t.Fatalf("batched complete failed: %v\n%s", err, out)
+	}
+	onlyBatches("complete", 2)
+	var results []bulkResult
+	decodeEnvelope(t, out, &results)
+	if len(results) != 12 || results[11].Name != "Task 11" || !results[11].Success {
+		t.Errorf("unexpected results: %+v", results)
+	}
+	if stored, _ := srv.Task(gids[11]); !stored.Completed {
+		t.Error("task not completed")
+	}
+
+	// Custom fields are looked up in batches too
+	rootCmd.SetIn(strings.NewReader(strings.Join(gids, "\n")))
+	if out, err := runCLI(t, "update", "--stdin", "--field", "Story Points=3"); err != nil {
+		t.Fatalf("batched update failed: %v\n%s", err, out)
+	}
+	onlyBatches("update", 4)
+	stored, _ := srv.Task(gids[5])
+	if len(stored.CustomFields) != 1 || stored.CustomFields[0].NumberValue == nil || *stored.CustomFields[0].NumberValue != 3 {
+		t.Errorf("custom field not set: %+v", stored.CustomFields)
+	}
+
+	if out, err := runCLI(t, append([]string{"move", "--section", "Done", "--project", proj.GID}, gids...)...); err != nil {
+		t.Fatalf("batched move failed: %v\n%s", err, out)
+	}
+	if n := len(srv.SectionTasks(done.GID)); n != 12 {
+		t.Errorf("expected 12 tasks in Done, got %d", n)
+	}
+	srv.ResetRequests()
+
+	// One failed action fails only its task
+	srv.Fail(asanatest.Failure{Method: "POST", Path: "/tasks/" + gids[3] + "/addTag", Status: http.StatusForbidden, Times: 1})
+	rootCmd.SetIn(strings.NewReader(strings.Join(gids, "\n")))
+	out, err = runCLI(t, "tag", "add", "--stdin", "bug", "--create", "--workspace", ws.GID)
+	if err == nil || err.Error() != "1 of 12 tasks failed" {
+		t.Errorf("expected one failure, got %v", err)
+	}
+	if !strings.Contains(out, "✓ Tag created: bug") || !strings.Contains(out, "11 tagged, 1 failed") {
+		t.Errorf("unexpected tag add output:\n%s", out)
+	}
+	if stored, _ := srv.Task(gids[0]); len(stored.Tags) != 1 {
+		t.Errorf("task not tagged: %+v", stored.Tags)
+	}
+	if stored, _ := srv.Task(gids[3]); len(stored.Tags) != 0 {
+		t.Errorf("failed task tagged: %+v", stored.Tags)
+	}
+
+	out, err = runCLI(t, "move", gids[0], gids[1], "--section", "Done", "--project", proj.GID, "--after", gids[2])
+	if err == nil || !strings.Contains(out, "only work when moving a single task") {
+		t.Errorf("expected --after to be refused, got %v:\n%s", err, out)
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	srv := newTestServer(t)
+	ws := srv.AddWorkspace("Acme")
+	proj := srv.AddProject(ws.GID, "Roadmap")
+	points := srv.AddCustomField(proj.GID, asana.CustomField{Name: "Story Points", Type: asana.CustomFieldNumber})
+	var gids []string
+	for i := 0; i < 7; i++ {
+		task := srv.AddTask(proj.GID, asana.Task{Name: fmt.Sprintf("Task %d", i)})
+		gids = append(gids, task.GID)
+	}
+
+	// writes reports the requests other than reads the server received
+	writes := func() []string {
+		var sent []string
+		for _, r := range srv.Requests() {
+			if r.Method != "GET" {
+				sent = append(sent, r.Method+" "+r.Path)
+			}
+		}
+		srv.ResetRequests()
+		return sent
+	}
+
+	out, err := runCLI(t, "complete", gids[0], "--dry-run")
+	if err != nil {
+		t.Fatalf("complete --dry-run failed: %v\n%s", err, out)
+	}
+	for _, want := range []string{"[dry-run] PUT /tasks/" + gids[0] + "?opt_fields=", `"completed": true`, "Dry run: 1 request not sent"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("dry-run output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Task completed") {
+		t.Errorf("dry run reported a change it did not make:\n%s", out)
+	}
+	if sent := writes(); len(sent) != 0 {
+		t.Errorf("dry run sent %v", sent)
+	}
+	if stored, _ := srv.Task(gids[0]); stored.Completed {
+		t.Error("dry run completed the task")
+	}
+
+	var envelope struct {
+		Success bool      `json:"success"`
+		DryRun  ui.DryRun `json:"dry_run"`
+	}
+	out, err = runCLI(t, "delete", gids[0], gids[1], "--dry-run", "--json")
+	if err != nil {
+		t.Fatalf("delete --dry-run failed: %v\n%s", err, out)
+	}
+	if err := json.Unmarshal([]byte(out), &envelope); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	var deleted []string
+	for _, r := range envelope.DryRun.Requests {
+		deleted = append(deleted, r.Method+" "+r.Endpoint)
+	}
+	sort.Strings(deleted)
+	if want := []string{"DELETE /tasks/" + gids[0], "DELETE /tasks/" + gids[1]}; !envelope.Success || strings.Join(deleted, ",") != strings.Join(want, ",") {
+		t.Errorf("planned %v, want %v", deleted, want)
+	}
+	if _, ok := srv.Task(gids[0]); !ok {
+		t.Error("dry run deleted the task")
+	}
+
+	// Batched reads still run; the batched writes are planned
+	out, err = runCLI(t, append([]string{"update", "--field", "Story Points=3", "--dry-run", "--json"}, gids...)...)
+	if err != nil {
+		t.Fatalf("batched update --dry-run failed: %v\n%s", err, out)
+	}
+	envelope.DryRun = ui.DryRun{}
+	if err := json.Unmarshal([]byte(out), &envelope); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if len(envelope.DryRun.Requests) != 1 || envelope.DryRun.Requests[0].Endpoint != "/batch" {
+		t.Fatalf("expected one planned batch, got %+v", envelope.DryRun.Requests)
+	}
+	payload, _ := json.Marshal(envelope.DryRun.Requests[0].Payload)
+	if n := strings.Count(string(payload), `"method":"put"`); n != 7 || !strings.Contains(string(payload), fmt.Sprintf(`"custom_fields":{%q:3}`, points.GID)) {
+		t.Errorf("unexpected batch payload (%d puts): %s", n, payload)
+	}
+	if sent := writes(); len(sent) != 1 || sent[0] != "POST /batch" {
+		t.Errorf("expected only the read batch to be sent, got %v", sent)
+	}
+	if stored, _ := srv.Task(gids[2]); stored.CustomFields[0].NumberValue != nil {
+		t.Error("dry run set the custom field")
+	}
+
+	// Uploads are described rather than dumped, and reads report an empty plan
+	file := filepath.Join(t.TempDir(), "notes.txt")
+	os.WriteFile(file, []byte("hello"), 0644)
+	out, err = runCLI(t, "attach", gids[0], file, "--dry-run")
+	if err != nil || !strings.Contains(out, "bytes of multipart/form-data>") {
+		t.Errorf("attach --dry-run: %v\n%s", err, out)
+	}
+	out, err = runCLI(t, "list", proj.GID, "--dry-run", "--json")
+	if err != nil || !strings.Contains(out, "\"dry_run\": {\n    \"requests\": []") {
+		t.Errorf("list --dry-run: %v\n%s", err, out)
+	}
+	if out, err = runCLI(t, "project", "duplicate", proj.GID, "--dry-run"); err != nil || strings.Contains(out, "Duplicat") {
+		t.Errorf("project duplicate --dry-run: %v\n%s", err, out)
+	}
+
+	// Commands report only what they would send, never a stand-in result
+	out, err = runCLI(t, "create", proj.GID, "--name", "Draft", "--dry-run")
+	if err != nil || strings.Contains(out, "Task created") || !strings.Contains(out, "[dry-run] POST /tasks") {
+		t.Errorf("create --dry-run: %v\n%s", err, out)
+	}
+	out, err = runCLI(t, "create", proj.GID, "--name", "Draft", "--dry-run", "--json")
+	if err != nil || !strings.Contains(out, "\"success\": true,\n  \"meta\": {\n    \"action\": \"created\"") {
+		t.Errorf("create --dry-run --json: %v\n%s", err, out)
+	}
+
+	// A failed read still ends with the summary, and shows what went wrong
+	out, err = runCLI(t, append([]string{"update", "404404", "--field", "Story Points=3", "--dry-run"}, gids...)...)
+	if err == nil || !strings.Contains(out, "✗ 404404") || !strings.Contains(out, "nothing was changed") {
+		t.Errorf("expected a failed dry run with its summary, got %v:\n%s", err, out)
+	}
+	writes() // the batched reads
+
+	// A tag that would be created is named in the requests that use it
+	out, err = runCLI(t, "tag", "add", gids[0], "newtag", "--create", "--dry-run", "--json")
+	if err != nil {
+		t.Fatalf("tag add --create --dry-run failed: %v\n%s", err, out)
+	}
+	envelope.DryRun = ui.DryRun{}
+	if err := json.Unmarshal([]byte(out), &envelope); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if planned := envelope.DryRun.Requests; len(planned) != 2 || planned[1].Endpoint != "/tasks/"+gids[0]+"/addTag" {
+		t.Fatalf("unexpected plan for tag add --create: %+v", planned)
+	}
+	if payload, _ := json.Marshal(envelope.DryRun.Requests[1].Payload); string(payload) != `{"data":{"tag":"\u003cnew tag \"newtag\"\u003e"}}` {
+		t.Errorf("addTag payload = %s", payload)
+	}
+	out, _ = runCLI(t, "tag", "add", gids[0], "newtag", "--create", "--dry-run")
+	if strings.Contains(out, "Tag created") || !strings.Contains(out, `"tag": "<new tag \"newtag\">"`) {
+		t.Errorf("unexpected tag add --create --dry-run output:\n%s", out)
+	}
+	if sent := writes(); len(sent) != 0 {
+		t.Errorf("dry run sent %v", sent)
+	}
+
+	// Without --dry-run nothing is added to the envelope
+	out, _ = runCLI(t, "complete", gids[0], "--json")
+	if strings.Contains(out, "dry_run") {
+		t.Errorf("unexpected dry_run in:\n%s", out)
+	}
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/TheCoolRobot/asana-cli/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var commentStdin bool
+
+const commentTemplate = `
+# Write your comment above. Lines starting with '#' are ignored,
+# and an empty comment aborts.
+`
+
+var commentCmd = &cobra.Command{
+	Use:   "comment [task-id] [\"text\"]",
+	Short: "Add a comment to a task",
+	Long: `Add a comment to a task.
+
+The comment text is taken from the second argument, from standard input
+with --stdin, or otherwise written in $VISUAL / $EDITOR.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		taskGID := args[0]
+
+		text, err := commentText(args)
+		if err != nil {
+			return reportError(err)
+		}
+
+		client := newClient()
+		story, err := client.AddComment(cmd.Context(), taskGID, text)
+		if err != nil {
+			return reportError(err)
+		}
+
+		if jsonOutput {
+			meta := map[string]interface{}{
+				"action":   "commented",
+				"task_gid": taskGID,
+			}
+			ui.PrintJSONWithMeta(story, meta, nil)
+		} else {
+			fmt.Printf("💬 Comment added to task %s\n", taskGID)
+		}
+
+		return nil
+	},
+}
+
+// commentText resolves the comment body from the arguments, stdin or the
+// user's editor
+func commentText(args []string) (string, error) {
+	var text string
+	switch {
+	case len(args) > 1 && commentStdin:
+		return "", errors.New("pass the comment as an argument or with --stdin, not both")
+	case len(args) > 1:
+		text = args[1]
+	case commentStdin:
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return "", err
+		}
+		text = string(data)
+	default:
+		edited, err := editText(commentTemplate)
+		if err != nil {
+			return "", err
+		}
+		text = edited
+	}
+
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "", errors.New("empty comment, nothing posted")
+	}
+	return text, nil
+}
+
+func init() {
+	commentCmd.Flags().BoolVar(&commentStdin, "stdin", false, "Read the comment text from standard input")
+}
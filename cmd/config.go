@@ -1,9 +1,14 @@
 package cmd
 
 import (
+	"bufio"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/TheCoolRobot/asana-cli/internal/asana"
 	"github.com/TheCoolRobot/asana-cli/internal/config"
 	"github.com/TheCoolRobot/asana-cli/internal/ui"
 )
@@ -90,13 +95,31 @@ var configProjectCmd = &cobra.Command{
 	Long:  "Add, remove, list, or switch between saved projects",
 }
 
-var projectAddCmd = &cobra.Command{
+var configProjectAddCmd = &cobra.Command{
 	Use:   "add [name] [project-id]",
 	Short: "Add a new project",
-	Args:  cobra.MinimumNArgs(2),
+	Long: `Save a project under a name for use with other commands.
+
+Without a project ID, the projects in the workspace are listed to pick one
+from, and the name defaults to the project's own name.`,
+	Args: cobra.MaximumNArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		name := args[0]
-		projectID := args[1]
+		var name, projectID string
+		if len(args) > 0 {
+			name = args[0]
+		}
+		if len(args) > 1 {
+			projectID = args[1]
+		} else {
+			picked, err := pickProject(cmd)
+			if err != nil {
+				return reportError(err)
+			}
+			projectID = picked.GID
+			if name == "" {
+				name = picked.Name
+			}
+		}
 
 		cfg, _ := config.Load()
 		err := cfg.AddProject(name, projectID, workspace, setDesc)
@@ -115,14 +138,16 @@ var projectAddCmd = &cobra.Command{
 		} else {
 			fmt.Printf("✓ Project added: %s\n", name)
 			fmt.Printf("  ID: %s\n", projectID)
-			fmt.Printf("  Set as current\n")
+			if cfg.CurrentProject == name {
+				fmt.Printf("  Set as current\n")
+			}
 		}
 
 		return nil
 	},
 }
 
-var projectRemoveCmd = &cobra.Command{
+var configProjectRemoveCmd = &cobra.Command{
 	Use:   "remove [name]",
 	Short: "Remove a project",
 	Args:  cobra.ExactArgs(1),
@@ -152,18 +177,35 @@ var projectRemoveCmd = &cobra.Command{
 	},
 }
 
-var projectListCmd = &cobra.Command{
-	Use:   "list",
-	Short: "List all projects",
+var configProjectListCmd = &cobra.Command{
+	Use:         "list",
+	Short:       "List all projects",
+	Annotations: map[string]string{recordOutput: "true"},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cfg, _ := config.Load()
 		projects := cfg.ListProjects()
 
+		if outputFormat != "" && outputFormat != "json" {
+			// The tabular formats mark the current project in a column
+			type projectRow struct {
+				Current bool `json:"current"`
+				config.ProjectConfig
+			}
+			rows := make([]projectRow, len(projects))
+			for i, proj := range projects {
+				rows[i] = projectRow{Current: proj.Name == cfg.CurrentProject, ProjectConfig: proj}
+			}
+			return printOutput(rows, nil, ui.ProjectConfigColumns)
+		}
+
 		if jsonOutput {
 			meta := map[string]interface{}{
 				"count":            len(projects),
 				"current_project": cfg.CurrentProject,
 			}
+			if outputFormat != "" {
+				return printOutput(projects, meta, ui.ProjectConfigColumns)
+			}
 			ui.PrintJSONWithMeta(projects, meta, nil)
 		} else {
 			if len(projects) == 0 {
@@ -189,7 +231,7 @@ var projectListCmd = &cobra.Command{
 	},
 }
 
-var projectSwitchCmd = &cobra.Command{
+var configProjectSwitchCmd = &cobra.Command{
 	Use:   "switch [name]",
 	Short: "Switch to a different project",
 	Args:  cobra.ExactArgs(1),
@@ -218,22 +260,159 @@ var projectSwitchCmd = &cobra.Command{
 	},
 }
 
+var configTemplateCmd = &cobra.Command{
+	Use:   "template",
+	Short: "Manage saved --format templates",
+	Long: `Save Go templates under a name to use with --format.
+
+  asana-cli config template set prompt '{{.Name}} ({{.DueDate | date "Jan 2"}})'
+  asana-cli list --format prompt
+
+` + ui.TemplateHelp,
+}
+
+var configTemplateSetCmd = &cobra.Command{
+	Use:   "set [name] [template]",
+	Short: "Save a template",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, text := args[0], args[1]
+		if _, err := ui.ParseTemplate(text); err != nil {
+			return reportError(fmt.Errorf("invalid template: %w", err))
+		}
+
+		cfg, _ := config.Load()
+		if err := cfg.SetTemplate(name, text); err != nil {
+			return reportError(err)
+		}
+
+		if jsonOutput {
+			meta := map[string]interface{}{"action": "saved", "template": name}
+			ui.PrintJSONWithMeta(map[string]string{"name": name, "template": text}, meta, nil)
+		} else {
+			fmt.Printf("✓ Template saved: %s\n", name)
+		}
+		return nil
+	},
+}
+
+var configTemplateRemoveCmd = &cobra.Command{
+	Use:   "remove [name]",
+	Short: "Remove a saved template",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+
+		cfg, _ := config.Load()
+		if err := cfg.RemoveTemplate(name); err != nil {
+			return reportError(err)
+		}
+
+		if jsonOutput {
+			meta := map[string]interface{}{"action": "removed", "template": name}
+			ui.PrintJSONWithMeta(map[string]string{"status": "deleted"}, meta, nil)
+		} else {
+			fmt.Printf("✓ Template removed: %s\n", name)
+		}
+		return nil
+	},
+}
+
+var configTemplateListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List saved templates",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, _ := config.Load()
+		names := make([]string, 0, len(cfg.Templates))
+		for name := range cfg.Templates {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		if jsonOutput {
+			templates := make([]map[string]string, len(names))
+			for i, name := range names {
+				templates[i] = map[string]string{"name": name, "template": cfg.Templates[name]}
+			}
+			ui.PrintJSONWithMeta(templates, map[string]interface{}{"count": len(names)}, nil)
+			return nil
+		}
+
+		if len(names) == 0 {
+			fmt.Println("No templates saved. Add one with: asana-cli config template set <name> <template>")
+			return nil
+		}
+		fmt.Println("Templates:")
+		for _, name := range names {
+			fmt.Printf("  %s\n", name)
+			fmt.Printf("    %s\n", cfg.Templates[name])
+		}
+		return nil
+	},
+}
+
 func init() {
 	configCmd.AddCommand(configGetCmd)
 	configCmd.AddCommand(configSetCmd)
 	configCmd.AddCommand(configProjectCmd)
+	configCmd.AddCommand(configTemplateCmd)
 
 	configSetCmd.Flags().StringVar(&setToken, "token", "", "API token")
 	configSetCmd.Flags().StringVar(&setWorkspace, "workspace", "", "Default workspace ID")
 	configSetCmd.Flags().StringVar(&setName, "name", "" , "Default name")
 
-	configProjectCmd.AddCommand(projectAddCmd)
-	configProjectCmd.AddCommand(projectRemoveCmd)
-	configProjectCmd.AddCommand(projectListCmd)
-	configProjectCmd.AddCommand(projectSwitchCmd)
+	configProjectCmd.AddCommand(configProjectAddCmd)
+	configProjectCmd.AddCommand(configProjectRemoveCmd)
+	configProjectCmd.AddCommand(configProjectListCmd)
+	configProjectCmd.AddCommand(configProjectSwitchCmd)
+
+	configTemplateCmd.AddCommand(configTemplateSetCmd)
+	configTemplateCmd.AddCommand(configTemplateRemoveCmd)
+	configTemplateCmd.AddCommand(configTemplateListCmd)
 
-	projectAddCmd.Flags().StringVar(&workspace, "workspace", "", "Workspace ID for this project")
-	projectAddCmd.Flags().StringVar(&setDesc, "description", "", "Project description")
+	configProjectAddCmd.Flags().StringVar(&workspace, "workspace", "", "Workspace ID for this project")
+	configProjectAddCmd.Flags().StringVar(&setDesc, "description", "", "Project description")
+}
+
+// pickProject lists the active projects in the workspace and asks which
+// one to use. The chosen project's workspace is recorded in the workspace
+// flag for saving with it.
+func pickProject(cmd *cobra.Command) (*asana.Project, error) {
+	if jsonOutput {
+		return nil, fmt.Errorf("a project ID is required with --json")
+	}
+
+	client := newClient()
+	workspaceGID, err := resolveWorkspace(cmd, client)
+	if err != nil {
+		return nil, err
+	}
+	projects, err := client.GetProjectsFiltered(cmd.Context(), workspaceGID, map[string]string{"archived": "false"})
+	if err != nil {
+		return nil, err
+	}
+	if len(projects) == 0 {
+		return nil, fmt.Errorf("no projects in workspace %s", workspaceGID)
+	}
+
+	out := cmd.OutOrStdout()
+	for i, p := range projects {
+		fmt.Fprintf(out, "%3d) %s%s\n", i+1, p.Name, projectSummary(&p))
+	}
+
+	in := bufio.NewReader(cmd.InOrStdin())
+	for {
+		fmt.Fprintf(out, "Select a project [1-%d]: ", len(projects))
+		line, err := in.ReadString('\n')
+		if n, convErr := strconv.Atoi(strings.TrimSpace(line)); convErr == nil && n >= 1 && n <= len(projects) {
+			workspace = workspaceGID
+			return &projects[n-1], nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("no project selected")
+		}
+		fmt.Fprintf(out, "Please enter a number between 1 and %d\n", len(projects))
+	}
 }
 
 func maskToken(token string) string {
@@ -16,12 +16,17 @@ var (
 	taskDueDate     string
 	taskPriority    string
 	taskSection     string
+	taskParent      string
+	taskFields      []string
 )
 
 var createCmd = &cobra.Command{
 	Use:   "create [project-id]",
 	Short: "Create a new task",
-	Args:  cobra.MinimumNArgs(1),
+	Long: `Create a new task in a project.
+
+With --parent the task is created as a subtask, and the project is optional.`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if taskName == "" {
 			if jsonOutput {
@@ -32,23 +37,58 @@ var createCmd = &cobra.Command{
 			return fmt.Errorf("task name required")
 		}
 
-		projectGID := args[0]
-		client := asana.NewClient(token)
+		if len(args) == 0 && taskParent == "" {
+			return reportError(fmt.Errorf("a project ID is required unless --parent is given"))
+		}
+
+		projectGID := ""
+		if len(args) > 0 {
+			projectGID = args[0]
+		}
+		client := newClient()
 
 		req := &asana.TaskCreateRequest{
 			Name:        taskName,
 			Description: taskDescription,
-			Projects:    []string{projectGID},
+			Parent:      taskParent,
 			Assignee:    taskAssignee,
 			DueOn:       taskDueDate,
-			Priority:    taskPriority,
+		}
+		if projectGID != "" {
+			req.Projects = []string{projectGID}
+		}
+
+		// Custom fields are set by GID, resolved from the project's fields
+		if assignments := customFieldArgs(taskFields, taskPriority); len(assignments) > 0 {
+			var defs []asana.CustomField
+			if projectGID != "" {
+				var err error
+				if defs, err = client.GetCustomFieldSettings(cmd.Context(), projectGID); err != nil {
+					return reportError(err)
+				}
+			}
+			values, err := asana.ResolveCustomFields(defs, assignments)
+			if err != nil {
+				return reportError(err)
+			}
+			req.CustomFields = values
 		}
 
+		// Sections are set through a membership, which replaces the plain
+		// project list
 		if taskSection != "" {
-			req.Section = taskSection
+			if projectGID == "" {
+				return reportError(fmt.Errorf("--section needs a project ID"))
+			}
+			sec, err := resolveSection(cmd.Context(), client, projectGID, taskSection)
+			if err != nil {
+				return reportError(err)
+			}
+			req.Projects = nil
+			req.Memberships = []asana.MembershipRequest{{Project: projectGID, Section: sec.GID}}
 		}
 
-		task, err := client.CreateTask(req)
+		task, err := client.CreateTask(cmd.Context(), req)
 		if err != nil {
 			if jsonOutput {
 				ui.PrintJSON(nil, err)
@@ -63,6 +103,9 @@ var createCmd = &cobra.Command{
 				"action":     "created",
 				"project_id": projectGID,
 			}
+			if taskParent != "" {
+				meta["parent_gid"] = taskParent
+			}
 			ui.PrintJSONWithMeta(task, meta, nil)
 		} else {
 			fmt.Printf("✓ Task created: %s\n", task.Name)
@@ -78,7 +121,9 @@ func init() {
 	createCmd.Flags().StringVar(&taskDescription, "description", "", "Task description")
 	createCmd.Flags().StringVar(&taskAssignee, "assignee", "", "Assignee user GID")
 	createCmd.Flags().StringVar(&taskDueDate, "due", "", "Due date (YYYY-MM-DD)")
-	createCmd.Flags().StringVar(&taskPriority, "priority", "", "Priority (1=high, 2=medium, 3=low)")
-	createCmd.Flags().StringVar(&taskSection, "section", "", "Section GID")
+	createCmd.Flags().StringVar(&taskPriority, "priority", "", "Priority, i.e. the project's \"Priority\" custom field (e.g. high)")
+	createCmd.Flags().StringArrayVar(&taskFields, "field", nil, "Set a custom field, e.g. \"Story Points=5\" (repeatable)")
+	createCmd.Flags().StringVar(&taskSection, "section", "", "Section name or GID within the project")
+	createCmd.Flags().StringVar(&taskParent, "parent", "", "Create the task as a subtask of this task GID")
 	if err := createCmd.MarkFlagRequired("name"); err != nil{log.Fatalf(err.Error())}
 }
\ No newline at end of file
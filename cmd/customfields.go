@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/TheCoolRobot/asana-cli/internal/asana"
+)
+
+// customFieldArgs collects --field assignments, with --priority as
+// shorthand for the conventional "Priority" custom field
+func customFieldArgs(fields []string, priority string) []string {
+	if priority != "" {
+		fields = append(fields, "Priority="+priority)
+	}
+	return fields
+}
+
+// customFieldValueFields are the custom field parts of the default task
+// fields, which --field filters need even when --fields narrows the rest
+func customFieldValueFields() []string {
+	var fields []string
+	for _, f := range asana.DefaultTaskFields {
+		if strings.HasPrefix(f, "custom_fields.") {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// filterByCustomFields keeps the tasks whose custom fields match every
+// "Name=value" filter. An empty value matches tasks where the field is
+// unset or absent.
+func filterByCustomFields(tasks []asana.Task, filters []string) ([]asana.Task, error) {
+	type match struct{ name, value string }
+	matches := make([]match, len(filters))
+	for i, f := range filters {
+		name, value, err := asana.ParseFieldAssignment(f)
+		if err != nil {
+			return nil, err
+		}
+		matches[i] = match{name, value}
+	}
+
+	kept := tasks[:0]
+	for _, t := range tasks {
+		ok := true
+		for _, m := range matches {
+			f := t.CustomField(m.name)
+			if f == nil {
+				ok = m.value == ""
+			} else {
+				ok = f.Matches(m.value)
+			}
+			if !ok {
+				break
+			}
+		}
+		if ok {
+			kept = append(kept, t)
+		}
+	}
+	return kept, nil
+}
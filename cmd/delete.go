@@ -1,22 +1,41 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/spf13/cobra"
-	"github.com/TheCoolRobot/asana-cli/internal/asana"
 	"github.com/TheCoolRobot/asana-cli/internal/ui"
 )
 
 var deleteCmd = &cobra.Command{
-	Use:   "delete [task-id]",
-	Short: "Delete a task",
-	Args:  cobra.ExactArgs(1),
+	Use:   "delete [task-id...]",
+	Short: "Delete tasks",
+	Long: `Delete one or more tasks.
+
+With several GIDs or --stdin the tasks are deleted concurrently and a
+summary of each is printed; the exit code is non-zero if any failed.`,
+	Example: `  asana-cli delete 1201 1202
+  cat stale-gids.txt | asana-cli delete --stdin --json`,
+	Args: taskGIDArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		taskID := args[0]
-		client := asana.NewClient(token)
+		gids, err := taskGIDs(cmd, args)
+		if err != nil {
+			return reportError(err)
+		}
+		client := newClient()
 
-		err := client.DeleteTask(taskID)
+		if bulkMode(args) {
+			results := runBulk(cmd.Context(), client, gids, bulkOp{
+				call: func(ctx context.Context, gid string) (string, error) {
+					return "", client.DeleteTask(ctx, gid)
+				},
+			})
+			return printBulk("deleted", results, nil)
+		}
+
+		taskID := gids[0]
+		err = client.DeleteTask(cmd.Context(), taskID)
 		if err != nil {
 			if jsonOutput {
 				ui.PrintJSON(nil, err)
@@ -38,4 +57,8 @@ var deleteCmd = &cobra.Command{
 
 		return nil
 	},
-}
\ No newline at end of file
+}
+
+func init() {
+	addBulkFlags(deleteCmd)
+}
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/TheCoolRobot/asana-cli/internal/asana"
+	"github.com/TheCoolRobot/asana-cli/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var dependOn []string
+
+var dependCmd = &cobra.Command{
+	Use:   "depend",
+	Short: "Manage task dependencies",
+	Long:  "Mark tasks as blocked by other tasks, or remove those links",
+}
+
+var dependAddCmd = &cobra.Command{
+	Use:   "add [task-id] --on [task-id]",
+	Short: "Mark a task as blocked by other tasks",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		taskGID := args[0]
+		client := newClient()
+
+		for _, on := range dependOn {
+			cycle, err := dependencyCycle(cmd.Context(), client, taskGID, on)
+			if err != nil {
+				return reportError(err)
+			}
+			if cycle != nil {
+				return reportError(fmt.Errorf("refusing to add dependency, it would create a cycle: %s", strings.Join(cycle, " → ")))
+			}
+		}
+
+		if err := client.AddDependencies(cmd.Context(), taskGID, dependOn); err != nil {
+			return reportError(err)
+		}
+
+		if jsonOutput {
+			meta := map[string]interface{}{
+				"action":   "dependencies_added",
+				"task_gid": taskGID,
+			}
+			ui.PrintJSONWithMeta(map[string]interface{}{"dependencies": dependOn}, meta, nil)
+		} else {
+			fmt.Printf("✓ Task %s is now blocked by %s\n", taskGID, strings.Join(dependOn, ", "))
+		}
+
+		return nil
+	},
+}
+
+var dependRemoveCmd = &cobra.Command{
+	Use:   "remove [task-id] --on [task-id]",
+	Short: "Remove dependencies from a task",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		taskGID := args[0]
+		client := newClient()
+
+		if err := client.RemoveDependencies(cmd.Context(), taskGID, dependOn); err != nil {
+			return reportError(err)
+		}
+
+		if jsonOutput {
+			meta := map[string]interface{}{
+				"action":   "dependencies_removed",
+				"task_gid": taskGID,
+			}
+			ui.PrintJSONWithMeta(map[string]interface{}{"dependencies": dependOn}, meta, nil)
+		} else {
+			fmt.Printf("✓ Task %s is no longer blocked by %s\n", taskGID, strings.Join(dependOn, ", "))
+		}
+
+		return nil
+	},
+}
+
+// dependencyCycle reports whether making taskGID depend on onGID would
+// close a loop, i.e. whether onGID is already (transitively) blocked by
+// taskGID. If so it returns the loop as a "blocked by" chain of GIDs
+// starting and ending at taskGID.
+func dependencyCycle(ctx context.Context, client *asana.Client, taskGID, onGID string) ([]string, error) {
+	if taskGID == onGID {
+		return []string{taskGID, taskGID}, nil
+	}
+
+	// Breadth-first over "is blocked by" edges, remembering how each task
+	// was reached so the loop can be reported
+	via := map[string]string{onGID: ""}
+	queue := []string{onGID}
+	for len(queue) > 0 {
+		gid := queue[0]
+		queue = queue[1:]
+
+		deps, err := client.GetDependencies(ctx, gid)
+		if err != nil {
+			if asana.IsNotFound(err) && gid == onGID {
+				return nil, fmt.Errorf("task %s not found", onGID)
+			}
+			return nil, err
+		}
+		for _, dep := range deps {
+			if _, seen := via[dep.GID]; seen {
+				continue
+			}
+			via[dep.GID] = gid
+			if dep.GID == taskGID {
+				var chain []string
+				for g := gid; g != ""; g = via[g] {
+					chain = append([]string{g}, chain...)
+				}
+				return append(append([]string{taskGID}, chain...), taskGID), nil
+			}
+			queue = append(queue, dep.GID)
+		}
+	}
+	return nil, nil
+}
+
+func init() {
+	for _, c := range []*cobra.Command{dependAddCmd, dependRemoveCmd} {
+		c.Flags().StringSliceVar(&dependOn, "on", nil, "GID of the blocking task (repeatable or comma-separated)")
+		c.MarkFlagRequired("on")
+	}
+
+	dependCmd.AddCommand(dependAddCmd)
+	dependCmd.AddCommand(dependRemoveCmd)
+}
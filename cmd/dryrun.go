@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/TheCoolRobot/asana-cli/internal/asana"
+	"github.com/TheCoolRobot/asana-cli/internal/ui"
+)
+
+var dryRun bool
+
+// dryRunLog holds the requests --dry-run kept from being sent
+var dryRunLog struct {
+	sync.Mutex
+	requests []asana.PlannedRequest
+	// started is set once the command got as far as setupDryRun
+	started bool
+}
+
+// setupDryRun starts a fresh log for the command about to run and has
+// --json envelopes include it
+func setupDryRun() {
+	dryRunLog.Lock()
+	dryRunLog.requests = nil
+	dryRunLog.started = true
+	dryRunLog.Unlock()
+
+	if dryRun {
+		ui.SetDryRun(plannedRequests)
+	} else {
+		ui.SetDryRun(nil)
+	}
+}
+
+// dryRunHeld is the output a dry run's command printed after its first
+// skipped request. The command's report of what it did is made up of
+// empty stand-in responses, so it is dropped unless the command fails.
+var dryRunHeld struct {
+	stdout *os.File
+	pipe   *os.File
+	buf    bytes.Buffer
+	done   chan struct{}
+}
+
+// recordDryRun logs a skipped request and, without --json, prints it and
+// holds back the rest of the command's output
+func recordDryRun(req asana.PlannedRequest) {
+	dryRunLog.Lock()
+	defer dryRunLog.Unlock()
+
+	dryRunLog.requests = append(dryRunLog.requests, req)
+	if jsonOutput {
+		return
+	}
+	if dryRunHeld.stdout == nil {
+		holdOutput()
+	}
+	w := dryRunHeld.stdout
+	fmt.Fprintf(w, "[dry-run] %s %s\n", req.Method, req.Endpoint)
+	switch payload := req.Payload.(type) {
+	case nil:
+	case string:
+		fmt.Fprintln(w, payload)
+	case json.RawMessage:
+		var b bytes.Buffer
+		if err := json.Indent(&b, unescapeHTML(payload), "", "  "); err != nil {
+			fmt.Fprintln(w, string(payload))
+		} else {
+			fmt.Fprintln(w, b.String())
+		}
+	default:
+		enc := json.NewEncoder(w)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(payload); err != nil {
+			fmt.Fprintln(w, payload)
+		}
+	}
+}
+
+// holdOutput points os.Stdout at a pipe collected into dryRunHeld.buf,
+// keeping the real stdout for the planned requests. If the pipe cannot be
+// made, the command's output is left alone.
+func holdOutput() {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return
+	}
+	dryRunHeld.stdout, dryRunHeld.pipe = os.Stdout, w
+	dryRunHeld.buf.Reset()
+	dryRunHeld.done = make(chan struct{})
+	go func() {
+		defer close(dryRunHeld.done)
+		dryRunHeld.buf.ReadFrom(r)
+		r.Close()
+	}()
+	os.Stdout = w
+}
+
+// finishDryRun restores stdout after a dry run, printing the held output
+// if the command failed, and ends the text output with what was skipped.
+// It runs whether or not the command succeeded.
+func finishDryRun(err error) {
+	if held := dryRunHeld.stdout; held != nil {
+		dryRunHeld.pipe.Close()
+		<-dryRunHeld.done
+		os.Stdout = held
+		dryRunHeld.stdout, dryRunHeld.pipe = nil, nil
+		if err != nil {
+			os.Stdout.Write(dryRunHeld.buf.Bytes())
+		}
+	}
+	if dryRunLog.started {
+		dryRunLog.started = false
+		printDryRunSummary()
+	}
+}
+
+// unescapeHTML undoes the \u003c, \u003e and \u0026 escapes encoding/json
+// puts in strings, so payloads print as they read
+func unescapeHTML(raw []byte) []byte {
+	out := make([]byte, 0, len(raw))
+	for i := 0; i < len(raw); i++ {
+		if raw[i] != '\\' || i+1 == len(raw) {
+			out = append(out, raw[i])
+			continue
+		}
+		if raw[i+1] == 'u' && i+6 <= len(raw) {
+			switch string(raw[i+2 : i+6]) {
+			case "003c":
+				out, i = append(out, '<'), i+5
+				continue
+			case "003e":
+				out, i = append(out, '>'), i+5
+				continue
+			case "0026":
+				out, i = append(out, '&'), i+5
+				continue
+			}
+		}
+		// Keep any other escape, including an escaped backslash, whole
+		out, i = append(out, raw[i], raw[i+1]), i+1
+	}
+	return out
+}
+
+// plannedRequests returns a copy of the requests skipped so far
+func plannedRequests() []asana.PlannedRequest {
+	dryRunLog.Lock()
+	defer dryRunLog.Unlock()
+	return append([]asana.PlannedRequest(nil), dryRunLog.requests...)
+}
+
+// printDryRunSummary ends a dry run's text output with what was skipped
+func printDryRunSummary() {
+	if !dryRun || jsonOutput {
+		return
+	}
+	n := len(plannedRequests())
+	plural := "s"
+	if n == 1 {
+		plural = ""
+	}
+	fmt.Printf("Dry run: %d request%s not sent, nothing was changed\n", n, plural)
+}
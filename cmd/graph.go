@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/TheCoolRobot/asana-cli/internal/asana"
+	"github.com/TheCoolRobot/asana-cli/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var graphSyntax string
+
+// graphFields is what the graph needs from each task in the project
+var graphFields = []string{"name", "completed", "dependencies.name", "dependencies.completed"}
+
+// graphNode is a task in a dependency graph. External nodes are
+// dependencies that live outside the project being graphed.
+type graphNode struct {
+	GID       string `json:"gid"`
+	Name      string `json:"name"`
+	Completed bool   `json:"completed"`
+	External  bool   `json:"external,omitempty"`
+}
+
+// graphEdge points from a blocking task to the task it blocks
+type graphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type dependencyGraph struct {
+	Nodes []graphNode `json:"nodes"`
+	Edges []graphEdge `json:"edges"`
+}
+
+var graphCmd = &cobra.Command{
+	Use:   "graph [project-id]",
+	Short: "Export a project's dependency graph",
+	Long: `Export a project's task dependency graph as Graphviz DOT or Mermaid.
+
+Edges point from the blocking task to the task it blocks. Dependencies
+outside the project are drawn dashed, completed tasks greyed out.
+
+  asana-cli graph <project-gid> | dot -Tsvg > deps.svg
+  asana-cli graph <project-gid> --syntax mermaid`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if graphSyntax != "dot" && graphSyntax != "mermaid" {
+			return reportError(fmt.Errorf("unknown graph syntax %q (want dot or mermaid)", graphSyntax))
+		}
+		if jsonOutput && cmd.Flags().Changed("syntax") {
+			return reportError(fmt.Errorf("--syntax draws the graph as text and cannot be combined with --json or --query"))
+		}
+
+		projectGID, err := resolveProject(cmd, args)
+		if err != nil {
+			return reportError(err)
+		}
+
+		client := newClient()
+		tasks, err := client.GetTasks(cmd.Context(), projectGID, map[string]string{
+			"opt_fields": strings.Join(graphFields, ","),
+		})
+		if err != nil {
+			return reportError(err)
+		}
+
+		graph := buildDependencyGraph(tasks)
+		if jsonOutput {
+			meta := map[string]interface{}{
+				"project_id": projectGID,
+				"nodes":      len(graph.Nodes),
+				"edges":      len(graph.Edges),
+			}
+			ui.PrintJSONWithMeta(graph, meta, nil)
+		} else if graphSyntax == "mermaid" {
+			graph.writeMermaid(os.Stdout)
+		} else {
+			graph.writeDOT(os.Stdout)
+		}
+
+		return nil
+	},
+}
+
+// buildDependencyGraph turns project tasks into nodes and "blocks" edges,
+// adding nodes for dependencies outside the project
+func buildDependencyGraph(tasks []asana.Task) dependencyGraph {
+	g := dependencyGraph{Nodes: []graphNode{}, Edges: []graphEdge{}}
+
+	inProject := make(map[string]bool, len(tasks))
+	for _, t := range tasks {
+		inProject[t.GID] = true
+		g.Nodes = append(g.Nodes, graphNode{GID: t.GID, Name: t.Name, Completed: t.Completed})
+	}
+
+	external := map[string]bool{}
+	for _, t := range tasks {
+		for _, dep := range t.Dependencies {
+			if !inProject[dep.GID] && !external[dep.GID] {
+				external[dep.GID] = true
+				g.Nodes = append(g.Nodes, graphNode{GID: dep.GID, Name: dep.Name, Completed: dep.Completed, External: true})
+			}
+			g.Edges = append(g.Edges, graphEdge{From: dep.GID, To: t.GID})
+		}
+	}
+	return g
+}
+
+func (g dependencyGraph) writeDOT(w io.Writer) {
+	fmt.Fprintln(w, "digraph dependencies {")
+	fmt.Fprintln(w, "  rankdir=LR;")
+	fmt.Fprintln(w, `  node [shape=box, style=rounded];`)
+	for _, n := range g.Nodes {
+		var attrs []string
+		attrs = append(attrs, fmt.Sprintf("label=%s", dotQuote(n.Name)))
+		if n.External {
+			attrs = append(attrs, `style="rounded,dashed"`)
+		}
+		if n.Completed {
+			attrs = append(attrs, "color=gray", "fontcolor=gray")
+		}
+		fmt.Fprintf(w, "  %s [%s];\n", dotQuote(n.GID), strings.Join(attrs, ", "))
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(w, "  %s -> %s;\n", dotQuote(e.From), dotQuote(e.To))
+	}
+	fmt.Fprintln(w, "}")
+}
+
+func (g dependencyGraph) writeMermaid(w io.Writer) {
+	fmt.Fprintln(w, "flowchart LR")
+	var done, external []string
+	for _, n := range g.Nodes {
+		fmt.Fprintf(w, "  %s[\"%s\"]\n", mermaidID(n.GID), mermaidEscape(n.Name))
+		if n.Completed {
+			done = append(done, mermaidID(n.GID))
+		}
+		if n.External {
+			external = append(external, mermaidID(n.GID))
+		}
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(w, "  %s --> %s\n", mermaidID(e.From), mermaidID(e.To))
+	}
+	if len(done) > 0 {
+		fmt.Fprintln(w, "  classDef done fill:#eee,color:#888")
+		fmt.Fprintf(w, "  class %s done\n", strings.Join(done, ","))
+	}
+	if len(external) > 0 {
+		fmt.Fprintln(w, "  classDef external stroke-dasharray: 5 5")
+		fmt.Fprintf(w, "  class %s external\n", strings.Join(external, ","))
+	}
+}
+
+// dotQuote renders s as a DOT quoted string
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
+
+// mermaidID prefixes GIDs, since Mermaid node IDs should not start with a
+// digit
+func mermaidID(gid string) string {
+	return "t" + gid
+}
+
+// mermaidEscape makes s safe inside a quoted Mermaid label
+func mermaidEscape(s string) string {
+	s = strings.ReplaceAll(s, `"`, "#quot;")
+	return strings.ReplaceAll(s, "\n", " ")
+}
+
+func init() {
+	graphCmd.Flags().StringVar(&graphSyntax, "syntax", "dot", "Graph syntax: dot or mermaid")
+}
@@ -2,40 +2,72 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/TheCoolRobot/asana-cli/internal/asana"
-	"github.com/TheCoolRobot/asana-cli/internal/config"
+	"github.com/TheCoolRobot/asana-cli/internal/query"
+	"github.com/TheCoolRobot/asana-cli/internal/syncdaemon"
 	"github.com/TheCoolRobot/asana-cli/internal/ui"
 )
 
 var (
 	filterCompleted bool
 	filterAssignee  string
-	filterTag       string
+	filterTags      []string
+	filterTagMode   string
+	listLimit       int
+	listFields      string
+	listCustom      []string
+	listWhere       string
+	listOffline     bool
 )
 
 var listCmd = &cobra.Command{
 	Use:   "list [project-id]",
 	Short: "List tasks from a project",
-	Args:  cobra.MaximumNArgs(1),
+	Long: `List the tasks in a project, in the TUI or with --json.
+
+The TUI opens only when stdin and stdout are terminals; in pipes, cron jobs
+and CI the tasks print as a table. --no-tui and --interactive force either.
+
+--offline reads the tasks the sync daemon last cached instead of calling the
+API; the client-side filters (--where, --tag and --field) still apply.
+
+--where filters with an expression such as
+  due < today and assignee = me and not completed and tag in (backend, infra)
+Fields: ` + strings.Join(query.Fields(), ", ") + `, and custom fields as
+cf.Name or cf."Name with spaces". Operators: = != < <= > >= ~ (contains) !~,
+in (a, b) and not in (...), combined with and, or, not and parentheses. A
+field on its own tests that it is set ("not assignee" means unassigned) and
+none is an unset value ("due = none").`,
+	Example: `  asana-cli list --where "due < today and not completed"
+  asana-cli list --where "not assignee and tag in (backend, infra)"
+  asana-cli list --offline --where 'cf.Priority = High or name ~ "urgent"'`,
+	Annotations: map[string]string{recordOutput: "true"},
+	Args:        cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		projectGID := ""
+		// Use provided project ID, or fall back to --project / current project
+		projectGID, err := resolveProject(cmd, args)
+		if err != nil {
+			return err
+		}
 
-		// Use provided project ID, or fall back to current project
-		if len(args) > 0 {
-			projectGID = args[0]
-		} else {
-			cfg, _ := config.Load()
-			currentProj := cfg.GetCurrentProject()
-			if currentProj == nil {
-				return fmt.Errorf("no project ID provided and no current project set. Use: asana-cli list <project-id> or asana-cli config project switch <name>")
-			}
-			projectGID = currentProj.ProjectID
+		where, err := parseWhere(listWhere)
+		if err != nil {
+			return reportError(err)
+		}
+		tui, err := useTUI()
+		if err != nil {
+			return reportError(err)
+		}
+		if listOffline && (filterCompleted || filterAssignee != "") {
+			return reportError(fmt.Errorf("--completed and --assignee are applied by the API; use --where with --offline"))
 		}
 
-		client := asana.NewClient(token)
+		client := newClient()
+		client.SetMaxItems(listLimit)
 
 		filters := make(map[string]string)
 		if filterCompleted {
@@ -44,8 +76,56 @@ var listCmd = &cobra.Command{
 		if filterAssignee != "" {
 			filters["assignee"] = filterAssignee
 		}
+		if filterTagMode != "all" && filterTagMode != "any" {
+			return reportError(fmt.Errorf("invalid --tag-mode %q, expected all or any", filterTagMode))
+		}
+		fields := asana.ParseFields(listFields)
+		if len(fields) > 0 {
+			optFields := append([]string{}, fields...)
+			if len(listCustom) > 0 {
+				optFields = append(optFields, customFieldValueFields()...)
+			}
+			if len(filterTags) > 0 {
+				optFields = append(optFields, "tags.name")
+			}
+			if where != nil {
+				optFields = append(optFields, where.OptFields()...)
+			}
+			filters["opt_fields"] = strings.Join(optFields, ",")
+		}
 
-		tasks, err := client.GetTasks(projectGID, filters)
+		// The client-side filters, applied after fetching
+		matchWhere := whereFilter(cmd, client, where)
+		filter := func(tasks []asana.Task) ([]asana.Task, error) {
+			var err error
+			if len(listCustom) > 0 {
+				if tasks, err = filterByCustomFields(tasks, listCustom); err != nil {
+					return nil, err
+				}
+			}
+			if len(filterTags) > 0 {
+				tasks = filterByTags(tasks, filterTags, filterTagMode == "any")
+			}
+			return matchWhere(tasks)
+		}
+
+		if streamOutput() && !listOffline {
+			return streamTasks(client.IterTasks(cmd.Context(), projectGID, filters), filter, fields)
+		}
+
+		var tasks []asana.Task
+		var cache *syncdaemon.CacheMetadata
+		if listOffline {
+			tasks, cache, err = syncdaemon.LoadCachedTasks(projectGID)
+			if err == nil && listLimit > 0 && len(tasks) > listLimit {
+				tasks = tasks[:listLimit]
+			}
+		} else {
+			tasks, err = client.GetTasks(cmd.Context(), projectGID, filters)
+		}
+		if err == nil {
+			tasks, err = filter(tasks)
+		}
 		if err != nil {
 			if jsonOutput {
 				ui.PrintJSON(nil, err)
@@ -61,7 +141,7 @@ var listCmd = &cobra.Command{
 			taskPtrs[i] = &tasks[i]
 		}
 
-		if jsonOutput {
+		if jsonOutput || outputFormat != "" {
 			meta := map[string]interface{}{
 				"count":      len(tasks),
 				"project_id": projectGID,
@@ -73,9 +153,34 @@ var listCmd = &cobra.Command{
 			if filterAssignee != "" {
 				meta["filter_assignee"] = filterAssignee
 			}
-			ui.PrintJSONWithMeta(tasks, meta, nil)
+			if len(listCustom) > 0 {
+				meta["filter_fields"] = listCustom
+			}
+			if len(filterTags) > 0 {
+				meta["filter_tags"] = filterTags
+				meta["filter_tag_mode"] = filterTagMode
+			}
+			if where != nil {
+				meta["filter_where"] = where.String()
+			}
+			if cache != nil {
+				meta["source"] = "cache"
+				meta["synced_at"] = cache.SyncedAt.Format(time.RFC3339)
+			}
+			// Templates see whole tasks; --fields only trims the request
+			var data interface{} = tasks
+			if len(fields) > 0 && formatTemplate == nil {
+				meta["fields"] = fields
+				data = asana.SelectFields(tasks, fields)
+			}
+			if outputFormat != "" {
+				return printOutput(data, meta, ui.TaskColumns)
+			}
+			ui.PrintJSONWithMeta(data, meta, nil)
+		} else if tui {
+			ui.StartTUI(cmd.Context(), taskPtrs, client, projectGID)
 		} else {
-			ui.StartTUI(taskPtrs, client, projectGID)
+			return printTaskTable(tasks)
 		}
 
 		return nil
@@ -85,5 +190,12 @@ var listCmd = &cobra.Command{
 func init() {
 	listCmd.Flags().BoolVar(&filterCompleted, "completed", false, "Show only completed tasks")
 	listCmd.Flags().StringVar(&filterAssignee, "assignee", "", "Filter by assignee ID")
-	listCmd.Flags().StringVar(&filterTag, "tag", "", "Filter by tag")
+	listCmd.Flags().StringArrayVar(&filterTags, "tag", nil, "Only show tasks with this tag, by name or GID (repeatable)")
+	listCmd.Flags().StringVar(&filterTagMode, "tag-mode", "all", "With several --tag filters, match tasks with all of them or any of them")
+	listCmd.Flags().IntVar(&listLimit, "limit", 0, "Maximum number of tasks to fetch (0 = all)")
+	listCmd.Flags().StringArrayVar(&listCustom, "field", nil, "Only show tasks whose custom field has this value, e.g. \"Sprint=12\" (repeatable)")
+	addTUIFlags(listCmd)
+	listCmd.Flags().StringVar(&listWhere, "where", "", whereHelp)
+	listCmd.Flags().BoolVar(&listOffline, "offline", false, "Read tasks from the sync daemon's cache instead of the API")
+	listCmd.Flags().StringVar(&listFields, "fields", "", "Comma-separated fields to request and output (e.g. name,due_on,assignee.name)")
 }
\ No newline at end of file
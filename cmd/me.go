@@ -3,18 +3,18 @@ package cmd
 import (
 	"fmt"
 
-	"github.com/TheCoolRobot/asana-cli/internal/asana"
 	"github.com/TheCoolRobot/asana-cli/internal/ui"
 	"github.com/spf13/cobra"
 )
 
 var meCmd = &cobra.Command{
-	Use:   "me",
-	Short: "Show current user info",
+	Use:         "me",
+	Short:       "Show current user info",
+	Annotations: map[string]string{recordOutput: "true"},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		client := asana.NewClient(token)
+		client := newClient()
 
-		user, err := client.GetMe()
+		user, err := client.GetMe(cmd.Context())
 		if err != nil {
 			if jsonOutput {
 				ui.PrintJSON(nil, err)
@@ -24,7 +24,9 @@ var meCmd = &cobra.Command{
 			return err
 		}
 
-		if jsonOutput {
+		if outputFormat != "" {
+			return printOutput(user, nil, ui.UserColumns)
+		} else if jsonOutput {
 			ui.PrintJSON(user, nil)
 		} else {
 			fmt.Printf("👤 %s\n", user.Name)
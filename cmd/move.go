@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/TheCoolRobot/asana-cli/internal/asana"
+	"github.com/TheCoolRobot/asana-cli/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	moveSection string
+	moveBefore  string
+	moveAfter   string
+)
+
+var moveCmd = &cobra.Command{
+	Use:   "move [task-id...] --section <name-or-gid>",
+	Short: "Move tasks to another section",
+	Long: `Move tasks into a section, taking them out of their current section in
+that project.
+
+The section may be given by name (case-insensitive) or GID. Names are looked
+up in --project or the current project, or else in the first task's first
+project.
+
+With several GIDs or --stdin the tasks are moved concurrently and a summary
+of each is printed; the exit code is non-zero if any failed. More than 5
+tasks are moved through the Batch API, 10 to a request.`,
+	Example: `  asana-cli move 1201 --section "In Progress"
+  asana-cli list --ndjson --where "completed" | asana-cli move --stdin --section Done`,
+	Args: taskGIDArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		gids, err := taskGIDs(cmd, args)
+		if err != nil {
+			return reportError(err)
+		}
+		if bulkMode(args) && (moveBefore != "" || moveAfter != "") {
+			return reportError(fmt.Errorf("--before and --after only work when moving a single task"))
+		}
+		taskGID := gids[0]
+		client := newClient()
+
+		projectGID := currentProject(cmd)
+		if projectGID == "" && !isGID(moveSection) {
+			task, err := client.GetTask(cmd.Context(), taskGID)
+			if err != nil {
+				return reportError(err)
+			}
+			if len(task.Projects) == 0 {
+				return reportError(fmt.Errorf("task %s is not in a project, give the section by GID", taskGID))
+			}
+			projectGID = task.Projects[0].GID
+		}
+
+		sec, err := resolveSection(cmd.Context(), client, projectGID, moveSection)
+		if err != nil {
+			return reportError(err)
+		}
+
+		if bulkMode(args) {
+			results := runBulk(cmd.Context(), client, gids, bulkOp{
+				call: func(ctx context.Context, gid string) (string, error) {
+					return "", client.AddTaskToSection(ctx, sec.GID, gid, "", "")
+				},
+				actions: func(gid string) ([]asana.BatchAction, error) {
+					return []asana.BatchAction{asana.AddTaskToSectionAction(sec.GID, gid, "", "")}, nil
+				},
+			})
+			return printBulk("moved", results, map[string]interface{}{"section_gid": sec.GID})
+		}
+
+		if err := client.AddTaskToSection(cmd.Context(), sec.GID, taskGID, moveBefore, moveAfter); err != nil {
+			return reportError(err)
+		}
+
+		if jsonOutput {
+			meta := map[string]interface{}{
+				"action":      "moved",
+				"task_gid":    taskGID,
+				"section_gid": sec.GID,
+			}
+			ui.PrintJSONWithMeta(sec, meta, nil)
+		} else {
+			fmt.Printf("✓ Moved task %s to section %s\n", taskGID, sec.Name)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	moveCmd.Flags().StringVar(&moveSection, "section", "", "Target section name or GID (required)")
+	moveCmd.Flags().StringVar(&moveBefore, "before", "", "Place it before this task in the section")
+	moveCmd.Flags().StringVar(&moveAfter, "after", "", "Place it after this task in the section")
+	moveCmd.MarkFlagsMutuallyExclusive("before", "after")
+	moveCmd.MarkFlagRequired("section")
+	addBulkFlags(moveCmd)
+}
@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/TheCoolRobot/asana-cli/internal/asana"
+	"github.com/TheCoolRobot/asana-cli/internal/config"
+	"github.com/TheCoolRobot/asana-cli/internal/jq"
+	"github.com/TheCoolRobot/asana-cli/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	outputFormat   string
+	outputColumns  []string
+	outputTemplate string
+
+	// formatTemplate is the parsed --format template, if any
+	formatTemplate *template.Template
+
+	noTUI       bool
+	interactive bool
+	ndjsonOut   bool
+	queryExpr   string
+)
+
+// recordOutput marks the commands that print records and so accept every
+// --output format; the rest only have their usual text and --json
+const recordOutput = "record-output"
+
+// setupOutput validates --output and --columns for the command about to
+// run. --output json is the same as --json, and --columns on its own
+// means a table (or json, with --json).
+func setupOutput(cmd *cobra.Command) error {
+	formatTemplate = nil
+	ui.SetQuery(nil)
+	if queryExpr != "" {
+		if err := setupQuery(); err != nil {
+			return err
+		}
+	}
+	if outputTemplate != "" {
+		return setupTemplate(cmd)
+	}
+	if ndjsonOut {
+		if jsonOutput || (outputFormat != "" && outputFormat != "ndjson") {
+			return fmt.Errorf("--ndjson cannot be combined with --json or --output")
+		}
+		outputFormat = "ndjson"
+	}
+
+	if outputFormat == "" && len(outputColumns) > 0 {
+		outputFormat = "table"
+		if jsonOutput {
+			outputFormat = "json"
+		}
+	}
+	if outputFormat == "" {
+		return nil
+	}
+	if !containsString(ui.OutputFormats, outputFormat) {
+		return fmt.Errorf("invalid --output %q (formats: %s)", outputFormat, strings.Join(ui.OutputFormats, ", "))
+	}
+	if outputFormat == "json" {
+		jsonOutput = true
+	}
+
+	if cmd.Annotations[recordOutput] == "" {
+		if outputFormat == "json" && len(outputColumns) == 0 {
+			outputFormat = ""
+			return nil
+		}
+		return fmt.Errorf("%s only supports --json output, not --output %s or --columns", cmd.CommandPath(), outputFormat)
+	}
+	return nil
+}
+
+// setupQuery parses --query, which works on the --json envelope and so
+// turns --json on
+func setupQuery() error {
+	if outputTemplate != "" || ndjsonOut || (outputFormat != "" && outputFormat != "json") {
+		return fmt.Errorf("--query works on the --json envelope and cannot be combined with --output, --ndjson or --format")
+	}
+	q, err := jq.Parse(queryExpr)
+	if err != nil {
+		return err
+	}
+	ui.SetQuery(q)
+	jsonOutput = true
+	return nil
+}
+
+// setupTemplate parses --format, which is either a saved template's name
+// or the template itself
+func setupTemplate(cmd *cobra.Command) error {
+	if outputFormat != "" || len(outputColumns) > 0 || jsonOutput || ndjsonOut {
+		return fmt.Errorf("--format cannot be combined with --output, --columns, --json or --ndjson")
+	}
+	if cmd.Annotations[recordOutput] == "" {
+		return fmt.Errorf("%s does not support --format", cmd.CommandPath())
+	}
+
+	text := outputTemplate
+	cfg, _ := config.Load()
+	if saved, ok := cfg.Templates[text]; ok {
+		text = saved
+	} else if !strings.Contains(text, "{{") {
+		return fmt.Errorf("no saved template %q (see asana-cli config template list)", text)
+	}
+
+	tmpl, err := ui.ParseTemplate(text)
+	if err != nil {
+		return fmt.Errorf("invalid --format template: %w", err)
+	}
+	formatTemplate = tmpl
+	outputFormat = "template"
+	return nil
+}
+
+// printOutput writes a command's records in the --output format, or
+// through the --format template. meta is only used by json.
+func printOutput(data interface{}, meta map[string]interface{}, columns ui.Columns) error {
+	if formatTemplate != nil {
+		if err := ui.PrintTemplate(os.Stdout, formatTemplate, data); err != nil {
+			return reportError(err)
+		}
+		return nil
+	}
+	if err := ui.PrintOutput(os.Stdout, outputFormat, data, meta, columns, outputColumns); err != nil {
+		return reportError(err)
+	}
+	return nil
+}
+
+// addTUIFlags adds --no-tui and --interactive to a command that opens the
+// TUI by default
+func addTUIFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&noTUI, "no-tui", false, "Print a table instead of opening the TUI")
+	cmd.Flags().BoolVar(&interactive, "interactive", false, "Open the TUI even when stdin or stdout is not a terminal")
+	cmd.MarkFlagsMutuallyExclusive("no-tui", "interactive")
+}
+
+// useTUI decides whether a command opens the TUI: --interactive and
+// --no-tui force it either way, otherwise it needs a terminal on both
+// stdin and stdout, so pipes, cron jobs and CI get a plain table
+func useTUI() (bool, error) {
+	if interactive {
+		if jsonOutput || outputFormat != "" {
+			return false, fmt.Errorf("--interactive cannot be combined with --json, --output or --format")
+		}
+		return true, nil
+	}
+	return !noTUI && ui.Interactive(), nil
+}
+
+// printTaskTable prints tasks as a plain table, in place of the TUI
+func printTaskTable(tasks []asana.Task) error {
+	if len(tasks) == 0 {
+		fmt.Println("No tasks")
+		return nil
+	}
+	if err := ui.PrintOutput(os.Stdout, "table", tasks, nil, ui.TaskColumns, nil); err != nil {
+		return reportError(err)
+	}
+	return nil
+}
+
+// streamOutput reports whether records should be printed as they arrive
+// rather than collected first, which is the case for NDJSON
+func streamOutput() bool {
+	return outputFormat == "ndjson"
+}
+
+// streamTasks prints tasks as NDJSON while their pages arrive. filter
+// applies the command's client-side filters to each page, and fields
+// trims the records as with --fields.
+func streamTasks(it *asana.Iterator[asana.Task], filter func([]asana.Task) ([]asana.Task, error), fields []string) error {
+	w := ui.NewNDJSONWriter(os.Stdout, ui.TaskColumns, outputColumns)
+	for it.Next() {
+		tasks, err := filter([]asana.Task{it.Value()})
+		if err != nil {
+			return reportError(err)
+		}
+		for _, task := range tasks {
+			var record interface{} = task
+			if len(fields) > 0 {
+				record = asana.SelectFields(task, fields)
+			}
+			if err := w.Write(record); err != nil {
+				return reportError(err)
+			}
+		}
+	}
+	if err := it.Err(); err != nil {
+		return reportError(err)
+	}
+	return nil
+}
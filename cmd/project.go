@@ -0,0 +1,433 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/TheCoolRobot/asana-cli/internal/asana"
+	"github.com/TheCoolRobot/asana-cli/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	projectName        string
+	projectDescription string
+	projectTeam        string
+	projectColor       string
+	projectView        string
+	projectDue         string
+	projectPublic      bool
+	projectPrivate     bool
+	projectArchived    bool
+	projectInclude     []string
+	projectNoWait      bool
+)
+
+// jobPollInterval is how often project duplicate checks on its job
+var jobPollInterval = time.Second
+
+var projectCmd = &cobra.Command{
+	Use:   "project",
+	Short: "Manage Asana projects",
+	Long: `List, create, update, archive, delete and duplicate projects.
+
+Projects are listed and created in --workspace, the configured default
+workspace or the current project's workspace. Teams may be given by name or
+GID. To save a project for use with other commands, see "config project add".`,
+}
+
+var projectListCmd = &cobra.Command{
+	Use:         "list",
+	Short:       "List the projects in a workspace",
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{recordOutput: "true"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := newClient()
+		workspaceGID, err := resolveWorkspace(cmd, client)
+		if err != nil {
+			return reportError(err)
+		}
+
+		filters := map[string]string{"archived": fmt.Sprint(projectArchived)}
+		if projectTeam != "" {
+			team, err := resolveTeam(cmd, client, workspaceGID, projectTeam)
+			if err != nil {
+				return reportError(err)
+			}
+			filters["team"] = team.GID
+		}
+
+		projects, err := client.GetProjectsFiltered(cmd.Context(), workspaceGID, filters)
+		if err != nil {
+			return reportError(err)
+		}
+
+		if jsonOutput || outputFormat != "" {
+			meta := map[string]interface{}{
+				"count":        len(projects),
+				"workspace_id": workspaceGID,
+				"archived":     projectArchived,
+			}
+			if projectTeam != "" {
+				meta["team_id"] = filters["team"]
+			}
+			if outputFormat != "" {
+				return printOutput(projects, meta, ui.ProjectColumns)
+			}
+			ui.PrintJSONWithMeta(projects, meta, nil)
+		} else if len(projects) == 0 {
+			fmt.Println("No projects")
+		} else {
+			for _, p := range projects {
+				fmt.Printf("%-20s %s%s\n", p.GID, p.Name, projectSummary(&p))
+			}
+		}
+
+		return nil
+	},
+}
+
+var projectCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create a project",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := validateProjectFlags(); err != nil {
+			return reportError(err)
+		}
+
+		client := newClient()
+		workspaceGID, err := resolveWorkspace(cmd, client)
+		if err != nil {
+			return reportError(err)
+		}
+
+		req := &asana.ProjectCreateRequest{
+			Name:        args[0],
+			Description: projectDescription,
+			Workspace:   workspaceGID,
+			Color:       projectColor,
+			DefaultView: projectView,
+			DueOn:       projectDue,
+			Public:      projectVisibility(),
+		}
+		if projectTeam != "" {
+			team, err := resolveTeam(cmd, client, workspaceGID, projectTeam)
+			if err != nil {
+				return reportError(err)
+			}
+			req.Team = team.GID
+		}
+
+		p, err := client.CreateProject(cmd.Context(), req)
+		if err != nil {
+			return reportError(err)
+		}
+
+		if jsonOutput {
+			meta := map[string]interface{}{
+				"action":       "created",
+				"workspace_id": workspaceGID,
+			}
+			ui.PrintJSONWithMeta(p, meta, nil)
+		} else {
+			fmt.Printf("✓ Project created: %s\n", p.Name)
+			fmt.Printf("  GID: %s\n", p.GID)
+		}
+
+		return nil
+	},
+}
+
+var projectUpdateCmd = &cobra.Command{
+	Use:   "update <project-id>",
+	Short: "Update a project",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := validateProjectFlags(); err != nil {
+			return reportError(err)
+		}
+
+		client := newClient()
+		req := &asana.ProjectUpdateRequest{
+			Name:        projectName,
+			Description: projectDescription,
+			Color:       projectColor,
+			DefaultView: projectView,
+			DueOn:       projectDue,
+			Public:      projectVisibility(),
+		}
+		if projectTeam != "" {
+			team, err := resolveTeam(cmd, client, "", projectTeam)
+			if err != nil {
+				return reportError(err)
+			}
+			req.Team = team.GID
+		}
+		if *req == (asana.ProjectUpdateRequest{}) {
+			return reportError(fmt.Errorf("nothing to update; set at least one of --name, --description, --team, --color, --view, --due, --public or --private"))
+		}
+
+		p, err := client.UpdateProject(cmd.Context(), args[0], req)
+		if err != nil {
+			return reportError(err)
+		}
+
+		if jsonOutput {
+			ui.PrintJSONWithMeta(p, map[string]interface{}{"action": "updated"}, nil)
+		} else {
+			fmt.Printf("✓ Project updated: %s\n", p.Name)
+		}
+
+		return nil
+	},
+}
+
+var projectArchiveCmd = &cobra.Command{
+	Use:   "archive <project-id>",
+	Short: "Archive a project",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return archiveProject(cmd, args[0], true)
+	},
+}
+
+var projectUnarchiveCmd = &cobra.Command{
+	Use:   "unarchive <project-id>",
+	Short: "Restore an archived project",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return archiveProject(cmd, args[0], false)
+	},
+}
+
+func archiveProject(cmd *cobra.Command, projectGID string, archived bool) error {
+	p, err := newClient().ArchiveProject(cmd.Context(), projectGID, archived)
+	if err != nil {
+		return reportError(err)
+	}
+
+	action := "archived"
+	if !archived {
+		action = "unarchived"
+	}
+	if jsonOutput {
+		ui.PrintJSONWithMeta(p, map[string]interface{}{"action": action}, nil)
+	} else {
+		fmt.Printf("✓ Project %s: %s\n", action, p.Name)
+	}
+	return nil
+}
+
+var projectDeleteCmd = &cobra.Command{
+	Use:   "delete <project-id>",
+	Short: "Delete a project",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectGID := args[0]
+		if err := newClient().DeleteProject(cmd.Context(), projectGID); err != nil {
+			return reportError(err)
+		}
+
+		if jsonOutput {
+			meta := map[string]interface{}{
+				"action":     "deleted",
+				"project_id": projectGID,
+			}
+			ui.PrintJSONWithMeta(map[string]string{"status": "deleted"}, meta, nil)
+		} else {
+			fmt.Printf("✓ Project deleted\n")
+		}
+
+		return nil
+	},
+}
+
+var projectDuplicateCmd = &cobra.Command{
+	Use:   "duplicate <project-id>",
+	Short: "Copy a project",
+	Long: `Copy a project with its sections and tasks.
+
+--include adds optional parts to the copy: members, notes, forms,
+task_notes, task_assignee, task_subtasks, task_attachments, task_dates,
+task_dependencies, task_followers, task_tags and task_projects.
+
+Asana copies projects in the background; the command waits for the copy to
+finish unless --no-wait is given.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := newClient()
+
+		name := projectName
+		if name == "" {
+			src, err := client.GetProject(cmd.Context(), args[0])
+			if err != nil {
+				return reportError(err)
+			}
+			name = "Copy of " + src.Name
+		}
+
+		req := &asana.ProjectDuplicateRequest{
+			Name:    name,
+			Include: strings.Join(projectInclude, ","),
+		}
+		if projectTeam != "" {
+			team, err := resolveTeam(cmd, client, "", projectTeam)
+			if err != nil {
+				return reportError(err)
+			}
+			req.Team = team.GID
+		}
+
+		job, err := client.DuplicateProject(cmd.Context(), args[0], req)
+		if err == nil && !projectNoWait {
+			job, err = client.WaitForJob(cmd.Context(), job, jobPollInterval)
+		}
+		if err != nil {
+			return reportError(err)
+		}
+		if job.NewProject == nil {
+			job.NewProject = &asana.Project{Name: name}
+		}
+
+		if jsonOutput {
+			meta := map[string]interface{}{
+				"action":     "duplicated",
+				"project_id": args[0],
+				"job_status": job.Status,
+			}
+			ui.PrintJSONWithMeta(job.NewProject, meta, nil)
+		} else if job.Status == asana.JobSucceeded {
+			fmt.Printf("✓ Project duplicated: %s\n", job.NewProject.Name)
+			fmt.Printf("  GID: %s\n", job.NewProject.GID)
+		} else {
+			fmt.Printf("✓ Duplicating project as %s (job %s, %s)\n", job.NewProject.Name, job.GID, job.Status)
+			fmt.Printf("  GID: %s\n", job.NewProject.GID)
+		}
+
+		return nil
+	},
+}
+
+// projectSummary describes a project's team and state for list output
+func projectSummary(p *asana.Project) string {
+	var parts []string
+	if p.Team != nil && p.Team.Name != "" {
+		parts = append(parts, p.Team.Name)
+	}
+	if p.DueOn != nil && !p.DueOn.IsZero() {
+		parts = append(parts, "due "+p.DueOn.Format("2006-01-02"))
+	}
+	if p.Archived {
+		parts = append(parts, "archived")
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(parts, ", ") + ")"
+}
+
+func validateProjectFlags() error {
+	if projectColor != "" && !containsString(asana.ProjectColors, projectColor) {
+		return fmt.Errorf("invalid color %q (colors: %s)", projectColor, strings.Join(asana.ProjectColors, ", "))
+	}
+	if projectView != "" && !containsString(asana.ProjectViews, projectView) {
+		return fmt.Errorf("invalid view %q (views: %s)", projectView, strings.Join(asana.ProjectViews, ", "))
+	}
+	if projectDue != "" {
+		if _, err := time.Parse("2006-01-02", projectDue); err != nil {
+			return fmt.Errorf("invalid due date %q, expected YYYY-MM-DD", projectDue)
+		}
+	}
+	return nil
+}
+
+// projectVisibility turns --public/--private into the request's public
+// field, which is left unset when neither is given
+func projectVisibility() *bool {
+	switch {
+	case projectPublic:
+		return &projectPublic
+	case projectPrivate:
+		public := false
+		return &public
+	}
+	return nil
+}
+
+// resolveTeam finds a team by GID or case-insensitive name. Teams are
+// looked up in workspaceGID, or the resolved workspace when it is empty. A
+// ref that matches no team but looks like a GID is used as one.
+func resolveTeam(cmd *cobra.Command, client *asana.Client, workspaceGID, ref string) (*asana.Team, error) {
+	var teams []asana.Team
+	var err error
+	if workspaceGID == "" {
+		workspaceGID, err = resolveWorkspace(cmd, client)
+	}
+	if err == nil {
+		teams, err = client.GetTeams(cmd.Context(), workspaceGID)
+	}
+	if err != nil {
+		if isGID(ref) {
+			return &asana.Team{GID: ref}, nil
+		}
+		return nil, err
+	}
+
+	for _, t := range teams {
+		if t.GID == ref {
+			return &t, nil
+		}
+	}
+	names := make([]string, 0, len(teams))
+	for _, t := range teams {
+		if strings.EqualFold(t.Name, ref) {
+			return &t, nil
+		}
+		names = append(names, t.Name)
+	}
+	if isGID(ref) {
+		return &asana.Team{GID: ref}, nil
+	}
+	return nil, fmt.Errorf("no team %q in workspace %s (teams: %s)", ref, workspaceGID, strings.Join(names, ", "))
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	for _, c := range []*cobra.Command{projectCreateCmd, projectUpdateCmd} {
+		c.Flags().StringVar(&projectDescription, "description", "", "Project description")
+		c.Flags().StringVar(&projectTeam, "team", "", "Team name or GID")
+		c.Flags().StringVar(&projectColor, "color", "", "Color, e.g. dark-blue or light-green")
+		c.Flags().StringVar(&projectView, "view", "", "Default view: list, board, calendar or timeline")
+		c.Flags().StringVar(&projectDue, "due", "", "Due date (YYYY-MM-DD)")
+		c.Flags().BoolVar(&projectPublic, "public", false, "Make the project public to its team or workspace")
+		c.Flags().BoolVar(&projectPrivate, "private", false, "Make the project private to its members")
+		c.MarkFlagsMutuallyExclusive("public", "private")
+	}
+	projectUpdateCmd.Flags().StringVar(&projectName, "name", "", "New project name")
+
+	projectListCmd.Flags().BoolVar(&projectArchived, "archived", false, "List archived projects instead of active ones")
+	projectListCmd.Flags().StringVar(&projectTeam, "team", "", "Only list the projects of this team (name or GID)")
+
+	projectDuplicateCmd.Flags().StringVar(&projectName, "name", "", "Name of the copy (default \"Copy of <name>\")")
+	projectDuplicateCmd.Flags().StringVar(&projectTeam, "team", "", "Team for the copy (name or GID)")
+	projectDuplicateCmd.Flags().StringSliceVar(&projectInclude, "include", nil, "Optional parts to copy, e.g. notes,task_notes,task_assignee")
+	projectDuplicateCmd.Flags().BoolVar(&projectNoWait, "no-wait", false, "Return as soon as the copy has started")
+
+	projectCmd.AddCommand(projectListCmd)
+	projectCmd.AddCommand(projectCreateCmd)
+	projectCmd.AddCommand(projectUpdateCmd)
+	projectCmd.AddCommand(projectArchiveCmd)
+	projectCmd.AddCommand(projectUnarchiveCmd)
+	projectCmd.AddCommand(projectDeleteCmd)
+	projectCmd.AddCommand(projectDuplicateCmd)
+}
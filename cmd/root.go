@@ -1,10 +1,19 @@
 package cmd
 
 import (
-	// "fmt"
+	"context"
+	"errors"
+	"fmt"
+	"log"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
+	"github.com/TheCoolRobot/asana-cli/internal/asana"
 	"github.com/TheCoolRobot/asana-cli/internal/config"
+	"github.com/TheCoolRobot/asana-cli/internal/ui"
 	"github.com/spf13/cobra"
 )
 
@@ -13,6 +22,11 @@ var (
 	token       string
 	workspace   string
 	project     string
+	timeout     time.Duration
+	debugHTTP   bool
+
+	// cancelTimeout releases the --timeout deadline once the command returns
+	cancelTimeout context.CancelFunc = func() {}
 )
 
 var rootCmd = &cobra.Command{
@@ -20,10 +34,27 @@ var rootCmd = &cobra.Command{
 	Short:   "Asana CLI - Beautiful task management",
 	Long:    "A feature-rich CLI for managing Asana tasks with TUI and sync daemon",
 	Version: getVersion(),
-	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		ui.SetupColor()
+		setupDryRun()
+		if err := setupOutput(cmd); err != nil {
+			return reportError(err)
+		}
 		if token == "" {
 			token = config.GetAPIToken()
 		}
+		if timeout > 0 {
+			ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+			cmd.SetContext(ctx)
+			cancelTimeout = cancel
+		}
+		return nil
+	},
+	PersistentPostRunE: func(cmd *cobra.Command, args []string) error {
+		if err := ui.QueryError(); err != nil {
+			return reportError(err)
+		}
+		return nil
 	},
 }
 
@@ -36,11 +67,94 @@ func getVersion() string {
 	return "dev"
 }
 
+// newClient builds an API client configured from the global flags.
+// ASANA_BASE_URL overrides the API root, e.g. to target a local stand-in.
+func newClient() *asana.Client {
+	opts := []asana.Option{
+		asana.WithUserAgent("asana-cli/" + getVersion()),
+	}
+	if baseURL := os.Getenv("ASANA_BASE_URL"); baseURL != "" {
+		opts = append(opts, asana.WithBaseURL(baseURL))
+	}
+	if debugHTTP {
+		opts = append(opts, asana.WithLogger(log.New(os.Stderr, "[http] ", log.Ltime|log.Lmicroseconds)))
+	}
+	if dryRun {
+		opts = append(opts, asana.WithDryRun(recordDryRun))
+	}
+	return asana.NewClient(token, opts...)
+}
+
+// resolveProject picks the project a command works on: the first argument,
+// then --project, then the current project from the config
+func resolveProject(cmd *cobra.Command, args []string) (string, error) {
+	if len(args) > 0 && args[0] != "" {
+		return args[0], nil
+	}
+	if project != "" {
+		return project, nil
+	}
+	cfg, _ := config.Load()
+	if currentProj := cfg.GetCurrentProject(); currentProj != nil {
+		return currentProj.ProjectID, nil
+	}
+	return "", fmt.Errorf("no project ID provided and no current project set. Use: %s <project-id> or asana-cli config project switch <name>", cmd.CommandPath())
+}
+
+// resolveWorkspace picks the workspace from --workspace, the configured
+// default, the current project's workspace or, failing those, the user's
+// only workspace
+func resolveWorkspace(cmd *cobra.Command, client *asana.Client) (string, error) {
+	if workspace != "" {
+		return workspace, nil
+	}
+	cfg, _ := config.Load()
+	if cfg.DefaultWorkspace != "" {
+		return cfg.DefaultWorkspace, nil
+	}
+	if currentProj := cfg.GetCurrentProject(); currentProj != nil && currentProj.WorkspaceID != "" {
+		return currentProj.WorkspaceID, nil
+	}
+
+	workspaces, err := client.GetWorkspaces(cmd.Context())
+	if err != nil {
+		return "", err
+	}
+	if len(workspaces) == 1 {
+		return workspaces[0].GID, nil
+	}
+	return "", fmt.Errorf("no workspace ID provided and no default workspace set. Use --workspace <workspace-id> or asana-cli config set --workspace <workspace-id>")
+}
+
+// reportError prints err the way the --json flag asks for and returns it,
+// so a command can end with `return reportError(err)`
+func reportError(err error) error {
+	if jsonOutput {
+		ui.PrintJSON(nil, err)
+	} else {
+		fmt.Println("Error:", err)
+		// Syntax errors in --where and --query underline the problem
+		var syntaxErr interface{ Pointer() string }
+		if errors.As(err, &syntaxErr) {
+			fmt.Println(syntaxErr.Pointer())
+		}
+	}
+	return err
+}
+
 func init() {
 	rootCmd.PersistentFlags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "", "Output format: "+strings.Join(ui.OutputFormats, ", ")+" (list, search, view, me, project list and config project list)")
+	rootCmd.PersistentFlags().StringVarP(&queryExpr, "query", "q", "", "jq-style expression applied to the --json output, e.g. '.data[] | select(.completed | not) | .gid' (implies --json)")
+	rootCmd.PersistentFlags().BoolVar(&ndjsonOut, "ndjson", false, "Stream records as newline-delimited JSON, one per line as pages arrive (same as --output ndjson)")
+	rootCmd.PersistentFlags().StringVar(&outputTemplate, "format", "", "Go template for each record, e.g. '{{.Name}} ({{.DueDate | date \"Jan 2\"}})', or a saved template's name")
+	rootCmd.PersistentFlags().StringSliceVar(&outputColumns, "columns", nil, "Columns for --output, e.g. gid,name,due,assignee (any JSON field path also works)")
 	rootCmd.PersistentFlags().StringVar(&token, "token", "", "Asana API token (or set ASANA_TOKEN)")
 	rootCmd.PersistentFlags().StringVar(&workspace, "workspace", "", "Default workspace ID")
 	rootCmd.PersistentFlags().StringVar(&project, "project", "", "Default project ID")
+	rootCmd.PersistentFlags().BoolVar(&debugHTTP, "debug-http", false, "Trace API requests and responses to stderr (token redacted)")
+	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "Print the requests that would change data instead of sending them (reads still run)")
+	rootCmd.PersistentFlags().DurationVar(&timeout, "timeout", 0, "Abort the command if it takes longer than this (e.g. 30s, 2m; 0 = no limit)")
 
 	// Add all commands
 	rootCmd.AddCommand(listCmd)
@@ -53,8 +167,33 @@ func init() {
 	rootCmd.AddCommand(configCmd)
 	rootCmd.AddCommand(syncCmd)
 	rootCmd.AddCommand(meCmd)
+	rootCmd.AddCommand(commentCmd)
+	rootCmd.AddCommand(subtaskCmd)
+	rootCmd.AddCommand(dependCmd)
+	rootCmd.AddCommand(graphCmd)
+	rootCmd.AddCommand(attachCmd)
+	rootCmd.AddCommand(attachmentsCmd)
+	rootCmd.AddCommand(sectionCmd)
+	rootCmd.AddCommand(moveCmd)
+	rootCmd.AddCommand(projectCmd)
+	rootCmd.AddCommand(tagCmd)
+	rootCmd.AddCommand(schemaCmd)
 }
 
+// Execute runs the root command with a context that is cancelled on
+// SIGINT/SIGTERM, so in-flight API requests are aborted on Ctrl+C
 func Execute() error {
-	return rootCmd.Execute()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	defer func() { cancelTimeout() }()
+
+	return execute(ctx)
+}
+
+// execute runs the root command and finishes what it started even when
+// the command fails, which cobra's post-run hooks are skipped for
+func execute(ctx context.Context) error {
+	err := rootCmd.ExecuteContext(ctx)
+	finishDryRun(err)
+	return err
 }
\ No newline at end of file
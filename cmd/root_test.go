@@ -32,4 +32,12 @@ func TestPersistentFlags(t *testing.T) {
 	if flags.Lookup("project") == nil {
 		t.Error("project flag not found")
 	}
+
+	if flags.Lookup("timeout") == nil {
+		t.Error("timeout flag not found")
+	}
+
+	if flags.Lookup("debug-http") == nil {
+		t.Error("debug-http flag not found")
+	}
 }
\ No newline at end of file
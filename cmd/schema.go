@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/TheCoolRobot/asana-cli/internal/asana"
+	"github.com/TheCoolRobot/asana-cli/internal/config"
+	"github.com/TheCoolRobot/asana-cli/internal/schema"
+	"github.com/TheCoolRobot/asana-cli/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// outputType is a record type that appears in --json output
+type outputType struct {
+	name        string
+	description string
+	value       interface{}
+}
+
+var outputTypes = []outputType{
+	{"envelope", "the object every --json command prints around its data", ui.JSONOutput{}},
+	{"task", "a task, as listed by list and search", asana.Task{}},
+	{"task-detail", "a task with its subtask tree and stories, as shown by view", taskDetail{}},
+	{"subtask", "a subtask and its own subtasks, as listed by subtask list", subtaskNode{}},
+	{"project", "a project, as listed by project list", asana.Project{}},
+	{"saved-project", "a project saved in the config, as listed by config project list", config.ProjectConfig{}},
+	{"user", "a user, as shown by me", asana.User{}},
+	{"workspace", "a workspace", asana.Workspace{}},
+	{"section", "a project section", asana.Section{}},
+	{"tag", "a tag", asana.Tag{}},
+	{"story", "a comment or activity entry", asana.Story{}},
+	{"attachment", "a file attached to a task", asana.Attachment{}},
+	{"custom-field", "a custom field definition", asana.CustomField{}},
+	{"job", "an asynchronous job such as a project duplication", asana.Job{}},
+	{"bulk-result", "the outcome for one task of complete, update or delete with several tasks", bulkResult{}},
+}
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema [type]",
+	Short: "Print the JSON Schema of an output type",
+	Long: `Print a JSON Schema document describing the records of one --json output
+type, generated from the structs the CLI encodes. Without a type, the types
+are listed. With --json the document is printed as the envelope's data,
+so --query can pick it apart.
+
+Every --json envelope carries meta.schema_version, currently ` + fmt.Sprint(ui.SchemaVersion) + `. It only
+changes when a field is removed, renamed or changes type; new fields may be
+added at any time.`,
+	Example: `  asana-cli schema task
+  asana-cli schema envelope > envelope.schema.json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			if jsonOutput {
+				names := make([]string, len(outputTypes))
+				for i, t := range outputTypes {
+					names[i] = t.name
+				}
+				ui.PrintJSONWithMeta(names, map[string]interface{}{"count": len(names)}, nil)
+				return nil
+			}
+			for _, t := range outputTypes {
+				fmt.Printf("%-14s %s\n", t.name, t.description)
+			}
+			return nil
+		}
+
+		doc, err := outputSchema(args[0])
+		if err != nil {
+			return reportError(err)
+		}
+		// With --json (and so --query) the document is the envelope's data
+		if jsonOutput {
+			ui.PrintJSONWithMeta(doc, map[string]interface{}{"type": args[0]}, nil)
+			return nil
+		}
+		b, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return reportError(err)
+		}
+		fmt.Println(string(b))
+		return nil
+	},
+}
+
+// outputSchema generates the schema document of a named output type
+func outputSchema(name string) (schema.Schema, error) {
+	for _, t := range outputTypes {
+		if t.name != name {
+			continue
+		}
+		doc := schema.Generate(t.name, t.value)
+		doc["description"] = strings.ToUpper(t.description[:1]) + t.description[1:]
+		if t.name == "envelope" {
+			describeEnvelopeMeta(doc)
+		}
+		return doc, nil
+	}
+
+	names := make([]string, len(outputTypes))
+	for i, t := range outputTypes {
+		names[i] = t.name
+	}
+	return nil, fmt.Errorf("unknown output type %q (types: %s)", name, strings.Join(names, ", "))
+}
+
+// describeEnvelopeMeta fills in the meta object, a free-form map in Go,
+// with the schema version every envelope carries
+func describeEnvelopeMeta(doc schema.Schema) {
+	envelope := doc["$defs"].(schema.Schema)["JSONOutput"].(schema.Schema)
+	envelope["properties"].(schema.Schema)["meta"] = schema.Schema{
+		"type": "object",
+		"properties": schema.Schema{
+			"schema_version": schema.Schema{"type": "integer", "const": ui.SchemaVersion},
+		},
+		"required":             []string{"schema_version"},
+		"additionalProperties": true,
+	}
+	envelope["required"] = append(envelope["required"].([]string), "meta")
+}
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TheCoolRobot/asana-cli/internal/asana"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// checkGolden compares got with testdata/<name>, or rewrites the file with
+// -update
+func checkGolden(t *testing.T, name, got string) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run go test ./cmd -update to create it)", err)
+	}
+	if got != string(want) {
+		t.Errorf("%s changed; if that is intended, run go test ./cmd -update and bump ui.SchemaVersion for breaking changes\ngot:\n%s", path, got)
+	}
+}
+
+// TestSchemaGolden pins the JSON Schema of every output type
+func TestSchemaGolden(t *testing.T) {
+	newTestServer(t)
+	for _, typ := range outputTypes {
+		out, err := runCLI(t, "schema", typ.name)
+		if err != nil {
+			t.Fatalf("schema %s failed: %v\n%s", typ.name, err, out)
+		}
+		checkGolden(t, filepath.Join("schema", typ.name+".json"), out)
+	}
+
+	out, err := runCLI(t, "schema", "tsak")
+	if err == nil || !strings.Contains(out, `unknown output type "tsak"`) {
+		t.Errorf("expected an unknown type error, got %v:\n%s", err, out)
+	}
+}
+
+// TestEnvelopeGolden pins the shape of the --json envelopes, with every
+// value replaced by its JSON type
+func TestEnvelopeGolden(t *testing.T) {
+	srv := newTestServer(t)
+	ws := srv.AddWorkspace("Acme")
+	proj := srv.AddProject(ws.GID, "Roadmap")
+	me := srv.Me()
+	due := &asana.CustomTime{Time: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)}
+	task := srv.AddTask(proj.GID, asana.Task{Name: "Fix login", Description: "Steps inside", DueDate: due, Assignee: &asana.User{GID: me.GID, Name: me.Name}})
+	bug := srv.AddTag(ws.GID, "bug")
+	srv.TagTask(task.GID, bug.GID)
+
+	for _, c := range []struct {
+		name string
+		args []string
+	}{
+		{"list", []string{"list", proj.GID, "--json"}},
+		{"view", []string{"view", task.GID, "--json"}},
+		{"me", []string{"me", "--json"}},
+		{"project-list", []string{"project", "list", "--workspace", ws.GID, "--json"}},
+		{"error", []string{"view", "404404", "--json"}},
+	} {
+		out, _ := runCLI(t, c.args...)
+		var doc interface{}
+		if err := json.Unmarshal([]byte(out), &doc); err != nil {
+			t.Fatalf("%v: invalid JSON: %v\n%s", c.args, err, out)
+		}
+		b, err := json.MarshalIndent(jsonShape(doc), "", "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkGolden(t, filepath.Join("envelope", c.name+".json"), string(b)+"\n")
+	}
+}
+
+// jsonShape replaces every value of a decoded JSON document with its
+// type. The elements of an array merge into one, so that a field any of
+// them has shows up.
+func jsonShape(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, x := range v {
+			out[k] = jsonShape(x)
+		}
+		return out
+	case []interface{}:
+		var merged interface{}
+		for _, x := range v {
+			merged = mergeShapes(merged, jsonShape(x))
+		}
+		if merged == nil {
+			return []interface{}{}
+		}
+		return []interface{}{merged}
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	}
+	return "null"
+}
+
+func mergeShapes(a, b interface{}) interface{} {
+	am, aok := a.(map[string]interface{})
+	bm, bok := b.(map[string]interface{})
+	if aok && bok {
+		for k, v := range bm {
+			am[k] = mergeShapes(am[k], v)
+		}
+		return am
+	}
+	if a == nil || a == "null" {
+		return b
+	}
+	return a
+}
@@ -2,22 +2,109 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/TheCoolRobot/asana-cli/internal/asana"
+	"github.com/TheCoolRobot/asana-cli/internal/query"
 	"github.com/TheCoolRobot/asana-cli/internal/ui"
 )
 
+var (
+	searchLimit  int
+	searchFields string
+
+	searchAssignees      []string
+	searchProjects       []string
+	searchSections       []string
+	searchTags           []string
+	searchTagMode        string
+	searchDueBefore      string
+	searchDueAfter       string
+	searchDueOn          string
+	searchCreatedBefore  string
+	searchCreatedAfter   string
+	searchModifiedBefore string
+	searchModifiedAfter  string
+	searchCompleted      bool
+	searchSubtask        bool
+	searchHasAttachment  bool
+	searchCustom         []string
+	searchSortBy         string
+	searchAscending      bool
+	searchWhere          string
+)
+
 var searchCmd = &cobra.Command{
 	Use:   "search [workspace-id] [query]",
 	Short: "Search for tasks",
-	Args:  cobra.ExactArgs(2),
+	Long: `Search the tasks in a workspace by text and filters.
+
+The workspace defaults to --workspace, the configured default workspace or
+the current project's workspace; give it as the first of two arguments to
+override. Projects, sections and tags may be given by name or GID (section
+names are looked up in the only --projects entry, or the current project).
+
+Dates are YYYY-MM-DD, today, tomorrow, yesterday or a number of days from
+today such as +7d or -30d. Custom field filters take the forms Name=value,
+Name= (not set), Name=* (set), Name<n, Name>n and Name~text.
+
+--where further filters the results on this side, with the expression
+language described in "asana-cli list --help".
+
+Results open in the TUI when stdin and stdout are terminals, and print as a
+table otherwise (or with --no-tui).`,
+	Example: `  asana-cli search "login bug"
+  asana-cli search --assignee me --due-before +7d --completed=false
+  asana-cli search --projects Roadmap --tag bug --tag urgent --tag-mode any
+  asana-cli search --field "Priority=High" --field "Points>3" --sort-by due_date --ascending
+  asana-cli search --projects Roadmap --where "not assignee or due < today"`,
+	Annotations: map[string]string{recordOutput: "true"},
+	Args:        cobra.MaximumNArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		workspaceGID := args[0]
-		query := args[1]
-		client := asana.NewClient(token)
+		where, err := parseWhere(searchWhere)
+		if err != nil {
+			return reportError(err)
+		}
+		tui, err := useTUI()
+		if err != nil {
+			return reportError(err)
+		}
+
+		client := newClient()
+		client.SetMaxItems(searchLimit)
+
+		var workspaceGID, query string
+		switch len(args) {
+		case 2:
+			workspaceGID, query = args[0], args[1]
+		case 1:
+			query = args[0]
+		}
+		if workspaceGID == "" {
+			if workspaceGID, err = resolveWorkspace(cmd, client); err != nil {
+				return reportError(err)
+			}
+		}
 
-		tasks, err := client.Search(workspaceGID, query)
+		opts, err := searchOptions(cmd, client, workspaceGID, query)
+		if err != nil {
+			return reportError(err)
+		}
+
+		fields := asana.ParseFields(searchFields)
+		requested := fields
+		if len(fields) > 0 && where != nil {
+			requested = append(append([]string{}, fields...), where.OptFields()...)
+		}
+		if streamOutput() {
+			return streamTasks(client.IterSearchTasks(cmd.Context(), workspaceGID, opts, requested...), whereFilter(cmd, client, where), fields)
+		}
+		tasks, err := client.SearchTasks(cmd.Context(), workspaceGID, opts, requested...)
+		if err == nil {
+			tasks, err = filterWhere(cmd, client, where, tasks)
+		}
 		if err != nil {
 			if jsonOutput {
 				ui.PrintJSON(nil, err)
@@ -32,17 +119,228 @@ var searchCmd = &cobra.Command{
 			taskPtrs[i] = &tasks[i]
 		}
 
-		if jsonOutput {
+		if jsonOutput || outputFormat != "" {
 			meta := map[string]interface{}{
-				"count": len(tasks),
-				"query": query,
+				"count":        len(tasks),
+				"query":        query,
+				"workspace_id": workspaceGID,
 			}
-			ui.PrintJSONWithMeta(tasks, meta, nil)
-		} else {
+			if filters := searchFilterMeta(opts); len(filters) > 0 {
+				meta["filters"] = filters
+			}
+			if where != nil {
+				meta["where"] = where.String()
+			}
+			// Templates see whole tasks; --fields only trims the request
+			var data interface{} = tasks
+			if len(fields) > 0 && formatTemplate == nil {
+				meta["fields"] = fields
+				data = asana.SelectFields(tasks, fields)
+			}
+			if outputFormat != "" {
+				return printOutput(data, meta, ui.TaskColumns)
+			}
+			ui.PrintJSONWithMeta(data, meta, nil)
+		} else if tui {
 			// Use empty projectGID for search results since they're from multiple projects
-			ui.StartTUI(taskPtrs, client, "")
+			ui.StartTUI(cmd.Context(), taskPtrs, client, "")
+		} else {
+			return printTaskTable(tasks)
 		}
 
 		return nil
 	},
-}
\ No newline at end of file
+}
+
+// searchOptions builds the search parameters from the flags, resolving
+// names to GIDs and relative dates to YYYY-MM-DD
+func searchOptions(cmd *cobra.Command, client *asana.Client, workspaceGID, query string) (*asana.SearchOptions, error) {
+	opts := &asana.SearchOptions{
+		Text:          query,
+		Assignees:     searchAssignees,
+		SortBy:        searchSortBy,
+		SortAscending: searchAscending,
+	}
+
+	if searchSortBy != "" && !containsString(asana.SearchSortOrders, searchSortBy) {
+		return nil, fmt.Errorf("invalid --sort-by %q (orders: %s)", searchSortBy, strings.Join(asana.SearchSortOrders, ", "))
+	}
+	if searchTagMode != "all" && searchTagMode != "any" {
+		return nil, fmt.Errorf("invalid --tag-mode %q, expected all or any", searchTagMode)
+	}
+
+	for _, d := range []struct {
+		flag string
+		raw  string
+		dst  *string
+	}{
+		{"due-before", searchDueBefore, &opts.DueBefore},
+		{"due-after", searchDueAfter, &opts.DueAfter},
+		{"due-on", searchDueOn, &opts.DueOn},
+		{"created-before", searchCreatedBefore, &opts.CreatedBefore},
+		{"created-after", searchCreatedAfter, &opts.CreatedAfter},
+		{"modified-before", searchModifiedBefore, &opts.ModifiedBefore},
+		{"modified-after", searchModifiedAfter, &opts.ModifiedAfter},
+	} {
+		if d.raw == "" {
+			continue
+		}
+		date, err := parseDateArg(d.raw, time.Now())
+		if err != nil {
+			return nil, fmt.Errorf("--%s: %w", d.flag, err)
+		}
+		*d.dst = date
+	}
+
+	flags := cmd.Flags()
+	for _, b := range []struct {
+		flag  string
+		value bool
+		dst   **bool
+	}{
+		{"completed", searchCompleted, &opts.Completed},
+		{"subtask", searchSubtask, &opts.IsSubtask},
+		{"has-attachment", searchHasAttachment, &opts.HasAttachment},
+	} {
+		if flags.Changed(b.flag) {
+			value := b.value
+			*b.dst = &value
+		}
+	}
+
+	projects, err := resolveProjectRefs(cmd, client, workspaceGID, searchProjects)
+	if err != nil {
+		return nil, err
+	}
+	opts.Projects = projects
+
+	if len(searchSections) > 0 {
+		sectionProject := currentProject(cmd)
+		if len(opts.Projects) == 1 {
+			sectionProject = opts.Projects[0]
+		}
+		for _, ref := range searchSections {
+			sec, err := resolveSection(cmd.Context(), client, sectionProject, ref)
+			if err != nil {
+				return nil, err
+			}
+			opts.Sections = append(opts.Sections, sec.GID)
+		}
+	}
+
+	if len(searchTags) > 0 {
+		resolver := newTagResolver(cmd, client, false)
+		resolver.workspaceGID = workspaceGID
+		tags, err := resolver.resolve(searchTags)
+		if err != nil {
+			return nil, err
+		}
+		for _, tag := range tags {
+			if searchTagMode == "all" {
+				opts.AllTags = append(opts.AllTags, tag.GID)
+			} else {
+				opts.Tags = append(opts.Tags, tag.GID)
+			}
+		}
+	}
+
+	if len(searchCustom) > 0 {
+		defs, err := client.GetWorkspaceCustomFields(cmd.Context(), workspaceGID)
+		if err != nil {
+			return nil, err
+		}
+		for _, expr := range searchCustom {
+			f, err := asana.ParseCustomFieldFilter(defs, expr)
+			if err != nil {
+				return nil, err
+			}
+			opts.CustomFields = append(opts.CustomFields, f)
+		}
+	}
+
+	return opts, nil
+}
+
+// resolveProjectRefs finds projects in the workspace by GID or
+// case-insensitive name. A ref that matches no project but looks like a
+// GID is used as one.
+func resolveProjectRefs(cmd *cobra.Command, client *asana.Client, workspaceGID string, refs []string) ([]string, error) {
+	if len(refs) == 0 {
+		return nil, nil
+	}
+	projects, err := client.GetProjects(cmd.Context(), workspaceGID)
+	if err != nil {
+		return nil, err
+	}
+
+	gids := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		gid, err := matchProjectRef(projects, workspaceGID, ref)
+		if err != nil {
+			return nil, err
+		}
+		gids = append(gids, gid)
+	}
+	return gids, nil
+}
+
+func matchProjectRef(projects []asana.Project, workspaceGID, ref string) (string, error) {
+	for _, p := range projects {
+		if p.GID == ref {
+			return p.GID, nil
+		}
+	}
+	for _, p := range projects {
+		if strings.EqualFold(p.Name, ref) {
+			return p.GID, nil
+		}
+	}
+	if isGID(ref) {
+		return ref, nil
+	}
+	return "", fmt.Errorf("no project %q in workspace %s", ref, workspaceGID)
+}
+
+// searchFilterMeta lists the search parameters other than the text query,
+// for the JSON envelope
+func searchFilterMeta(opts *asana.SearchOptions) map[string]string {
+	filters := make(map[string]string)
+	for key, values := range opts.Values() {
+		if key != "text" {
+			filters[key] = strings.Join(values, ",")
+		}
+	}
+	return filters
+}
+
+// parseDateArg accepts YYYY-MM-DD, today, tomorrow, yesterday or an
+// offset in days such as +7d or -30d, and returns a YYYY-MM-DD date
+func parseDateArg(s string, now time.Time) (string, error) {
+	return query.ParseDate(s, now)
+}
+
+func init() {
+	searchCmd.Flags().IntVar(&searchLimit, "limit", 0, "Maximum number of results to fetch (0 = all)")
+	searchCmd.Flags().StringVar(&searchFields, "fields", "", "Comma-separated fields to request and output (e.g. name,due_on,assignee.name)")
+
+	searchCmd.Flags().StringSliceVar(&searchAssignees, "assignee", nil, "Assigned to any of these users (me or GIDs)")
+	searchCmd.Flags().StringSliceVar(&searchProjects, "projects", nil, "In any of these projects (names or GIDs)")
+	searchCmd.Flags().StringSliceVar(&searchSections, "sections", nil, "In any of these sections (names or GIDs)")
+	searchCmd.Flags().StringSliceVar(&searchTags, "tag", nil, "Tagged with these tags (names or GIDs, repeatable)")
+	searchCmd.Flags().StringVar(&searchTagMode, "tag-mode", "all", "With several --tag filters, match tasks with all of them or any of them")
+	searchCmd.Flags().StringVar(&searchDueBefore, "due-before", "", "Due before this date")
+	searchCmd.Flags().StringVar(&searchDueAfter, "due-after", "", "Due after this date")
+	searchCmd.Flags().StringVar(&searchDueOn, "due-on", "", "Due on this date")
+	searchCmd.Flags().StringVar(&searchCreatedBefore, "created-before", "", "Created before this date")
+	searchCmd.Flags().StringVar(&searchCreatedAfter, "created-after", "", "Created after this date")
+	searchCmd.Flags().StringVar(&searchModifiedBefore, "modified-before", "", "Last modified before this date")
+	searchCmd.Flags().StringVar(&searchModifiedAfter, "modified-after", "", "Last modified after this date")
+	searchCmd.Flags().BoolVar(&searchCompleted, "completed", false, "Only completed tasks (--completed=false for incomplete ones)")
+	searchCmd.Flags().BoolVar(&searchSubtask, "subtask", false, "Only subtasks (--subtask=false for top-level tasks)")
+	searchCmd.Flags().BoolVar(&searchHasAttachment, "has-attachment", false, "Only tasks with attachments (--has-attachment=false for those without)")
+	searchCmd.Flags().StringArrayVar(&searchCustom, "field", nil, "Custom field filter, e.g. \"Priority=High\" or \"Points>3\" (repeatable)")
+	searchCmd.Flags().StringVar(&searchSortBy, "sort-by", "", "Sort by modified_at (default), created_at, completed_at, due_date or likes")
+	searchCmd.Flags().BoolVar(&searchAscending, "ascending", false, "Sort in ascending order")
+	addTUIFlags(searchCmd)
+	searchCmd.Flags().StringVar(&searchWhere, "where", "", whereHelp)
+}
@@ -0,0 +1,297 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/TheCoolRobot/asana-cli/internal/asana"
+	"github.com/TheCoolRobot/asana-cli/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	sectionBefore string
+	sectionAfter  string
+)
+
+var sectionCmd = &cobra.Command{
+	Use:   "section",
+	Short: "Manage the sections of a project",
+	Long: `List, create, rename, delete and reorder the sections of a project.
+
+Sections may be given by name (case-insensitive) or GID. The project is
+taken from --project or the current project.`,
+}
+
+var sectionListCmd = &cobra.Command{
+	Use:   "list [project-id]",
+	Short: "List the sections of a project",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectGID, err := resolveProject(cmd, args)
+		if err != nil {
+			return reportError(err)
+		}
+
+		sections, err := newClient().GetSections(cmd.Context(), projectGID)
+		if err != nil {
+			return reportError(err)
+		}
+
+		if jsonOutput {
+			meta := map[string]interface{}{
+				"count":      len(sections),
+				"project_id": projectGID,
+			}
+			ui.PrintJSONWithMeta(sections, meta, nil)
+		} else if len(sections) == 0 {
+			fmt.Println("No sections")
+		} else {
+			for _, sec := range sections {
+				fmt.Printf("%-20s %s\n", sec.GID, sec.Name)
+			}
+		}
+
+		return nil
+	},
+}
+
+var sectionCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Add a section to a project",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		projectGID, err := resolveProject(cmd, nil)
+		if err != nil {
+			return reportError(err)
+		}
+		client := newClient()
+
+		req := &asana.SectionRequest{Name: args[0]}
+		if req.InsertBefore, req.InsertAfter, err = sectionAnchor(cmd.Context(), client, projectGID); err != nil {
+			return reportError(err)
+		}
+
+		sec, err := client.CreateSection(cmd.Context(), projectGID, req)
+		if err != nil {
+			return reportError(err)
+		}
+
+		if jsonOutput {
+			meta := map[string]interface{}{
+				"action":     "created",
+				"project_id": projectGID,
+			}
+			ui.PrintJSONWithMeta(sec, meta, nil)
+		} else {
+			fmt.Printf("✓ Section created: %s\n", sec.Name)
+			fmt.Printf("  GID: %s\n", sec.GID)
+		}
+
+		return nil
+	},
+}
+
+var sectionRenameCmd = &cobra.Command{
+	Use:   "rename <section> <new-name>",
+	Short: "Rename a section",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := newClient()
+		sec, err := resolveSection(cmd.Context(), client, currentProject(cmd), args[0])
+		if err != nil {
+			return reportError(err)
+		}
+
+		renamed, err := client.RenameSection(cmd.Context(), sec.GID, args[1])
+		if err != nil {
+			return reportError(err)
+		}
+
+		if jsonOutput {
+			meta := map[string]interface{}{
+				"action":   "renamed",
+				"old_name": sec.Name,
+			}
+			ui.PrintJSONWithMeta(renamed, meta, nil)
+		} else {
+			fmt.Printf("✓ Section renamed: %s → %s\n", sec.Name, renamed.Name)
+		}
+
+		return nil
+	},
+}
+
+var sectionDeleteCmd = &cobra.Command{
+	Use:   "delete <section>",
+	Short: "Delete an empty section",
+	Long:  "Delete a section. Asana only deletes sections that contain no tasks, and never a project's last section.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := newClient()
+		sec, err := resolveSection(cmd.Context(), client, currentProject(cmd), args[0])
+		if err != nil {
+			return reportError(err)
+		}
+
+		if err := client.DeleteSection(cmd.Context(), sec.GID); err != nil {
+			return reportError(err)
+		}
+
+		if jsonOutput {
+			meta := map[string]interface{}{
+				"action": "deleted",
+			}
+			ui.PrintJSONWithMeta(sec, meta, nil)
+		} else {
+			fmt.Printf("✓ Section deleted: %s\n", sec.Name)
+		}
+
+		return nil
+	},
+}
+
+var sectionReorderCmd = &cobra.Command{
+	Use:   "reorder <section> --before|--after <section>",
+	Short: "Move a section within its project",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if (sectionBefore == "") == (sectionAfter == "") {
+			return reportError(fmt.Errorf("exactly one of --before or --after is required"))
+		}
+
+		projectGID, err := resolveProject(cmd, nil)
+		if err != nil {
+			return reportError(err)
+		}
+		client := newClient()
+
+		sec, err := resolveSection(cmd.Context(), client, projectGID, args[0])
+		if err != nil {
+			return reportError(err)
+		}
+		before, after, err := sectionAnchor(cmd.Context(), client, projectGID)
+		if err != nil {
+			return reportError(err)
+		}
+
+		if err := client.MoveSection(cmd.Context(), projectGID, sec.GID, before, after); err != nil {
+			return reportError(err)
+		}
+
+		if jsonOutput {
+			meta := map[string]interface{}{
+				"action":     "reordered",
+				"project_id": projectGID,
+			}
+			if before != "" {
+				meta["before_section"] = before
+			} else {
+				meta["after_section"] = after
+			}
+			ui.PrintJSONWithMeta(sec, meta, nil)
+		} else if sectionBefore != "" {
+			fmt.Printf("✓ Moved section %s before %s\n", sec.Name, sectionBefore)
+		} else {
+			fmt.Printf("✓ Moved section %s after %s\n", sec.Name, sectionAfter)
+		}
+
+		return nil
+	},
+}
+
+// currentProject is resolveProject for commands where the project is only
+// needed to look up names, so a missing project is not an error
+func currentProject(cmd *cobra.Command) string {
+	projectGID, _ := resolveProject(cmd, nil)
+	return projectGID
+}
+
+// sectionAnchor resolves --before/--after to section GIDs
+func sectionAnchor(ctx context.Context, client *asana.Client, projectGID string) (before, after string, err error) {
+	if sectionBefore != "" {
+		sec, err := resolveSection(ctx, client, projectGID, sectionBefore)
+		if err != nil {
+			return "", "", err
+		}
+		before = sec.GID
+	}
+	if sectionAfter != "" {
+		sec, err := resolveSection(ctx, client, projectGID, sectionAfter)
+		if err != nil {
+			return "", "", err
+		}
+		after = sec.GID
+	}
+	return before, after, nil
+}
+
+// resolveSection finds a section of a project by GID or case-insensitive
+// name. Without a project, a numeric ref is taken to be a GID as is.
+func resolveSection(ctx context.Context, client *asana.Client, projectGID, ref string) (*asana.Section, error) {
+	if projectGID == "" {
+		if isGID(ref) {
+			return &asana.Section{GID: ref, Name: ref}, nil
+		}
+		return nil, fmt.Errorf("section %q given by name, but no project ID provided and no current project set", ref)
+	}
+
+	sections, err := client.GetSections(ctx, projectGID)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []asana.Section
+	names := make([]string, 0, len(sections))
+	for _, sec := range sections {
+		if sec.GID == ref {
+			return &sec, nil
+		}
+		if strings.EqualFold(sec.Name, ref) {
+			matches = append(matches, sec)
+		}
+		names = append(names, sec.Name)
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no section %q in project %s (sections: %s)", ref, projectGID, strings.Join(names, ", "))
+	case 1:
+		return &matches[0], nil
+	default:
+		gids := make([]string, len(matches))
+		for i, sec := range matches {
+			gids[i] = sec.GID
+		}
+		return nil, fmt.Errorf("section name %q is ambiguous, use a GID: %s", ref, strings.Join(gids, ", "))
+	}
+}
+
+// isGID reports whether s looks like an Asana GID, i.e. is all digits
+func isGID(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func init() {
+	sectionCreateCmd.Flags().StringVar(&sectionBefore, "before", "", "Insert before this section (name or GID)")
+	sectionCreateCmd.Flags().StringVar(&sectionAfter, "after", "", "Insert after this section (name or GID)")
+	sectionCreateCmd.MarkFlagsMutuallyExclusive("before", "after")
+	sectionReorderCmd.Flags().StringVar(&sectionBefore, "before", "", "Move before this section (name or GID)")
+	sectionReorderCmd.Flags().StringVar(&sectionAfter, "after", "", "Move after this section (name or GID)")
+	sectionReorderCmd.MarkFlagsMutuallyExclusive("before", "after")
+
+	sectionCmd.AddCommand(sectionListCmd)
+	sectionCmd.AddCommand(sectionCreateCmd)
+	sectionCmd.AddCommand(sectionRenameCmd)
+	sectionCmd.AddCommand(sectionDeleteCmd)
+	sectionCmd.AddCommand(sectionReorderCmd)
+}
@@ -0,0 +1,231 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/TheCoolRobot/asana-cli/internal/asana"
+	"github.com/TheCoolRobot/asana-cli/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// maxSubtaskDepth is how deep subtask trees are fetched. Asana allows
+// subtasks to nest five levels deep.
+const maxSubtaskDepth = 5
+
+var (
+	subtaskName        string
+	subtaskDescription string
+	subtaskAssignee    string
+	subtaskDueDate     string
+
+	subtaskRecursive bool
+
+	subtaskParent   string
+	subtaskTopLevel bool
+	subtaskBefore   string
+	subtaskAfter    string
+)
+
+// subtaskNode is a task with its subtasks filled in, as rendered by
+// `subtask list --recursive` and `view`
+type subtaskNode struct {
+	asana.Task
+	Subtasks []subtaskNode `json:"subtasks,omitempty"`
+}
+
+var subtaskCmd = &cobra.Command{
+	Use:   "subtask",
+	Short: "Manage subtasks",
+	Long:  "Add, list and move subtasks",
+}
+
+var subtaskAddCmd = &cobra.Command{
+	Use:   "add [parent-id]",
+	Short: "Add a subtask to a task",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		parentGID := args[0]
+		client := newClient()
+
+		req := &asana.TaskCreateRequest{
+			Name:        subtaskName,
+			Description: subtaskDescription,
+			Parent:      parentGID,
+			Assignee:    subtaskAssignee,
+			DueOn:       subtaskDueDate,
+		}
+
+		task, err := client.CreateTask(cmd.Context(), req)
+		if err != nil {
+			return reportError(err)
+		}
+
+		if jsonOutput {
+			meta := map[string]interface{}{
+				"action":     "created",
+				"parent_gid": parentGID,
+			}
+			ui.PrintJSONWithMeta(task, meta, nil)
+		} else {
+			fmt.Printf("✓ Subtask created: %s\n", task.Name)
+			fmt.Printf("  GID: %s\n", task.GID)
+		}
+
+		return nil
+	},
+}
+
+var subtaskListCmd = &cobra.Command{
+	Use:   "list [parent-id]",
+	Short: "List the subtasks of a task",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		parentGID := args[0]
+		client := newClient()
+
+		depth := 1
+		if subtaskRecursive {
+			depth = maxSubtaskDepth
+		}
+		tree, err := fetchSubtaskTree(cmd.Context(), client, parentGID, depth)
+		if err != nil {
+			return reportError(err)
+		}
+
+		done, total := subtaskCounts(tree)
+		if jsonOutput {
+			meta := map[string]interface{}{
+				"parent_gid": parentGID,
+				"count":      total,
+				"completed":  done,
+			}
+			ui.PrintJSONWithMeta(tree, meta, nil)
+		} else if total == 0 {
+			fmt.Println("No subtasks")
+		} else {
+			fmt.Printf("Subtasks (%d/%d complete):\n", done, total)
+			printSubtaskTree(tree, "  ")
+		}
+
+		return nil
+	},
+}
+
+var subtaskMoveCmd = &cobra.Command{
+	Use:   "move [task-id]",
+	Short: "Move a task under another parent, or back to the top level",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		taskGID := args[0]
+
+		if (subtaskParent == "") == !subtaskTopLevel {
+			return reportError(errors.New("specify exactly one of --parent or --top-level"))
+		}
+		if subtaskBefore != "" && subtaskAfter != "" {
+			return reportError(errors.New("--before and --after cannot be used together"))
+		}
+
+		client := newClient()
+		task, err := client.SetParent(cmd.Context(), taskGID, &asana.SetParentRequest{
+			Parent:       subtaskParent,
+			InsertBefore: subtaskBefore,
+			InsertAfter:  subtaskAfter,
+		})
+		if err != nil {
+			return reportError(err)
+		}
+
+		if jsonOutput {
+			meta := map[string]interface{}{
+				"action":     "moved",
+				"task_gid":   taskGID,
+				"parent_gid": subtaskParent,
+			}
+			ui.PrintJSONWithMeta(task, meta, nil)
+		} else if subtaskParent == "" {
+			fmt.Printf("✓ %s is now a top-level task\n", task.Name)
+		} else {
+			fmt.Printf("✓ %s moved under %s\n", task.Name, subtaskParent)
+		}
+
+		return nil
+	},
+}
+
+// fetchSubtaskTree loads a task's subtasks, descending into those that have
+// subtasks of their own until depth levels have been fetched
+func fetchSubtaskTree(ctx context.Context, client *asana.Client, taskGID string, depth int) ([]subtaskNode, error) {
+	subtasks, err := client.GetSubtasks(ctx, taskGID)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make([]subtaskNode, len(subtasks))
+	for i, sub := range subtasks {
+		nodes[i].Task = sub
+		if depth > 1 && sub.NumSubtasks > 0 {
+			nodes[i].Subtasks, err = fetchSubtaskTree(ctx, client, sub.GID, depth-1)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return nodes, nil
+}
+
+// subtaskCounts returns how many of the given subtasks are complete
+func subtaskCounts(nodes []subtaskNode) (done, total int) {
+	for _, n := range nodes {
+		if n.Completed {
+			done++
+		}
+	}
+	return done, len(nodes)
+}
+
+// printSubtaskTree draws nodes as an indented tree, with the completion
+// count of each subtask that has subtasks of its own
+func printSubtaskTree(nodes []subtaskNode, indent string) {
+	for i, n := range nodes {
+		branch, next := "├── ", "│   "
+		if i == len(nodes)-1 {
+			branch, next = "└── ", "    "
+		}
+
+		mark := "○"
+		if n.Completed {
+			mark = "✓"
+		}
+		fmt.Printf("%s%s%s %s", indent, branch, mark, n.Name)
+		if len(n.Subtasks) > 0 {
+			done, total := subtaskCounts(n.Subtasks)
+			fmt.Printf(" (%d/%d)", done, total)
+		} else if n.NumSubtasks > 0 {
+			fmt.Printf(" (+%d)", n.NumSubtasks)
+		}
+		fmt.Printf("  [%s]\n", n.GID)
+
+		printSubtaskTree(n.Subtasks, indent+next)
+	}
+}
+
+func init() {
+	subtaskAddCmd.Flags().StringVar(&subtaskName, "name", "", "Subtask name (required)")
+	subtaskAddCmd.Flags().StringVar(&subtaskDescription, "description", "", "Subtask description")
+	subtaskAddCmd.Flags().StringVar(&subtaskAssignee, "assignee", "", "Assignee user GID")
+	subtaskAddCmd.Flags().StringVar(&subtaskDueDate, "due", "", "Due date (YYYY-MM-DD)")
+	subtaskAddCmd.MarkFlagRequired("name")
+
+	subtaskListCmd.Flags().BoolVarP(&subtaskRecursive, "recursive", "r", false, "Include nested subtasks")
+
+	subtaskMoveCmd.Flags().StringVar(&subtaskParent, "parent", "", "GID of the new parent task")
+	subtaskMoveCmd.Flags().BoolVar(&subtaskTopLevel, "top-level", false, "Make the task a top-level task again")
+	subtaskMoveCmd.Flags().StringVar(&subtaskBefore, "before", "", "Place it before this sibling subtask")
+	subtaskMoveCmd.Flags().StringVar(&subtaskAfter, "after", "", "Place it after this sibling subtask")
+
+	subtaskCmd.AddCommand(subtaskAddCmd)
+	subtaskCmd.AddCommand(subtaskListCmd)
+	subtaskCmd.AddCommand(subtaskMoveCmd)
+}
@@ -3,8 +3,6 @@ package cmd
 import (
 	"fmt"
 	"log"
-	"os"
-	"os/signal"
 	"strings"
 
 	"github.com/TheCoolRobot/asana-cli/internal/syncdaemon"
@@ -27,19 +25,11 @@ var syncCmd = &cobra.Command{
 			projectIDs[i] = strings.TrimSpace(projectIDs[i])
 		}
 
-		daemon := syncdaemon.NewDaemon(token, projectIDs)
+		daemon := syncdaemon.NewDaemon(newClient(), projectIDs)
 
-		// Handle Ctrl+C gracefully
-		sigChan := make(chan os.Signal, 1)
-		signal.Notify(sigChan, os.Interrupt)
-
-		go func() {
-			<-sigChan
-			fmt.Println("\n[sync-daemon] Received interrupt signal")
-			daemon.Stop()
-		}()
-
-		daemon.Start()
+		// The command context is cancelled on Ctrl+C (or when --timeout
+		// expires), which stops the daemon gracefully
+		daemon.Start(cmd.Context())
 		return nil
 	},
 }
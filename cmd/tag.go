@@ -0,0 +1,440 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/TheCoolRobot/asana-cli/internal/asana"
+	"github.com/TheCoolRobot/asana-cli/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	tagColor  string
+	tagNotes  string
+	tagCreate bool
+)
+
+var tagCmd = &cobra.Command{
+	Use:   "tag",
+	Short: "Manage tags and tag tasks",
+	Long: `List, create, rename and delete the tags in a workspace, and add them to
+or remove them from tasks.
+
+Tags may be given by name (case-insensitive) or GID. Names are looked up in
+--workspace, the configured default workspace or the current project's
+workspace.`,
+}
+
+var tagListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the tags in a workspace",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := newClient()
+		workspaceGID, err := resolveWorkspace(cmd, client)
+		if err != nil {
+			return reportError(err)
+		}
+
+		tags, err := client.GetTags(cmd.Context(), workspaceGID)
+		if err != nil {
+			return reportError(err)
+		}
+
+		if jsonOutput {
+			meta := map[string]interface{}{
+				"count":        len(tags),
+				"workspace_id": workspaceGID,
+			}
+			ui.PrintJSONWithMeta(tags, meta, nil)
+		} else if len(tags) == 0 {
+			fmt.Println("No tags")
+		} else {
+			for _, tag := range tags {
+				if tag.Color != "" && tag.Color != "none" {
+					fmt.Printf("%-20s %s (%s)\n", tag.GID, tag.Name, tag.Color)
+				} else {
+					fmt.Printf("%-20s %s\n", tag.GID, tag.Name)
+				}
+			}
+		}
+
+		return nil
+	},
+}
+
+var tagCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create a tag",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := validateTagColor(); err != nil {
+			return reportError(err)
+		}
+
+		client := newClient()
+		workspaceGID, err := resolveWorkspace(cmd, client)
+		if err != nil {
+			return reportError(err)
+		}
+
+		tag, err := client.CreateTag(cmd.Context(), &asana.TagRequest{
+			Name:      args[0],
+			Color:     tagColor,
+			Notes:     tagNotes,
+			Workspace: workspaceGID,
+		})
+		if err != nil {
+			return reportError(err)
+		}
+
+		if jsonOutput {
+			meta := map[string]interface{}{
+				"action":       "created",
+				"workspace_id": workspaceGID,
+			}
+			ui.PrintJSONWithMeta(tag, meta, nil)
+		} else {
+			fmt.Printf("✓ Tag created: %s\n", tag.Name)
+			fmt.Printf("  GID: %s\n", tag.GID)
+		}
+
+		return nil
+	},
+}
+
+var tagRenameCmd = &cobra.Command{
+	Use:   "rename <tag> <new-name>",
+	Short: "Rename a tag",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := validateTagColor(); err != nil {
+			return reportError(err)
+		}
+
+		client := newClient()
+		tags, err := newTagResolver(cmd, client, false).resolve(args[:1])
+		if err != nil {
+			return reportError(err)
+		}
+
+		renamed, err := client.UpdateTag(cmd.Context(), tags[0].GID, &asana.TagRequest{Name: args[1], Color: tagColor})
+		if err != nil {
+			return reportError(err)
+		}
+
+		if jsonOutput {
+			meta := map[string]interface{}{
+				"action":   "renamed",
+				"old_name": tags[0].Name,
+			}
+			ui.PrintJSONWithMeta(renamed, meta, nil)
+		} else {
+			fmt.Printf("✓ Tag renamed: %s → %s\n", tags[0].Name, renamed.Name)
+		}
+
+		return nil
+	},
+}
+
+var tagDeleteCmd = &cobra.Command{
+	Use:   "delete <tag>",
+	Short: "Delete a tag, removing it from every task",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := newClient()
+		tags, err := newTagResolver(cmd, client, false).resolve(args)
+		if err != nil {
+			return reportError(err)
+		}
+
+		if err := client.DeleteTag(cmd.Context(), tags[0].GID); err != nil {
+			return reportError(err)
+		}
+
+		if jsonOutput {
+			ui.PrintJSONWithMeta(tags[0], map[string]interface{}{"action": "deleted"}, nil)
+		} else {
+			fmt.Printf("✓ Tag deleted: %s\n", tags[0].Name)
+		}
+
+		return nil
+	},
+}
+
+var tagAddCmd = &cobra.Command{
+	Use:   "add [task-id] <tag...>",
+	Short: "Add tags to tasks",
+	Long: `Add tags to a task. With --create, tags that don't exist yet are created first.
+
+With --stdin the tasks are read from standard input, one GID per line or
+NDJSON records, and every argument is a tag. They are tagged concurrently
+and a summary of each is printed; the exit code is non-zero if any failed.
+More than 5 tasks are tagged through the Batch API, 10 actions to a request.`,
+	Example: `  asana-cli tag add 1201 bug urgent
+  asana-cli list --ndjson --where "overdue" | asana-cli tag add --stdin late`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if bulkStdin {
+			return cobra.MinimumNArgs(1)(cmd, args)
+		}
+		return cobra.MinimumNArgs(2)(cmd, args)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if bulkStdin {
+			return tagTasks(cmd, args)
+		}
+		return changeTaskTags(cmd, args[0], args[1:], true)
+	},
+}
+
+var tagRemoveCmd = &cobra.Command{
+	Use:   "remove [task-id] <tag...>",
+	Short: "Remove tags from a task",
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return changeTaskTags(cmd, args[0], args[1:], false)
+	},
+}
+
+func changeTaskTags(cmd *cobra.Command, taskGID string, refs []string, add bool) error {
+	client := newClient()
+	resolver := newTagResolver(cmd, client, add && tagCreate)
+	tags, err := resolver.resolve(refs)
+	if err != nil {
+		return reportError(err)
+	}
+
+	for _, tag := range tags {
+		if add {
+			err = client.AddTag(cmd.Context(), taskGID, tag.GID)
+		} else {
+			err = client.RemoveTag(cmd.Context(), taskGID, tag.GID)
+		}
+		if err != nil {
+			return reportError(err)
+		}
+	}
+
+	action, message := "tags_added", fmt.Sprintf("Tagged task %s with %s", taskGID, tagNames(tags))
+	if !add {
+		action, message = "tags_removed", fmt.Sprintf("Removed %s from task %s", tagNames(tags), taskGID)
+	}
+	if jsonOutput {
+		meta := map[string]interface{}{
+			"action":   action,
+			"task_gid": taskGID,
+		}
+		if len(resolver.created) > 0 {
+			meta["created_tags"] = resolver.created
+		}
+		ui.PrintJSONWithMeta(map[string]interface{}{"tags": tags}, meta, nil)
+	} else {
+		for _, tag := range resolver.created {
+			fmt.Printf("✓ Tag created: %s\n", tag.Name)
+		}
+		fmt.Printf("✓ %s\n", message)
+	}
+	return nil
+}
+
+// tagTasks adds tags to the tasks read from stdin
+func tagTasks(cmd *cobra.Command, refs []string) error {
+	gids, err := taskGIDs(cmd, nil)
+	if err != nil {
+		return reportError(err)
+	}
+	client := newClient()
+	resolver := newTagResolver(cmd, client, tagCreate)
+	tags, err := resolver.resolve(refs)
+	if err != nil {
+		return reportError(err)
+	}
+
+	results := runBulk(cmd.Context(), client, gids, bulkOp{
+		call: func(ctx context.Context, gid string) (string, error) {
+			for _, tag := range tags {
+				if err := client.AddTag(ctx, gid, tag.GID); err != nil {
+					return "", err
+				}
+			}
+			return "", nil
+		},
+		actions: func(gid string) ([]asana.BatchAction, error) {
+			actions := make([]asana.BatchAction, len(tags))
+			for i, tag := range tags {
+				actions[i] = asana.AddTagAction(gid, tag.GID)
+			}
+			return actions, nil
+		},
+	})
+
+	meta := map[string]interface{}{"tags": tags}
+	if len(resolver.created) > 0 {
+		meta["created_tags"] = resolver.created
+	}
+	if !jsonOutput {
+		for _, tag := range resolver.created {
+			fmt.Printf("✓ Tag created: %s\n", tag.Name)
+		}
+	}
+	return printBulk("tagged", results, meta)
+}
+
+// tagResolver turns tag names or GIDs into tags, fetching the workspace's
+// tags at most once. With create set, unknown names become new tags.
+type tagResolver struct {
+	cmd     *cobra.Command
+	client  *asana.Client
+	create  bool
+	loaded  bool
+	tags    []asana.Tag
+	created []asana.Tag
+
+	workspaceGID string
+}
+
+func newTagResolver(cmd *cobra.Command, client *asana.Client, create bool) *tagResolver {
+	return &tagResolver{cmd: cmd, client: client, create: create}
+}
+
+func (r *tagResolver) resolve(refs []string) ([]asana.Tag, error) {
+	tags := make([]asana.Tag, 0, len(refs))
+	for _, ref := range refs {
+		tag, err := r.find(ref)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, *tag)
+	}
+	return tags, nil
+}
+
+// find looks ref up among the workspace's tags by GID or name. A ref that
+// matches no tag but looks like a GID is used as one, so tags outside the
+// workspace still work, unless create makes it a new tag's name.
+func (r *tagResolver) find(ref string) (*asana.Tag, error) {
+	if err := r.load(); err != nil {
+		if isGID(ref) {
+			return &asana.Tag{GID: ref, Name: ref}, nil
+		}
+		return nil, err
+	}
+
+	for i, tag := range r.tags {
+		if tag.GID == ref {
+			return &r.tags[i], nil
+		}
+	}
+	var matches []asana.Tag
+	for _, tag := range r.tags {
+		if strings.EqualFold(tag.Name, ref) {
+			matches = append(matches, tag)
+		}
+	}
+	switch {
+	case len(matches) == 1:
+		return &matches[0], nil
+	case len(matches) > 1:
+		gids := make([]string, len(matches))
+		for i, tag := range matches {
+			gids[i] = tag.GID
+		}
+		return nil, fmt.Errorf("tag name %q is ambiguous, use a GID: %s", ref, strings.Join(gids, ", "))
+	case r.create:
+		return r.createTag(ref)
+	case isGID(ref):
+		return &asana.Tag{GID: ref, Name: ref}, nil
+	}
+	return nil, fmt.Errorf("no tag %q in workspace %s", ref, r.workspaceGID)
+}
+
+// load fetches the workspace's tags the first time it's called
+func (r *tagResolver) load() error {
+	if r.loaded {
+		return nil
+	}
+	var err error
+	if r.workspaceGID == "" {
+		if r.workspaceGID, err = resolveWorkspace(r.cmd, r.client); err != nil {
+			return err
+		}
+	}
+	if r.tags, err = r.client.GetTags(r.cmd.Context(), r.workspaceGID); err != nil {
+		return err
+	}
+	r.loaded = true
+	return nil
+}
+
+func (r *tagResolver) createTag(name string) (*asana.Tag, error) {
+	tag, err := r.client.CreateTag(r.cmd.Context(), &asana.TagRequest{Name: name, Workspace: r.workspaceGID})
+	if err != nil {
+		return nil, err
+	}
+	if dryRun {
+		// The tag was not created, so later requests name it instead
+		tag = &asana.Tag{GID: fmt.Sprintf("<new tag %q>", name), Name: name}
+	}
+	r.tags = append(r.tags, *tag)
+	r.created = append(r.created, *tag)
+	return tag, nil
+}
+
+// filterByTags keeps the tasks carrying every tag (or, with matchAny set,
+// at least one of them). Tags are matched by case-insensitive name or GID.
+func filterByTags(tasks []asana.Task, refs []string, matchAny bool) []asana.Task {
+	kept := tasks[:0]
+	for _, t := range tasks {
+		matched := 0
+		for _, ref := range refs {
+			if hasTag(&t, ref) {
+				matched++
+			}
+		}
+		if (matchAny && matched > 0) || (!matchAny && matched == len(refs)) {
+			kept = append(kept, t)
+		}
+	}
+	return kept
+}
+
+func hasTag(t *asana.Task, ref string) bool {
+	for _, tag := range t.Tags {
+		if tag.GID == ref || strings.EqualFold(tag.Name, ref) {
+			return true
+		}
+	}
+	return false
+}
+
+func tagNames(tags []asana.Tag) string {
+	names := make([]string, len(tags))
+	for i, tag := range tags {
+		names[i] = tag.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+func validateTagColor() error {
+	if tagColor != "" && !containsString(asana.ProjectColors, tagColor) {
+		return fmt.Errorf("invalid color %q (colors: %s)", tagColor, strings.Join(asana.ProjectColors, ", "))
+	}
+	return nil
+}
+
+func init() {
+	tagCreateCmd.Flags().StringVar(&tagColor, "color", "", "Color, e.g. dark-blue or light-green")
+	tagCreateCmd.Flags().StringVar(&tagNotes, "notes", "", "Tag description")
+	tagRenameCmd.Flags().StringVar(&tagColor, "color", "", "Also change the color")
+	tagAddCmd.Flags().BoolVar(&tagCreate, "create", false, "Create tags that don't exist yet")
+	addBulkFlags(tagAddCmd)
+
+	tagCmd.AddCommand(tagListCmd)
+	tagCmd.AddCommand(tagCreateCmd)
+	tagCmd.AddCommand(tagRenameCmd)
+	tagCmd.AddCommand(tagDeleteCmd)
+	tagCmd.AddCommand(tagAddCmd)
+	tagCmd.AddCommand(tagRemoveCmd)
+}
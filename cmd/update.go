@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/spf13/cobra"
@@ -14,25 +15,33 @@ var (
 	updateAssignee    string
 	updateDueDate     string
 	updatePriority    string
+	updateFields      []string
 )
 
 var updateCmd = &cobra.Command{
-	Use:   "update [task-id]",
-	Short: "Update a task",
-	Args:  cobra.ExactArgs(1),
+	Use:   "update [task-id...]",
+	Short: "Update tasks",
+	Long: `Update one or more tasks with the same changes.
+
+With several GIDs or --stdin the tasks are updated concurrently and a
+summary of each is printed; the exit code is non-zero if any failed. More
+than 5 tasks are updated through the Batch API, 10 to a request.`,
+	Example: `  asana-cli update 1201 --name "Ship it" --due 2026-05-01
+  asana-cli update 1201 1202 1203 --assignee 1100 --priority high`,
+	Args: taskGIDArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		taskGID := args[0]
-		client := asana.NewClient(token)
-
-		req := &asana.TaskUpdateRequest{
-			Name:        updateName,
-			Description: updateDescription,
-			Assignee:    updateAssignee,
-			DueOn:       updateDueDate,
-			Priority:    updatePriority,
+		gids, err := taskGIDs(cmd, args)
+		if err != nil {
+			return reportError(err)
+		}
+		client := newClient()
+
+		if bulkMode(args) {
+			return updateTasks(cmd.Context(), client, gids)
 		}
 
-		task, err := client.UpdateTask(taskGID, req)
+		taskGID := gids[0]
+		task, err := updateTask(cmd.Context(), client, taskGID)
 		if err != nil {
 			if jsonOutput {
 				ui.PrintJSON(nil, err)
@@ -56,10 +65,110 @@ var updateCmd = &cobra.Command{
 	},
 }
 
+// updateTasks applies the update flags to many tasks and prints a summary
+func updateTasks(ctx context.Context, client *asana.Client, gids []string) error {
+	// Above batchThreshold the custom fields are looked up in batches too
+	var defs map[string][]asana.CustomField
+	var defErrs map[string]error
+	op := bulkOp{
+		call: func(ctx context.Context, gid string) (string, error) {
+			task, err := updateTask(ctx, client, gid)
+			if err != nil {
+				return "", err
+			}
+			return task.Name, nil
+		},
+		actions: func(gid string) ([]asana.BatchAction, error) {
+			if err := defErrs[gid]; err != nil {
+				return nil, err
+			}
+			req, err := updateRequest(defs[gid])
+			if err != nil {
+				return nil, err
+			}
+			return []asana.BatchAction{asana.UpdateTaskAction(gid, req)}, nil
+		},
+	}
+	if setsCustomFields() {
+		op.prepare = func(ctx context.Context, gids []string) {
+			defs, defErrs = batchTaskCustomFields(ctx, client, gids)
+		}
+	}
+	return printBulk("updated", runBulk(ctx, client, gids, op), nil)
+}
+
+// updateTask applies the update flags to one task
+func updateTask(ctx context.Context, client *asana.Client, taskGID string) (*asana.Task, error) {
+	// Custom fields are set by GID, resolved from the task's own fields
+	var defs []asana.CustomField
+	if setsCustomFields() {
+		var err error
+		if defs, err = client.GetTaskCustomFields(ctx, taskGID); err != nil {
+			return nil, err
+		}
+	}
+
+	req, err := updateRequest(defs)
+	if err != nil {
+		return nil, err
+	}
+	return client.UpdateTask(ctx, taskGID, req)
+}
+
+// setsCustomFields reports whether the update flags set any custom field
+func setsCustomFields() bool {
+	return len(customFieldArgs(updateFields, updatePriority)) > 0
+}
+
+// updateRequest builds the request the update flags describe. defs are the
+// task's custom field definitions, which are only needed to set them.
+func updateRequest(defs []asana.CustomField) (*asana.TaskUpdateRequest, error) {
+	req := &asana.TaskUpdateRequest{
+		Name:        updateName,
+		Description: updateDescription,
+		Assignee:    updateAssignee,
+		DueOn:       updateDueDate,
+	}
+
+	if assignments := customFieldArgs(updateFields, updatePriority); len(assignments) > 0 {
+		var err error
+		if req.CustomFields, err = asana.ResolveCustomFields(defs, assignments); err != nil {
+			return nil, err
+		}
+	}
+	return req, nil
+}
+
+// batchTaskCustomFields gets the custom field definitions of many tasks
+// through the Batch API, with the error for each task it could not get
+func batchTaskCustomFields(ctx context.Context, client *asana.Client, gids []string) (map[string][]asana.CustomField, map[string]error) {
+	actions := make([]asana.BatchAction, len(gids))
+	for i, gid := range gids {
+		actions[i] = asana.GetTaskAction(gid, asana.PrefixFields("custom_fields", asana.DefaultCustomFieldFields)...)
+	}
+	results, _ := client.Batch(ctx, actions)
+
+	defs := make(map[string][]asana.CustomField, len(gids))
+	errs := make(map[string]error)
+	for i, r := range results {
+		task, err := r.Task()
+		if err != nil {
+			errs[gids[i]] = err
+			continue
+		}
+		for _, f := range task.CustomFields {
+			defs[gids[i]] = append(defs[gids[i]], f.CustomField)
+		}
+	}
+	return defs, errs
+}
+
 func init() {
 	updateCmd.Flags().StringVar(&updateName, "name", "", "New task name")
 	updateCmd.Flags().StringVar(&updateDescription, "description", "", "New task description")
 	updateCmd.Flags().StringVar(&updateAssignee, "assignee", "", "New assignee user GID")
 	updateCmd.Flags().StringVar(&updateDueDate, "due", "", "New due date (YYYY-MM-DD)")
-	updateCmd.Flags().StringVar(&updatePriority, "priority", "", "New priority (1=high, 2=medium, 3=low)")
+	updateCmd.Flags().StringVar(&updatePriority, "priority", "", "New priority, i.e. the task's \"Priority\" custom field (e.g. high)")
+	updateCmd.Flags().StringArrayVar(&updateFields, "field", nil, "Set a custom field, e.g. \"Story Points=5\"; \"Name=\" clears it (repeatable)")
+	addBulkFlags(updateCmd)
 }
\ No newline at end of file
@@ -2,21 +2,59 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/TheCoolRobot/asana-cli/internal/asana"
 	"github.com/TheCoolRobot/asana-cli/internal/ui"
 )
 
+var viewFields string
+
+// taskDetail is the --json form of view: the task plus its subtask tree
+// and activity feed
+type taskDetail struct {
+	*asana.Task
+	Subtasks []subtaskNode `json:"subtasks"`
+	Stories  []asana.Story `json:"stories"`
+}
+
+// viewColumns shows more of the task than a list does by default, one
+// field per line
+var viewColumns = ui.Columns{
+	Available: ui.TaskColumns.Available,
+	Default:   []string{"gid", "name", "completed", "due", "assignee", "projects", "sections", "tags", "parent", "subtasks", "notes"},
+}
+
 var viewCmd = &cobra.Command{
-	Use:   "view [task-id]",
-	Short: "View task details",
-	Args:  cobra.ExactArgs(1),
+	Use:         "view [task-id]",
+	Short:       "View task details",
+	Annotations: map[string]string{recordOutput: "true"},
+	Args:        cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		taskGID := args[0]
-		client := asana.NewClient(token)
+		client := newClient()
+
+		fields := asana.ParseFields(viewFields)
+		taskFields, withSubtasks, withStories := splitDetailFields(fields)
+		task, err := client.GetTask(cmd.Context(), taskGID, taskFields...)
+		if err == nil && len(fields) > 0 && len(taskFields) == 0 {
+			// Only subtask or story fields were asked for; the task itself is
+			// just its gid
+			task = &asana.Task{GID: task.GID}
+		}
+
+		// num_subtasks saves a request for the common case of no subtasks,
+		// but is only known when the default fields were requested
+		var subtasks []subtaskNode
+		if err == nil && withSubtasks && (len(fields) > 0 || task.NumSubtasks > 0) {
+			subtasks, err = fetchSubtaskTree(cmd.Context(), client, taskGID, maxSubtaskDepth)
+		}
 
-		task, err := client.GetTask(taskGID)
+		var stories []asana.Story
+		if err == nil && withStories {
+			stories, err = client.GetStories(cmd.Context(), taskGID)
+		}
 		if err != nil {
 			if jsonOutput {
 				ui.PrintJSON(nil, err)
@@ -26,8 +64,24 @@ var viewCmd = &cobra.Command{
 			return err
 		}
 
-		if jsonOutput {
-			ui.PrintJSON(task, nil)
+		if jsonOutput || outputFormat != "" {
+			detail := taskDetail{Task: task, Subtasks: subtasks, Stories: stories}
+			if detail.Subtasks == nil {
+				detail.Subtasks = []subtaskNode{}
+			}
+			if detail.Stories == nil {
+				detail.Stories = []asana.Story{}
+			}
+			switch {
+			case outputFormat != "" && len(fields) > 0 && formatTemplate == nil:
+				return printOutput(asana.SelectFields(detail, fields), map[string]interface{}{"fields": fields}, viewColumns)
+			case outputFormat != "":
+				return printOutput(detail, nil, viewColumns)
+			case len(fields) > 0:
+				ui.PrintJSONWithMeta(asana.SelectFields(detail, fields), map[string]interface{}{"fields": fields}, nil)
+			default:
+				ui.PrintJSON(detail, nil)
+			}
 		} else {
 			fmt.Printf("📋 %s\n", task.Name)
 			fmt.Printf("   GID: %s\n", task.GID)
@@ -41,6 +95,11 @@ var viewCmd = &cobra.Command{
 			if task.Assignee != nil {
 				fmt.Printf("   Assigned to: %s\n", task.Assignee.Name)
 			}
+			for _, m := range task.Memberships {
+				if m.Project != nil && m.Section != nil {
+					fmt.Printf("   Section: %s (%s)\n", m.Section.Name, m.Project.Name)
+				}
+			}
 			if len(task.Tags) > 0 {
 				fmt.Printf("   Tags: ")
 				for i, tag := range task.Tags {
@@ -51,8 +110,85 @@ var viewCmd = &cobra.Command{
 				}
 				fmt.Printf("\n")
 			}
+			for i := range task.CustomFields {
+				if f := &task.CustomFields[i]; f.IsSet() {
+					fmt.Printf("   %s: %s\n", f.Name, f.String())
+				}
+			}
+			if len(task.Dependencies) > 0 {
+				fmt.Printf("   Blocked by: %s\n", taskNames(task.Dependencies))
+			}
+			if len(task.Dependents) > 0 {
+				fmt.Printf("   Blocking: %s\n", taskNames(task.Dependents))
+			}
+			if task.Parent != nil {
+				fmt.Printf("   Parent: %s (%s)\n", task.Parent.Name, task.Parent.GID)
+			}
+			if len(subtasks) > 0 {
+				done, total := subtaskCounts(subtasks)
+				fmt.Printf("\n   Subtasks (%d/%d complete):\n", done, total)
+				printSubtaskTree(subtasks, "   ")
+			}
+			if len(stories) > 0 {
+				fmt.Printf("\n   Activity:\n")
+				for _, story := range stories {
+					printStory(story)
+				}
+			}
 		}
 
 		return nil
 	},
+}
+
+// splitDetailFields separates the "subtasks" and "stories" entries from
+// the task fields passed to --fields. Without --fields both are always
+// fetched; with it, only when asked for.
+func splitDetailFields(fields []string) (taskFields []string, withSubtasks, withStories bool) {
+	if len(fields) == 0 {
+		return nil, true, true
+	}
+	for _, f := range fields {
+		switch {
+		case f == "subtasks" || strings.HasPrefix(f, "subtasks."):
+			withSubtasks = true
+		case f == "stories" || strings.HasPrefix(f, "stories."):
+			withStories = true
+		default:
+			taskFields = append(taskFields, f)
+		}
+	}
+	return taskFields, withSubtasks, withStories
+}
+
+// taskNames lists tasks as "Name (gid)" for one-line display
+func taskNames(tasks []asana.Task) string {
+	names := make([]string, len(tasks))
+	for i, t := range tasks {
+		names[i] = fmt.Sprintf("%s (%s)", t.Name, t.GID)
+	}
+	return strings.Join(names, ", ")
+}
+
+// printStory prints one timeline entry. Comments are shown in full under
+// their author; system events on a single line.
+func printStory(story asana.Story) {
+	author := "Asana"
+	if story.CreatedBy != nil && story.CreatedBy.Name != "" {
+		author = story.CreatedBy.Name
+	}
+	when := story.CreatedAt.Local().Format("2006-01-02 15:04")
+
+	if story.IsComment() {
+		fmt.Printf("   💬 %s  %s:\n", when, author)
+		for _, line := range strings.Split(story.Text, "\n") {
+			fmt.Printf("      %s\n", line)
+		}
+	} else {
+		fmt.Printf("   • %s  %s %s\n", when, author, story.Text)
+	}
+}
+
+func init() {
+	viewCmd.Flags().StringVar(&viewFields, "fields", "", "Comma-separated fields to request and output (e.g. name,notes,assignee.name,subtasks,stories)")
 }
\ No newline at end of file
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/TheCoolRobot/asana-cli/internal/asana"
+	"github.com/TheCoolRobot/asana-cli/internal/query"
+	"github.com/spf13/cobra"
+)
+
+// whereHelp documents --where for the commands that take it
+const whereHelp = `Filter with an expression, e.g. "due < today and assignee = me and not completed and tag in (backend, infra)"`
+
+// parseWhere compiles a --where expression, or returns nil if there is none
+func parseWhere(expr string) (*query.Query, error) {
+	if expr == "" {
+		return nil, nil
+	}
+	return query.Parse(expr)
+}
+
+// filterWhere keeps the tasks matching q, looking up the current user
+// only if the expression mentions "me"
+func filterWhere(cmd *cobra.Command, client *asana.Client, q *query.Query, tasks []asana.Task) ([]asana.Task, error) {
+	return whereFilter(cmd, client, q)(tasks)
+}
+
+// whereFilter is filterWhere for tasks that arrive in batches, such as
+// streamed pages: the current user is looked up once, on first use
+func whereFilter(cmd *cobra.Command, client *asana.Client, q *query.Query) func([]asana.Task) ([]asana.Task, error) {
+	var env *query.Env
+	return func(tasks []asana.Task) ([]asana.Task, error) {
+		if q == nil {
+			return tasks, nil
+		}
+		if env == nil {
+			e := query.Env{Now: time.Now()}
+			if q.UsesMe() {
+				me, err := client.GetMe(cmd.Context())
+				if err != nil {
+					return nil, err
+				}
+				e.Me = me.GID
+			}
+			env = &e
+		}
+		return q.Filter(tasks, *env), nil
+	}
+}
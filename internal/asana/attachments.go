@@ -0,0 +1,145 @@
+package asana
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"path/filepath"
+	"strings"
+)
+
+// MaxAttachmentSize is the largest file Asana accepts as an attachment
+const MaxAttachmentSize = 100 << 20
+
+// ErrAttachmentTooLarge is returned for uploads or downloads over the
+// allowed size
+var ErrAttachmentTooLarge = errors.New("attachment too large")
+
+// GetAttachments lists the files attached to a task
+// GET /tasks/{task_gid}/attachments
+func (c *Client) GetAttachments(ctx context.Context, taskGID string) ([]Attachment, error) {
+	endpoint := fmt.Sprintf("/tasks/%s/attachments", taskGID)
+	return collect(newIterator[Attachment](ctx, c, endpoint, setFields(nil, nil, DefaultAttachmentFields)))
+}
+
+// GetAttachment retrieves a single attachment, including a fresh
+// DownloadURL
+// GET /attachments/{attachment_gid}
+func (c *Client) GetAttachment(ctx context.Context, attachmentGID string) (*Attachment, error) {
+	endpoint := withFields(fmt.Sprintf("/attachments/%s", attachmentGID), nil, DefaultAttachmentFields)
+	body, err := c.do(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Data *Attachment `json:"data"`
+	}
+
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, err
+	}
+
+	return response.Data, nil
+}
+
+// UploadAttachment attaches the contents of r to a task as a file called
+// name. Its content type is detected from the name and the data itself.
+// Content over MaxAttachmentSize is rejected with ErrAttachmentTooLarge.
+// POST /tasks/{task_gid}/attachments
+func (c *Client) UploadAttachment(ctx context.Context, taskGID, name string, r io.Reader) (*Attachment, error) {
+	data, err := io.ReadAll(io.LimitReader(r, MaxAttachmentSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MaxAttachmentSize {
+		return nil, fmt.Errorf("%s: %w (limit is %d MB)", name, ErrAttachmentTooLarge, MaxAttachmentSize>>20)
+	}
+
+	var buf bytes.Buffer
+	form := multipart.NewWriter(&buf)
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename="%s"`, escapeQuotes(filepath.Base(name))))
+	header.Set("Content-Type", DetectContentType(name, data))
+	part, err := form.CreatePart(header)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := part.Write(data); err != nil {
+		return nil, err
+	}
+	if err := form.Close(); err != nil {
+		return nil, err
+	}
+
+	endpoint := withFields(fmt.Sprintf("/tasks/%s/attachments", taskGID), nil, DefaultAttachmentFields)
+	body, err := c.doRaw(ctx, "POST", endpoint, form.FormDataContentType(), buf.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Data *Attachment `json:"data"`
+	}
+
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, err
+	}
+
+	return response.Data, nil
+}
+
+// DownloadAttachment streams an attachment's contents to w, returning the
+// content type reported by the file host. Download URLs are pre-signed, so
+// no credentials are sent with the request.
+func (c *Client) DownloadAttachment(ctx context.Context, att *Attachment, w io.Writer) (string, error) {
+	if att.DownloadURL == "" {
+		return "", fmt.Errorf("attachment %s (%s) is hosted on %s and cannot be downloaded, open %s instead", att.GID, att.Name, att.Host, att.ViewURL)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", att.DownloadURL, nil)
+	if err != nil {
+		return "", err
+	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+
+	// Files can take far longer than an API call, so only the context
+	// bounds the transfer, not the client's per-request timeout
+	downloader := &http.Client{Transport: c.http.Transport}
+	resp, err := downloader.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("downloading %s: %s", att.Name, resp.Status)
+	}
+
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return "", err
+	}
+	return resp.Header.Get("Content-Type"), nil
+}
+
+// DetectContentType guesses a file's MIME type from its extension, falling
+// back to sniffing its first bytes
+func DetectContentType(name string, data []byte) string {
+	if ct := mime.TypeByExtension(filepath.Ext(name)); ct != "" {
+		return ct
+	}
+	return http.DetectContentType(data)
+}
+
+func escapeQuotes(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s)
+}
@@ -0,0 +1,196 @@
+package asana
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// MaxBatchActions is the most actions Asana accepts in one batch request
+const MaxBatchActions = 10
+
+// BatchAction is one API call sent as part of a batch. The *Action
+// functions build the actions of the client methods of the same name.
+type BatchAction struct {
+	// Method is the lower-case HTTP method, e.g. "put"
+	Method string `json:"method"`
+	// RelativePath is the endpoint without the API root or query
+	RelativePath string        `json:"relative_path"`
+	Data         interface{}   `json:"data,omitempty"`
+	Options      *BatchOptions `json:"options,omitempty"`
+}
+
+// BatchOptions are the query options of a batch action
+type BatchOptions struct {
+	Fields []string `json:"fields,omitempty"`
+}
+
+// BatchResult is the outcome of one BatchAction
+type BatchResult struct {
+	// StatusCode is the HTTP status of the action, or zero if the batch
+	// request that carried it failed
+	StatusCode int
+	// Data is the "data" of the action's response
+	Data json.RawMessage
+	// Err is an *APIError for a failed action, or the error of the batch
+	// request that carried it
+	Err error
+}
+
+// Decode unmarshals the action's response data into v, or returns the
+// action's error
+func (r BatchResult) Decode(v interface{}) error {
+	if r.Err != nil {
+		return r.Err
+	}
+	if len(r.Data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(r.Data, v)
+}
+
+// Task returns the task an action such as UpdateTaskAction responded with
+func (r BatchResult) Task() (*Task, error) {
+	var task Task
+	if err := r.Decode(&task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// GetTaskAction is the batch form of GetTask
+func GetTaskAction(taskGID string, fields ...string) BatchAction {
+	if len(fields) == 0 {
+		fields = DefaultTaskFields
+	}
+	return BatchAction{
+		Method:       "get",
+		RelativePath: fmt.Sprintf("/tasks/%s", taskGID),
+		Options:      &BatchOptions{Fields: fields},
+	}
+}
+
+// UpdateTaskAction is the batch form of UpdateTask
+func UpdateTaskAction(taskGID string, req *TaskUpdateRequest) BatchAction {
+	return BatchAction{
+		Method:       "put",
+		RelativePath: fmt.Sprintf("/tasks/%s", taskGID),
+		Data:         req,
+		Options:      &BatchOptions{Fields: DefaultTaskFields},
+	}
+}
+
+// CompleteTaskAction is the batch form of CompleteTask
+func CompleteTaskAction(taskGID string) BatchAction {
+	completed := true
+	return UpdateTaskAction(taskGID, &TaskUpdateRequest{Completed: &completed})
+}
+
+// AddTagAction is the batch form of AddTag
+func AddTagAction(taskGID, tagGID string) BatchAction {
+	return BatchAction{
+		Method:       "post",
+		RelativePath: fmt.Sprintf("/tasks/%s/addTag", taskGID),
+		Data:         map[string]string{"tag": tagGID},
+	}
+}
+
+// AddTaskToSectionAction is the batch form of AddTaskToSection
+func AddTaskToSectionAction(sectionGID, taskGID, insertBefore, insertAfter string) BatchAction {
+	return BatchAction{
+		Method:       "post",
+		RelativePath: fmt.Sprintf("/sections/%s/addTask", sectionGID),
+		Data:         sectionTaskData(taskGID, insertBefore, insertAfter),
+	}
+}
+
+// Batch sends actions through the Batch API, MaxBatchActions per request,
+// and returns one result per action in the same order. The actions of a
+// request may run in any order. A failed action, or one whose request
+// failed, has its error in Err; the returned error is the first failed
+// request, if any.
+// POST /batch
+func (c *Client) Batch(ctx context.Context, actions []BatchAction) ([]BatchResult, error) {
+	results := make([]BatchResult, len(actions))
+	var firstErr error
+	for start := 0; start < len(actions); start += MaxBatchActions {
+		end := min(start+MaxBatchActions, len(actions))
+		if err := c.batch(ctx, actions[start:end], results[start:end]); err != nil {
+			for i := start; i < end; i++ {
+				results[i] = BatchResult{Err: err}
+			}
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return results, firstErr
+}
+
+// readOnly reports whether a batch only reads
+func readOnly(actions []BatchAction) bool {
+	for _, action := range actions {
+		if action.Method != "get" {
+			return false
+		}
+	}
+	return true
+}
+
+// batch sends one batch request and fills in results
+func (c *Client) batch(ctx context.Context, actions []BatchAction, results []BatchResult) error {
+	payload, err := json.Marshal(map[string]interface{}{
+		"data": map[string]interface{}{"actions": actions},
+	})
+	if err != nil {
+		return err
+	}
+
+	var body []byte
+	if c.dryRun != nil && !readOnly(actions) {
+		c.skip("POST", "/batch", "application/json", payload)
+		for i := range results {
+			results[i] = BatchResult{StatusCode: http.StatusOK}
+		}
+		return nil
+	}
+	// A batch of reads changes nothing, so even a dry run sends it. A
+	// rate-limited batch ran none of its actions and is safe to resend.
+	if body, err = c.doRetry(ctx, "POST", "/batch", "application/json", payload, true); err != nil {
+		return err
+	}
+
+	var response struct {
+		Data []struct {
+			StatusCode int             `json:"status_code"`
+			Body       json.RawMessage `json:"body"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return err
+	}
+	if len(response.Data) != len(actions) {
+		return fmt.Errorf("batch returned %d results for %d actions", len(response.Data), len(actions))
+	}
+
+	for i, r := range response.Data {
+		results[i].StatusCode = r.StatusCode
+		if r.StatusCode < 200 || r.StatusCode >= 300 {
+			results[i].Err = newAPIError(strings.ToUpper(actions[i].Method), actions[i].RelativePath, r.StatusCode, r.Body)
+			continue
+		}
+		var doc struct {
+			Data json.RawMessage `json:"data"`
+		}
+		if len(r.Body) > 0 {
+			if err := json.Unmarshal(r.Body, &doc); err != nil {
+				results[i].Err = err
+				continue
+			}
+		}
+		results[i].Data = doc.Data
+	}
+	return nil
+}
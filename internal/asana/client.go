@@ -2,77 +2,194 @@ package asana
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
 	"net/url"
 	"os"
-	
+	"time"
 )
+// DefaultRequestTimeout bounds a single HTTP round trip so that a hung
+// connection cannot block a caller that did not set its own deadline
+const DefaultRequestTimeout = 60 * time.Second
+
 // "strings"
 type Client struct {
-	apiToken string
-	baseURL  string
-	http     *http.Client
+	apiToken  string
+	baseURL   string
+	http      *http.Client
+	userAgent string
+	logger    *log.Logger
+	pageSize int
+	maxItems int
+	retry    RetryPolicy
+	limiter  *limiter
+	dryRun   func(PlannedRequest)
 }
 
-func NewClient(apiToken string) *Client {
+// NewClient creates an API client. An empty token falls back to the
+// ASANA_TOKEN environment variable.
+func NewClient(apiToken string, opts ...Option) *Client {
 	if apiToken == "" {
 		apiToken = os.Getenv("ASANA_TOKEN")
 	}
 
-	return &Client{
-		apiToken: apiToken,
-		baseURL:  "https://app.asana.com/api/1.0",
-		http:     &http.Client{},
+	c := &Client{
+		apiToken:  apiToken,
+		baseURL:   DefaultBaseURL,
+		http:      &http.Client{Timeout: DefaultRequestTimeout},
+		userAgent: DefaultUserAgent,
+		pageSize:  DefaultPageSize,
+		retry:     DefaultRetryPolicy,
+		limiter:   newLimiter(DefaultMaxConcurrent, 0),
+	}
+
+	for _, opt := range opts {
+		opt(c)
 	}
+
+	return c
 }
 
-func (c *Client) do(method, endpoint string, body interface{}) ([]byte, error) {
-	if c.apiToken == "" {
-		return nil, fmt.Errorf("ASANA_TOKEN not set")
+// SetRetryPolicy replaces the client's retry policy. A policy with
+// MaxRetries of zero disables retries entirely.
+func (c *Client) SetRetryPolicy(p RetryPolicy) {
+	c.retry = p
+}
+
+// SetRateLimit bounds how many requests may be in flight at once and how
+// many may be started per minute. Zero disables the respective limit.
+func (c *Client) SetRateLimit(maxConcurrent, perMinute int) {
+	c.limiter = newLimiter(maxConcurrent, perMinute)
+}
+
+// SetPageSize sets how many records are requested per page on list
+// endpoints. Values outside 1..MaxPageSize are clamped.
+func (c *Client) SetPageSize(n int) {
+	if n < 1 {
+		n = 1
+	}
+	if n > MaxPageSize {
+		n = MaxPageSize
 	}
+	c.pageSize = n
+}
 
-	var reqBody io.Reader
+// SetMaxItems caps the number of records returned by list methods and
+// iterators. Zero (the default) means no cap.
+func (c *Client) SetMaxItems(n int) {
+	if n < 0 {
+		n = 0
+	}
+	c.maxItems = n
+}
+
+func (c *Client) do(ctx context.Context, method, endpoint string, body interface{}) ([]byte, error) {
+	var payload []byte
 	if body != nil {
 		jsonBody, err := json.Marshal(body)
 		if err != nil {
 			return nil, err
 		}
-		reqBody = bytes.NewBuffer(jsonBody)
+		payload = jsonBody
+	}
+
+	return c.doRaw(ctx, method, endpoint, "application/json", payload)
+}
+
+// doRaw sends a pre-encoded payload of the given content type, with the
+// same retry handling as do. A dry-run client skips everything but GETs.
+func (c *Client) doRaw(ctx context.Context, method, endpoint, contentType string, payload []byte) ([]byte, error) {
+	if c.dryRun != nil && method != http.MethodGet {
+		return c.skip(method, endpoint, contentType, payload), nil
+	}
+	return c.doRetry(ctx, method, endpoint, contentType, payload, false)
+}
+
+// doRetry sends a request, retrying it as the client's policy allows.
+// rateLimited also retries a POST that was turned away with a 429.
+func (c *Client) doRetry(ctx context.Context, method, endpoint, contentType string, payload []byte, rateLimited bool) ([]byte, error) {
+	if c.apiToken == "" {
+		return nil, ErrNoToken
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, respBody, err := c.send(ctx, method, endpoint, contentType, payload)
+		if err == nil && resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			return respBody, nil
+		}
+
+		status := 0
+		var header http.Header
+		if err == nil {
+			status = resp.StatusCode
+			header = resp.Header
+		}
+
+		if attempt >= c.retry.MaxRetries || ctx.Err() != nil || !shouldRetry(method, status, err, rateLimited) {
+			if err != nil {
+				return nil, err
+			}
+			return nil, newAPIError(method, endpoint, status, respBody)
+		}
+
+		if err := sleepContext(ctx, c.retry.backoff(attempt, header)); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// send performs a single HTTP round trip, waiting on the client's rate
+// limiter first
+func (c *Client) send(ctx context.Context, method, endpoint, contentType string, payload []byte) (*http.Response, []byte, error) {
+	release, err := c.limiter.acquire(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer release()
+
+	var reqBody io.Reader
+	if payload != nil {
+		reqBody = bytes.NewReader(payload)
 	}
 
 	url := c.baseURL + endpoint
-	req, err := http.NewRequest(method, url, reqBody)
+	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	req.Header.Add("Authorization", "Bearer "+c.apiToken)
-	req.Header.Add("Content-Type", "application/json")
+	req.Header.Add("Content-Type", contentType)
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+
+	c.traceRequest(req, payload)
+	start := time.Now()
 
 	resp, err := c.http.Do(req)
 	if err != nil {
-		return nil, err
+		c.traceResponse(req, nil, nil, err, time.Since(start))
+		return nil, nil, err
 	}
 	defer resp.Body.Close()
 
 	respBody, err := io.ReadAll(resp.Body)
+	c.traceResponse(req, resp, respBody, err, time.Since(start))
 	if err != nil {
-		return nil, err
-	}
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("API error (%d): %s", resp.StatusCode, string(respBody))
+		return nil, nil, err
 	}
 
-	return respBody, nil
+	return resp, respBody, nil
 }
 
 // GetMe retrieves current user info
-func (c *Client) GetMe() (*User, error) {
-	body, err := c.do("GET", "/users/me", nil)
+func (c *Client) GetMe(ctx context.Context) (*User, error) {
+	body, err := c.do(ctx, "GET", withFields("/users/me", nil, DefaultUserFields), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -89,8 +206,8 @@ func (c *Client) GetMe() (*User, error) {
 }
 
 // GetWorkspaces retrieves all workspaces
-func (c *Client) GetWorkspaces() ([]Workspace, error) {
-	body, err := c.do("GET", "/workspaces", nil)
+func (c *Client) GetWorkspaces(ctx context.Context) ([]Workspace, error) {
+	body, err := c.do(ctx, "GET", "/workspaces", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -106,16 +223,83 @@ func (c *Client) GetWorkspaces() ([]Workspace, error) {
 	return response.Data, nil
 }
 
-// GetProjects retrieves projects in a workspace
-func (c *Client) GetProjects(workspaceGID string) ([]Project, error) {
-	endpoint := fmt.Sprintf("/projects?workspace=%s", workspaceGID)
-	body, err := c.do("GET", endpoint, nil)
+// GetProjects retrieves every project in a workspace, following pagination
+func (c *Client) GetProjects(ctx context.Context, workspaceGID string) ([]Project, error) {
+	return collect(c.IterProjects(ctx, workspaceGID))
+}
+
+// IterProjects streams the projects in a workspace page by page
+// GET /projects?workspace={workspace_gid}
+func (c *Client) IterProjects(ctx context.Context, workspaceGID string) *Iterator[Project] {
+	return c.IterProjectsFiltered(ctx, workspaceGID, nil)
+}
+
+// GetProjectsFiltered retrieves the projects in a workspace matching filters
+// Supports filters: archived, team, opt_fields
+func (c *Client) GetProjectsFiltered(ctx context.Context, workspaceGID string, filters map[string]string) ([]Project, error) {
+	return collect(c.IterProjectsFiltered(ctx, workspaceGID, filters))
+}
+
+// IterProjectsFiltered streams the projects in a workspace matching filters
+// GET /projects?workspace={workspace_gid}
+func (c *Client) IterProjectsFiltered(ctx context.Context, workspaceGID string, filters map[string]string) *Iterator[Project] {
+	q := filterValues(filters)
+	q.Set("workspace", workspaceGID)
+	return newIterator[Project](ctx, c, "/projects", setFields(q, nil, DefaultProjectFields))
+}
+
+// GetProject retrieves a project
+// GET /projects/{project_gid}
+func (c *Client) GetProject(ctx context.Context, projectGID string) (*Project, error) {
+	endpoint := withFields(fmt.Sprintf("/projects/%s", projectGID), nil, DefaultProjectFields)
+	return c.projectRequest(ctx, "GET", endpoint, nil)
+}
+
+// CreateProject creates a project in a workspace, or in a team when
+// req.Team is set
+// POST /projects
+func (c *Client) CreateProject(ctx context.Context, req *ProjectCreateRequest) (*Project, error) {
+	return c.projectRequest(ctx, "POST", withFields("/projects", nil, DefaultProjectFields), req)
+}
+
+// UpdateProject updates a project
+// PUT /projects/{project_gid}
+func (c *Client) UpdateProject(ctx context.Context, projectGID string, req *ProjectUpdateRequest) (*Project, error) {
+	endpoint := withFields(fmt.Sprintf("/projects/%s", projectGID), nil, DefaultProjectFields)
+	return c.projectRequest(ctx, "PUT", endpoint, req)
+}
+
+// ArchiveProject archives or, with archived false, unarchives a project
+// Calls UpdateProject with archived set
+func (c *Client) ArchiveProject(ctx context.Context, projectGID string, archived bool) (*Project, error) {
+	return c.UpdateProject(ctx, projectGID, &ProjectUpdateRequest{
+		Archived: &archived,
+	})
+}
+
+// DeleteProject deletes a project. Its tasks are kept if they belong to
+// other projects.
+// DELETE /projects/{project_gid}
+func (c *Client) DeleteProject(ctx context.Context, projectGID string) error {
+	_, err := c.do(ctx, "DELETE", fmt.Sprintf("/projects/%s", projectGID), nil)
+	return err
+}
+
+func (c *Client) projectRequest(ctx context.Context, method, endpoint string, req interface{}) (*Project, error) {
+	var payload interface{}
+	if req != nil {
+		payload = map[string]interface{}{
+			"data": req,
+		}
+	}
+
+	body, err := c.do(ctx, method, endpoint, payload)
 	if err != nil {
 		return nil, err
 	}
 
 	var response struct {
-		Data []Project `json:"data"`
+		Data *Project `json:"data"`
 	}
 
 	if err := json.Unmarshal(body, &response); err != nil {
@@ -125,26 +309,61 @@ func (c *Client) GetProjects(workspaceGID string) ([]Project, error) {
 	return response.Data, nil
 }
 
-// GetTasks retrieves tasks from a project with optional filters
-// Supports filters: completed_since, assignee, modified_since, etc.
-func (c *Client) GetTasks(projectGID string, filters map[string]string) ([]Task, error) {
-	endpoint := fmt.Sprintf("/projects/%s/tasks", projectGID)
-	
-	if len(filters) > 0 {
-		q := url.Values{}
-		for k, v := range filters {
-			q.Add(k, v)
+// DuplicateProject starts copying a project. Asana duplicates projects in
+// the background; the returned job's NewProject is the copy, which is
+// complete once WaitForJob reports success.
+// POST /projects/{project_gid}/duplicate
+func (c *Client) DuplicateProject(ctx context.Context, projectGID string, req *ProjectDuplicateRequest) (*Job, error) {
+	endpoint := withFields(fmt.Sprintf("/projects/%s/duplicate", projectGID), nil, DefaultJobFields)
+	payload := map[string]interface{}{
+		"data": req,
+	}
+	return c.jobRequest(ctx, "POST", endpoint, payload)
+}
+
+// GetJob retrieves the status of an asynchronous job
+// GET /jobs/{job_gid}
+func (c *Client) GetJob(ctx context.Context, jobGID string) (*Job, error) {
+	endpoint := withFields(fmt.Sprintf("/jobs/%s", jobGID), nil, DefaultJobFields)
+	return c.jobRequest(ctx, "GET", endpoint, nil)
+}
+
+// WaitForJob polls a job every interval until it succeeds or fails, or ctx
+// is done. A failed job is returned along with an error.
+func (c *Client) WaitForJob(ctx context.Context, job *Job, interval time.Duration) (*Job, error) {
+	if c.dryRun != nil && job.GID == "" {
+		// The request that would have started the job was skipped
+		return job, nil
+	}
+	for {
+		switch job.Status {
+		case JobSucceeded:
+			return job, nil
+		case JobFailed:
+			return job, fmt.Errorf("job %s failed", job.GID)
+		}
+
+		select {
+		case <-ctx.Done():
+			return job, ctx.Err()
+		case <-time.After(interval):
+		}
+
+		var err error
+		if job, err = c.GetJob(ctx, job.GID); err != nil {
+			return nil, err
 		}
-		endpoint += "?" + q.Encode()
 	}
+}
 
-	body, err := c.do("GET", endpoint, nil)
+func (c *Client) jobRequest(ctx context.Context, method, endpoint string, payload interface{}) (*Job, error) {
+	body, err := c.do(ctx, method, endpoint, payload)
 	if err != nil {
 		return nil, err
 	}
 
 	var response struct {
-		Data []Task `json:"data"`
+		Data *Job `json:"data"`
 	}
 
 	if err := json.Unmarshal(body, &response); err != nil {
@@ -154,11 +373,25 @@ func (c *Client) GetTasks(projectGID string, filters map[string]string) ([]Task,
 	return response.Data, nil
 }
 
-// GetTask retrieves a specific task using task GID
+// GetTasks retrieves every task in a project with optional filters
+// Supports filters: completed_since, assignee, modified_since, opt_fields, etc.
+func (c *Client) GetTasks(ctx context.Context, projectGID string, filters map[string]string) ([]Task, error) {
+	return collect(c.IterTasks(ctx, projectGID, filters))
+}
+
+// IterTasks streams the tasks in a project page by page
+// GET /projects/{project_gid}/tasks
+func (c *Client) IterTasks(ctx context.Context, projectGID string, filters map[string]string) *Iterator[Task] {
+	endpoint := fmt.Sprintf("/projects/%s/tasks", projectGID)
+	return newIterator[Task](ctx, c, endpoint, setFields(filterValues(filters), nil, DefaultTaskFields))
+}
+
+// GetTask retrieves a specific task using task GID. If no fields are
+// given, DefaultTaskFields are requested.
 // GET /tasks/{task_gid}
-func (c *Client) GetTask(taskGID string) (*Task, error) {
-	endpoint := fmt.Sprintf("/tasks/%s", taskGID)
-	body, err := c.do("GET", endpoint, nil)
+func (c *Client) GetTask(ctx context.Context, taskGID string, fields ...string) (*Task, error) {
+	endpoint := withFields(fmt.Sprintf("/tasks/%s", taskGID), fields, DefaultTaskFields)
+	body, err := c.do(ctx, "GET", endpoint, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -176,12 +409,12 @@ func (c *Client) GetTask(taskGID string) (*Task, error) {
 
 // CreateTask creates a new task
 // POST /tasks
-func (c *Client) CreateTask(req *TaskCreateRequest) (*Task, error) {
+func (c *Client) CreateTask(ctx context.Context, req *TaskCreateRequest) (*Task, error) {
 	payload := map[string]interface{}{
 		"data": req,
 	}
 
-	body, err := c.do("POST", "/tasks", payload)
+	body, err := c.do(ctx, "POST", withFields("/tasks", nil, DefaultTaskFields), payload)
 	if err != nil {
 		return nil, err
 	}
@@ -199,13 +432,13 @@ func (c *Client) CreateTask(req *TaskCreateRequest) (*Task, error) {
 
 // UpdateTask updates a task using task GID
 // PUT /tasks/{task_gid}
-func (c *Client) UpdateTask(taskGID string, req *TaskUpdateRequest) (*Task, error) {
-	endpoint := fmt.Sprintf("/tasks/%s", taskGID)
+func (c *Client) UpdateTask(ctx context.Context, taskGID string, req *TaskUpdateRequest) (*Task, error) {
+	endpoint := withFields(fmt.Sprintf("/tasks/%s", taskGID), nil, DefaultTaskFields)
 	payload := map[string]interface{}{
 		"data": req,
 	}
 
-	body, err := c.do("PUT", endpoint, payload)
+	body, err := c.do(ctx, "PUT", endpoint, payload)
 	if err != nil {
 		return nil, err
 	}
@@ -223,31 +456,212 @@ func (c *Client) UpdateTask(taskGID string, req *TaskUpdateRequest) (*Task, erro
 
 // CompleteTask marks a task as complete
 // Calls UpdateTask with completed: true
-func (c *Client) CompleteTask(taskGID string) (*Task, error) {
+func (c *Client) CompleteTask(ctx context.Context, taskGID string) (*Task, error) {
 	completed := true
-	return c.UpdateTask(taskGID, &TaskUpdateRequest{
+	return c.UpdateTask(ctx, taskGID, &TaskUpdateRequest{
 		Completed: &completed,
 	})
 }
 
 // DeleteTask deletes a task
 // DELETE /tasks/{task_gid}
-func (c *Client) DeleteTask(taskGID string) error {
+func (c *Client) DeleteTask(ctx context.Context, taskGID string) error {
 	endpoint := fmt.Sprintf("/tasks/%s", taskGID)
-	_, err := c.do("DELETE", endpoint, nil)
+	_, err := c.do(ctx, "DELETE", endpoint, nil)
+	return err
+}
+
+// GetCustomFieldSettings retrieves the custom fields enabled on a project
+// GET /projects/{project_gid}/custom_field_settings
+func (c *Client) GetCustomFieldSettings(ctx context.Context, projectGID string) ([]CustomField, error) {
+	type setting struct {
+		CustomField CustomField `json:"custom_field"`
+	}
+
+	endpoint := fmt.Sprintf("/projects/%s/custom_field_settings", projectGID)
+	query := setFields(nil, nil, PrefixFields("custom_field", DefaultCustomFieldFields))
+	settings, err := collect(newIterator[setting](ctx, c, endpoint, query))
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make([]CustomField, len(settings))
+	for i, s := range settings {
+		fields[i] = s.CustomField
+	}
+	return fields, nil
+}
+
+// GetTaskCustomFields retrieves the definitions of the custom fields on a
+// task, i.e. those of the projects it belongs to
+func (c *Client) GetTaskCustomFields(ctx context.Context, taskGID string) ([]CustomField, error) {
+	task, err := c.GetTask(ctx, taskGID, PrefixFields("custom_fields", DefaultCustomFieldFields)...)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make([]CustomField, len(task.CustomFields))
+	for i, f := range task.CustomFields {
+		fields[i] = f.CustomField
+	}
+	return fields, nil
+}
+
+// GetSubtasks retrieves a task's direct subtasks, in their display order
+func (c *Client) GetSubtasks(ctx context.Context, taskGID string, fields ...string) ([]Task, error) {
+	return collect(c.IterSubtasks(ctx, taskGID, fields...))
+}
+
+// IterSubtasks streams a task's direct subtasks page by page
+// GET /tasks/{task_gid}/subtasks
+func (c *Client) IterSubtasks(ctx context.Context, taskGID string, fields ...string) *Iterator[Task] {
+	endpoint := fmt.Sprintf("/tasks/%s/subtasks", taskGID)
+	return newIterator[Task](ctx, c, endpoint, setFields(nil, fields, DefaultTaskFields))
+}
+
+// SetParent makes a task a subtask of another, or a top-level task again
+// when req.Parent is empty
+// POST /tasks/{task_gid}/setParent
+func (c *Client) SetParent(ctx context.Context, taskGID string, req *SetParentRequest) (*Task, error) {
+	data := map[string]interface{}{"parent": nil}
+	if req.Parent != "" {
+		data["parent"] = req.Parent
+	}
+	if req.InsertBefore != "" {
+		data["insert_before"] = req.InsertBefore
+	}
+	if req.InsertAfter != "" {
+		data["insert_after"] = req.InsertAfter
+	}
+	payload := map[string]interface{}{
+		"data": data,
+	}
+
+	endpoint := withFields(fmt.Sprintf("/tasks/%s/setParent", taskGID), nil, DefaultTaskFields)
+	body, err := c.do(ctx, "POST", endpoint, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Data *Task `json:"data"`
+	}
+
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, err
+	}
+
+	return response.Data, nil
+}
+
+// GetDependencies retrieves the tasks a task is blocked by
+// GET /tasks/{task_gid}/dependencies
+func (c *Client) GetDependencies(ctx context.Context, taskGID string) ([]Task, error) {
+	endpoint := fmt.Sprintf("/tasks/%s/dependencies", taskGID)
+	return collect(newIterator[Task](ctx, c, endpoint, setFields(nil, nil, CompactTaskFields)))
+}
+
+// GetDependents retrieves the tasks blocked by a task
+// GET /tasks/{task_gid}/dependents
+func (c *Client) GetDependents(ctx context.Context, taskGID string) ([]Task, error) {
+	endpoint := fmt.Sprintf("/tasks/%s/dependents", taskGID)
+	return collect(newIterator[Task](ctx, c, endpoint, setFields(nil, nil, CompactTaskFields)))
+}
+
+// AddDependencies marks a task as blocked by each of dependencyGIDs
+// POST /tasks/{task_gid}/addDependencies
+func (c *Client) AddDependencies(ctx context.Context, taskGID string, dependencyGIDs []string) error {
+	return c.changeDependencies(ctx, taskGID, "addDependencies", dependencyGIDs)
+}
+
+// RemoveDependencies unblocks a task from each of dependencyGIDs
+// POST /tasks/{task_gid}/removeDependencies
+func (c *Client) RemoveDependencies(ctx context.Context, taskGID string, dependencyGIDs []string) error {
+	return c.changeDependencies(ctx, taskGID, "removeDependencies", dependencyGIDs)
+}
+
+func (c *Client) changeDependencies(ctx context.Context, taskGID, action string, dependencyGIDs []string) error {
+	payload := map[string]interface{}{
+		"data": map[string][]string{"dependencies": dependencyGIDs},
+	}
+	_, err := c.do(ctx, "POST", fmt.Sprintf("/tasks/%s/%s", taskGID, action), payload)
 	return err
 }
 
-// GetSections retrieves sections in a project
-func (c *Client) GetSections(projectGID string) ([]Section, error) {
+// GetStories retrieves a task's full activity feed, oldest first
+func (c *Client) GetStories(ctx context.Context, taskGID string) ([]Story, error) {
+	return collect(c.IterStories(ctx, taskGID))
+}
+
+// IterStories streams a task's comments and activity page by page
+// GET /tasks/{task_gid}/stories
+func (c *Client) IterStories(ctx context.Context, taskGID string) *Iterator[Story] {
+	endpoint := fmt.Sprintf("/tasks/%s/stories", taskGID)
+	return newIterator[Story](ctx, c, endpoint, setFields(nil, nil, DefaultStoryFields))
+}
+
+// AddComment posts a comment on a task
+// POST /tasks/{task_gid}/stories
+func (c *Client) AddComment(ctx context.Context, taskGID, text string) (*Story, error) {
+	endpoint := withFields(fmt.Sprintf("/tasks/%s/stories", taskGID), nil, DefaultStoryFields)
+	payload := map[string]interface{}{
+		"data": map[string]string{"text": text},
+	}
+
+	body, err := c.do(ctx, "POST", endpoint, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Data *Story `json:"data"`
+	}
+
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, err
+	}
+
+	return response.Data, nil
+}
+
+// GetSections retrieves every section in a project
+func (c *Client) GetSections(ctx context.Context, projectGID string) ([]Section, error) {
+	return collect(c.IterSections(ctx, projectGID))
+}
+
+// IterSections streams the sections in a project page by page
+// GET /projects/{project_gid}/sections
+func (c *Client) IterSections(ctx context.Context, projectGID string) *Iterator[Section] {
 	endpoint := fmt.Sprintf("/projects/%s/sections", projectGID)
-	body, err := c.do("GET", endpoint, nil)
+	return newIterator[Section](ctx, c, endpoint, setFields(nil, nil, DefaultSectionFields))
+}
+
+// CreateSection adds a section to a project
+// POST /projects/{project_gid}/sections
+func (c *Client) CreateSection(ctx context.Context, projectGID string, req *SectionRequest) (*Section, error) {
+	endpoint := withFields(fmt.Sprintf("/projects/%s/sections", projectGID), nil, DefaultSectionFields)
+	return c.sectionRequest(ctx, "POST", endpoint, req)
+}
+
+// RenameSection changes a section's name
+// PUT /sections/{section_gid}
+func (c *Client) RenameSection(ctx context.Context, sectionGID, name string) (*Section, error) {
+	endpoint := withFields(fmt.Sprintf("/sections/%s", sectionGID), nil, DefaultSectionFields)
+	return c.sectionRequest(ctx, "PUT", endpoint, &SectionRequest{Name: name})
+}
+
+func (c *Client) sectionRequest(ctx context.Context, method, endpoint string, req *SectionRequest) (*Section, error) {
+	payload := map[string]interface{}{
+		"data": req,
+	}
+
+	body, err := c.do(ctx, method, endpoint, payload)
 	if err != nil {
 		return nil, err
 	}
 
 	var response struct {
-		Data []Section `json:"data"`
+		Data *Section `json:"data"`
 	}
 
 	if err := json.Unmarshal(body, &response); err != nil {
@@ -257,17 +671,95 @@ func (c *Client) GetSections(projectGID string) ([]Section, error) {
 	return response.Data, nil
 }
 
-// Search searches for tasks by text query
-// GET /workspaces/{workspace_gid}/tasks/search
-func (c *Client) Search(workspaceGID, query string) ([]Task, error) {
-	endpoint := fmt.Sprintf("/workspaces/%s/tasks/search?text=%s", workspaceGID, url.QueryEscape(query))
-	body, err := c.do("GET", endpoint, nil)
+// DeleteSection deletes a section. Asana only deletes empty sections, and
+// never a project's last one.
+// DELETE /sections/{section_gid}
+func (c *Client) DeleteSection(ctx context.Context, sectionGID string) error {
+	_, err := c.do(ctx, "DELETE", fmt.Sprintf("/sections/%s", sectionGID), nil)
+	return err
+}
+
+// MoveSection reorders a section within its project, placing it directly
+// before or after another section (exactly one of the two must be set)
+// POST /projects/{project_gid}/sections/insert
+func (c *Client) MoveSection(ctx context.Context, projectGID, sectionGID, beforeGID, afterGID string) error {
+	data := map[string]string{"section": sectionGID}
+	if beforeGID != "" {
+		data["before_section"] = beforeGID
+	}
+	if afterGID != "" {
+		data["after_section"] = afterGID
+	}
+	payload := map[string]interface{}{
+		"data": data,
+	}
+
+	_, err := c.do(ctx, "POST", fmt.Sprintf("/projects/%s/sections/insert", projectGID), payload)
+	return err
+}
+
+// AddTaskToSection moves a task into a section, removing it from any other
+// section of that project. insertBefore/insertAfter optionally position it
+// next to another task in the section; by default it goes to the end.
+// POST /sections/{section_gid}/addTask
+func (c *Client) AddTaskToSection(ctx context.Context, sectionGID, taskGID, insertBefore, insertAfter string) error {
+	payload := map[string]interface{}{
+		"data": sectionTaskData(taskGID, insertBefore, insertAfter),
+	}
+
+	_, err := c.do(ctx, "POST", fmt.Sprintf("/sections/%s/addTask", sectionGID), payload)
+	return err
+}
+
+func sectionTaskData(taskGID, insertBefore, insertAfter string) map[string]string {
+	data := map[string]string{"task": taskGID}
+	if insertBefore != "" {
+		data["insert_before"] = insertBefore
+	}
+	if insertAfter != "" {
+		data["insert_after"] = insertAfter
+	}
+	return data
+}
+
+// GetTags retrieves every tag in a workspace
+func (c *Client) GetTags(ctx context.Context, workspaceGID string) ([]Tag, error) {
+	return collect(c.IterTags(ctx, workspaceGID))
+}
+
+// IterTags streams the tags in a workspace page by page
+// GET /tags?workspace={workspace_gid}
+func (c *Client) IterTags(ctx context.Context, workspaceGID string) *Iterator[Tag] {
+	q := url.Values{}
+	q.Set("workspace", workspaceGID)
+	return newIterator[Tag](ctx, c, "/tags", setFields(q, nil, DefaultTagFields))
+}
+
+// CreateTag creates a tag in req.Workspace
+// POST /tags
+func (c *Client) CreateTag(ctx context.Context, req *TagRequest) (*Tag, error) {
+	return c.tagRequest(ctx, "POST", withFields("/tags", nil, DefaultTagFields), req)
+}
+
+// UpdateTag updates a tag's name, color or notes
+// PUT /tags/{tag_gid}
+func (c *Client) UpdateTag(ctx context.Context, tagGID string, req *TagRequest) (*Tag, error) {
+	endpoint := withFields(fmt.Sprintf("/tags/%s", tagGID), nil, DefaultTagFields)
+	return c.tagRequest(ctx, "PUT", endpoint, req)
+}
+
+func (c *Client) tagRequest(ctx context.Context, method, endpoint string, req *TagRequest) (*Tag, error) {
+	payload := map[string]interface{}{
+		"data": req,
+	}
+
+	body, err := c.do(ctx, method, endpoint, payload)
 	if err != nil {
 		return nil, err
 	}
 
 	var response struct {
-		Data []Task `json:"data"`
+		Data *Tag `json:"data"`
 	}
 
 	if err := json.Unmarshal(body, &response); err != nil {
@@ -277,11 +769,50 @@ func (c *Client) Search(workspaceGID, query string) ([]Task, error) {
 	return response.Data, nil
 }
 
+// DeleteTag deletes a tag, removing it from every task
+// DELETE /tags/{tag_gid}
+func (c *Client) DeleteTag(ctx context.Context, tagGID string) error {
+	_, err := c.do(ctx, "DELETE", fmt.Sprintf("/tags/%s", tagGID), nil)
+	return err
+}
+
+// AddTag tags a task
+// POST /tasks/{task_gid}/addTag
+func (c *Client) AddTag(ctx context.Context, taskGID, tagGID string) error {
+	return c.changeTag(ctx, "addTag", taskGID, tagGID)
+}
+
+// RemoveTag removes a tag from a task
+// POST /tasks/{task_gid}/removeTag
+func (c *Client) RemoveTag(ctx context.Context, taskGID, tagGID string) error {
+	return c.changeTag(ctx, "removeTag", taskGID, tagGID)
+}
+
+func (c *Client) changeTag(ctx context.Context, action, taskGID, tagGID string) error {
+	payload := map[string]interface{}{
+		"data": map[string]string{"tag": tagGID},
+	}
+	_, err := c.do(ctx, "POST", fmt.Sprintf("/tasks/%s/%s", taskGID, action), payload)
+	return err
+}
+
+// Search searches for tasks by text query, optionally requesting
+// specific fields instead of DefaultTaskFields
+func (c *Client) Search(ctx context.Context, workspaceGID, query string, fields ...string) ([]Task, error) {
+	return collect(c.IterSearch(ctx, workspaceGID, query, fields...))
+}
+
+// IterSearch streams task search results page by page
+// GET /workspaces/{workspace_gid}/tasks/search
+func (c *Client) IterSearch(ctx context.Context, workspaceGID, query string, fields ...string) *Iterator[Task] {
+	return c.IterSearchTasks(ctx, workspaceGID, &SearchOptions{Text: query}, fields...)
+}
+
 // GetUserTaskList retrieves a user's "My Tasks" list
 // GET /users/{user_gid}/user_task_list
-func (c *Client) GetUserTaskList(userGID string) ([]Task, error) {
+func (c *Client) GetUserTaskList(ctx context.Context, userGID string) ([]Task, error) {
 	endpoint := fmt.Sprintf("/users/%s/user_task_list", userGID)
-	body, err := c.do("GET", endpoint, nil)
+	body, err := c.do(ctx, "GET", endpoint, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -299,9 +830,9 @@ func (c *Client) GetUserTaskList(userGID string) ([]Task, error) {
 
 // GetUserTeams retrieves teams a specific user belongs to
 // GET /users/{user_gid}/teams
-func (c *Client) GetUserTeams(userGID string) ([]Team, error) {
+func (c *Client) GetUserTeams(ctx context.Context, userGID string) ([]Team, error) {
 	endpoint := fmt.Sprintf("/users/%s/teams", userGID)
-	body, err := c.do("GET", endpoint, nil)
+	body, err := c.do(ctx, "GET", endpoint, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -317,15 +848,21 @@ func (c *Client) GetUserTeams(userGID string) ([]Team, error) {
 	return response.Data, nil
 }
 
+// GetTeams retrieves the teams in an organization workspace
+// GET /workspaces/{workspace_gid}/teams
+func (c *Client) GetTeams(ctx context.Context, workspaceGID string) ([]Team, error) {
+	return collect(newIterator[Team](ctx, c, fmt.Sprintf("/workspaces/%s/teams", workspaceGID), nil))
+}
+
 // UpdateWorkspace updates a workspace
 // PUT /workspaces/{workspace_gid}
-func (c *Client) UpdateWorkspace(workspaceGID string, req *WorkspaceUpdateRequest) (*Workspace, error) {
+func (c *Client) UpdateWorkspace(ctx context.Context, workspaceGID string, req *WorkspaceUpdateRequest) (*Workspace, error) {
 	endpoint := fmt.Sprintf("/workspaces/%s", workspaceGID)
 	payload := map[string]interface{}{
 		"data": req,
 	}
 
-	body, err := c.do("PUT", endpoint, payload)
+	body, err := c.do(ctx, "PUT", endpoint, payload)
 	if err != nil {
 		return nil, err
 	}
@@ -341,30 +878,14 @@ func (c *Client) UpdateWorkspace(workspaceGID string, req *WorkspaceUpdateReques
 	return response.Data, nil
 }
 
-// GetTasksByWorkspace retrieves tasks from a workspace with optional filters
-func (c *Client) GetTasksByWorkspace(workspaceGID string, filters map[string]string) ([]Task, error) {
-	endpoint := fmt.Sprintf("/workspaces/%s/tasks", workspaceGID)
-	
-	if len(filters) > 0 {
-		q := url.Values{}
-		for k, v := range filters {
-			q.Add(k, v)
-		}
-		endpoint += "?" + q.Encode()
-	}
-
-	body, err := c.do("GET", endpoint, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	var response struct {
-		Data []Task `json:"data"`
-	}
-
-	if err := json.Unmarshal(body, &response); err != nil {
-		return nil, err
-	}
+// GetTasksByWorkspace retrieves every task in a workspace with optional filters
+func (c *Client) GetTasksByWorkspace(ctx context.Context, workspaceGID string, filters map[string]string) ([]Task, error) {
+	return collect(c.IterTasksByWorkspace(ctx, workspaceGID, filters))
+}
 
-	return response.Data, nil
-}
\ No newline at end of file
+// IterTasksByWorkspace streams the tasks in a workspace page by page
+// GET /workspaces/{workspace_gid}/tasks
+func (c *Client) IterTasksByWorkspace(ctx context.Context, workspaceGID string, filters map[string]string) *Iterator[Task] {
+	endpoint := fmt.Sprintf("/workspaces/%s/tasks", workspaceGID)
+	return newIterator[Task](ctx, c, endpoint, setFields(filterValues(filters), nil, DefaultTaskFields))
+}
@@ -1,6 +1,13 @@
 package asana
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
 	"testing"
 )
 
@@ -50,4 +57,106 @@ func TestClientInitialization(t *testing.T) {
 	if client.http == nil {
 		t.Error("http client not initialized")
 	}
-}
\ No newline at end of file
+}
+
+// newPagedServer serves `total` tasks from /projects/p1/tasks in pages of the
+// requested limit, recording each request's query string
+func newPagedServer(t *testing.T, total int, queries *[]url.Values) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		*queries = append(*queries, q)
+
+		limit, _ := strconv.Atoi(q.Get("limit"))
+		start, _ := strconv.Atoi(q.Get("offset"))
+		end := start + limit
+		if end > total {
+			end = total
+		}
+
+		data := make([]map[string]string, 0, end-start)
+		for i := start; i < end; i++ {
+			data = append(data, map[string]string{"gid": strconv.Itoa(i), "name": fmt.Sprintf("Task %d", i)})
+		}
+
+		resp := map[string]interface{}{"data": data, "next_page": nil}
+		if end < total {
+			resp["next_page"] = map[string]string{"offset": strconv.Itoa(end)}
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+}
+
+func TestGetTasksFollowsPagination(t *testing.T) {
+	var queries []url.Values
+	srv := newPagedServer(t, 250, &queries)
+	defer srv.Close()
+
+	client := NewClient("test-token")
+	client.baseURL = srv.URL
+
+	tasks, err := client.GetTasks(context.Background(), "p1", map[string]string{"assignee": "me"})
+	if err != nil {
+		t.Fatalf("GetTasks failed: %v", err)
+	}
+	if len(tasks) != 250 {
+		t.Fatalf("expected 250 tasks, got %d", len(tasks))
+	}
+	if tasks[249].GID != "249" {
+		t.Errorf("unexpected last task: %s", tasks[249].GID)
+	}
+	if len(queries) != 3 {
+		t.Fatalf("expected 3 page requests, got %d", len(queries))
+	}
+	for _, q := range queries {
+		if q.Get("assignee") != "me" {
+			t.Errorf("filter not sent on every page: %v", q)
+		}
+	}
+	if queries[1].Get("offset") != "100" {
+		t.Errorf("second page offset = %q", queries[1].Get("offset"))
+	}
+}
+
+func TestIteratorRespectsPageSizeAndMaxItems(t *testing.T) {
+	var queries []url.Values
+	srv := newPagedServer(t, 50, &queries)
+	defer srv.Close()
+
+	client := NewClient("test-token")
+	client.baseURL = srv.URL
+	client.SetPageSize(10)
+	client.SetMaxItems(25)
+
+	it := client.IterTasks(context.Background(), "p1", nil)
+	count := 0
+	for it.Next() {
+		count++
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("iteration failed: %v", err)
+	}
+	if count != 25 || it.Count() != 25 {
+		t.Errorf("expected 25 tasks, got %d", count)
+	}
+	if len(queries) != 3 {
+		t.Fatalf("expected 3 page requests, got %d", len(queries))
+	}
+	if got := queries[2].Get("limit"); got != "5" {
+		t.Errorf("last page should only request the remaining 5 items, got limit=%s", got)
+	}
+}
+
+func TestSetPageSizeClamps(t *testing.T) {
+	client := NewClient("test-token")
+
+	client.SetPageSize(500)
+	if client.pageSize != MaxPageSize {
+		t.Errorf("page size not clamped to max: %d", client.pageSize)
+	}
+
+	client.SetPageSize(0)
+	if client.pageSize != 1 {
+		t.Errorf("page size not clamped to 1: %d", client.pageSize)
+	}
+}
@@ -0,0 +1,234 @@
+package asana
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// String renders a custom field value for display. Asana's display_value
+// is used when present; otherwise it is derived from the typed value.
+func (v *CustomFieldValue) String() string {
+	if v.DisplayValue != "" {
+		return v.DisplayValue
+	}
+
+	switch {
+	case v.TextValue != nil:
+		return *v.TextValue
+	case v.NumberValue != nil:
+		return strconv.FormatFloat(*v.NumberValue, 'f', v.Precision, 64)
+	case v.EnumValue != nil:
+		return v.EnumValue.Name
+	case len(v.MultiEnumValues) > 0:
+		names := make([]string, len(v.MultiEnumValues))
+		for i, o := range v.MultiEnumValues {
+			names[i] = o.Name
+		}
+		return strings.Join(names, ", ")
+	case v.DateValue != nil:
+		if v.DateValue.DateTime != "" {
+			return v.DateValue.DateTime
+		}
+		return v.DateValue.Date
+	case len(v.PeopleValue) > 0:
+		names := make([]string, len(v.PeopleValue))
+		for i, u := range v.PeopleValue {
+			names[i] = u.Name
+		}
+		return strings.Join(names, ", ")
+	}
+	return ""
+}
+
+// IsSet reports whether the field has a value on the task
+func (v *CustomFieldValue) IsSet() bool {
+	return v.String() != ""
+}
+
+// Matches reports whether the value equals want, compared the way a person
+// would type it: case-insensitively, numerically for numbers, against any
+// of the options of a multi-enum or people field, and with an empty want
+// matching an unset field
+func (v *CustomFieldValue) Matches(want string) bool {
+	want = strings.TrimSpace(want)
+	if want == "" {
+		return !v.IsSet()
+	}
+
+	switch {
+	case v.NumberValue != nil:
+		n, err := strconv.ParseFloat(want, 64)
+		return err == nil && n == *v.NumberValue
+	case v.EnumValue != nil:
+		return matchesOption(*v.EnumValue, want)
+	case len(v.MultiEnumValues) > 0:
+		for _, o := range v.MultiEnumValues {
+			if matchesOption(o, want) {
+				return true
+			}
+		}
+		return false
+	case len(v.PeopleValue) > 0:
+		for _, u := range v.PeopleValue {
+			if u.GID == want || strings.EqualFold(u.Name, want) || strings.EqualFold(u.Email, want) {
+				return true
+			}
+		}
+		return false
+	case v.DateValue != nil:
+		return v.DateValue.Date == want || v.DateValue.DateTime == want
+	}
+	return strings.EqualFold(v.String(), want)
+}
+
+func matchesOption(o EnumOption, want string) bool {
+	return o.GID == want || strings.EqualFold(o.Name, want)
+}
+
+// CustomField returns the task's custom field with the given name (case
+// insensitive) or GID, or nil if the task doesn't have it
+func (t *Task) CustomField(name string) *CustomFieldValue {
+	for i := range t.CustomFields {
+		f := &t.CustomFields[i]
+		if f.GID == name || strings.EqualFold(f.Name, name) {
+			return f
+		}
+	}
+	return nil
+}
+
+// Priority returns the value of the task's "Priority" custom field, the
+// usual way Asana projects track priority, or "" if there is none
+func (t *Task) Priority() string {
+	if f := t.CustomField("Priority"); f != nil {
+		return f.String()
+	}
+	return ""
+}
+
+// ParseFieldAssignment splits a "Name=value" argument as passed to --field
+func ParseFieldAssignment(s string) (name, value string, err error) {
+	name, value, ok := strings.Cut(s, "=")
+	name = strings.TrimSpace(name)
+	if !ok || name == "" {
+		return "", "", fmt.Errorf("invalid custom field %q, expected \"Name=value\"", s)
+	}
+	return name, strings.TrimSpace(value), nil
+}
+
+// ResolveCustomFields turns "Name=value" assignments into the custom_fields
+// payload of a task create or update request, keyed by field GID. Fields are
+// looked up by name or GID among defs, enum options by name or GID, and
+// values converted to the field's type. An empty value clears the field.
+func ResolveCustomFields(defs []CustomField, assignments []string) (map[string]interface{}, error) {
+	if len(assignments) == 0 {
+		return nil, nil
+	}
+
+	values := make(map[string]interface{}, len(assignments))
+	for _, a := range assignments {
+		name, raw, err := ParseFieldAssignment(a)
+		if err != nil {
+			return nil, err
+		}
+
+		def := findCustomField(defs, name)
+		if def == nil {
+			return nil, fmt.Errorf("unknown custom field %q (available: %s)", name, customFieldNames(defs))
+		}
+
+		value, err := def.parseValue(raw)
+		if err != nil {
+			return nil, fmt.Errorf("custom field %q: %w", def.Name, err)
+		}
+		values[def.GID] = value
+	}
+	return values, nil
+}
+
+func findCustomField(defs []CustomField, name string) *CustomField {
+	for i := range defs {
+		if defs[i].GID == name || strings.EqualFold(defs[i].Name, name) {
+			return &defs[i]
+		}
+	}
+	return nil
+}
+
+func customFieldNames(defs []CustomField) string {
+	if len(defs) == 0 {
+		return "none"
+	}
+	names := make([]string, len(defs))
+	for i, d := range defs {
+		names[i] = d.Name
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// parseValue converts a command-line value to what the API expects for the
+// field's type. nil clears the field.
+func (f *CustomField) parseValue(raw string) (interface{}, error) {
+	if raw == "" {
+		return nil, nil
+	}
+
+	switch f.Type {
+	case CustomFieldNumber:
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a number", raw)
+		}
+		return n, nil
+
+	case CustomFieldEnum:
+		o, err := f.option(raw)
+		if err != nil {
+			return nil, err
+		}
+		return o.GID, nil
+
+	case CustomFieldMultiEnum:
+		var gids []string
+		for _, name := range ParseFields(raw) {
+			o, err := f.option(name)
+			if err != nil {
+				return nil, err
+			}
+			gids = append(gids, o.GID)
+		}
+		return gids, nil
+
+	case CustomFieldDate:
+		if _, err := time.Parse("2006-01-02", raw); err == nil {
+			return DateValue{Date: raw}, nil
+		}
+		if _, err := time.Parse(time.RFC3339, raw); err == nil {
+			return DateValue{DateTime: raw}, nil
+		}
+		return nil, fmt.Errorf("%q is not a date (YYYY-MM-DD or RFC 3339)", raw)
+
+	case CustomFieldPeople:
+		return ParseFields(raw), nil
+	}
+
+	return raw, nil
+}
+
+// option finds an enum option by name or GID
+func (f *CustomField) option(name string) (*EnumOption, error) {
+	var names []string
+	for i, o := range f.EnumOptions {
+		if matchesOption(o, name) {
+			return &f.EnumOptions[i], nil
+		}
+		if o.Enabled {
+			names = append(names, o.Name)
+		}
+	}
+	return nil, fmt.Errorf("no option %q (options: %s)", name, strings.Join(names, ", "))
+}
@@ -0,0 +1,107 @@
+package asana
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func testCustomFields() []CustomField {
+	return []CustomField{
+		{GID: "1", Name: "Story Points", Type: CustomFieldNumber},
+		{GID: "2", Name: "Priority", Type: CustomFieldEnum, EnumOptions: []EnumOption{
+			{GID: "21", Name: "High", Enabled: true},
+			{GID: "22", Name: "Low", Enabled: true},
+		}},
+		{GID: "3", Name: "Labels", Type: CustomFieldMultiEnum, EnumOptions: []EnumOption{
+			{GID: "31", Name: "UX", Enabled: true},
+			{GID: "32", Name: "Backend", Enabled: true},
+		}},
+		{GID: "4", Name: "Launch", Type: CustomFieldDate},
+		{GID: "5", Name: "Reviewers", Type: CustomFieldPeople},
+		{GID: "6", Name: "Sprint", Type: CustomFieldText},
+	}
+}
+
+func TestResolveCustomFields(t *testing.T) {
+	got, err := ResolveCustomFields(testCustomFields(), []string{
+		"Story Points=5",
+		"priority=high",
+		"Labels=ux, Backend",
+		"Launch=2026-06-01",
+		"Reviewers=me,123",
+		"Sprint = 12 ",
+	})
+	if err != nil {
+		t.Fatalf("ResolveCustomFields failed: %v", err)
+	}
+
+	want := map[string]interface{}{
+		"1": 5.0,
+		"2": "21",
+		"3": []string{"31", "32"},
+		"4": DateValue{Date: "2026-06-01"},
+		"5": []string{"me", "123"},
+		"6": "12",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %#v, want %#v", got, want)
+	}
+
+	cleared, err := ResolveCustomFields(testCustomFields(), []string{"Priority="})
+	if err != nil || cleared["2"] != nil {
+		t.Errorf("expected an empty value to clear the field, got %v, %v", cleared, err)
+	}
+}
+
+func TestResolveCustomFieldsErrors(t *testing.T) {
+	tests := map[string]string{
+		"Points":            `expected "Name=value"`,
+		"Effort=3":          "unknown custom field",
+		"Story Points=many": "not a number",
+		"Priority=Urgent":   "options: High, Low",
+		"Launch=tomorrow":   "not a date",
+	}
+	for arg, want := range tests {
+		_, err := ResolveCustomFields(testCustomFields(), []string{arg})
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: expected error containing %q, got %v", arg, want, err)
+		}
+	}
+}
+
+func TestCustomFieldValueMatches(t *testing.T) {
+	points := 5.0
+	task := Task{CustomFields: []CustomFieldValue{
+		{CustomField: CustomField{Name: "Story Points", Type: CustomFieldNumber}, NumberValue: &points},
+		{CustomField: CustomField{Name: "Priority", Type: CustomFieldEnum}, EnumValue: &EnumOption{GID: "21", Name: "High"}},
+		{CustomField: CustomField{Name: "Labels", Type: CustomFieldMultiEnum}, MultiEnumValues: []EnumOption{{Name: "UX"}, {Name: "Backend"}}},
+		{CustomField: CustomField{Name: "Sprint", Type: CustomFieldText}},
+	}}
+
+	tests := []struct {
+		field, value string
+		want         bool
+	}{
+		{"story points", "5.0", true},
+		{"Story Points", "3", false},
+		{"Priority", "high", true},
+		{"Priority", "21", true},
+		{"Labels", "backend", true},
+		{"Labels", "Docs", false},
+		{"Sprint", "", true},
+		{"Priority", "", false},
+	}
+	for _, tt := range tests {
+		if got := task.CustomField(tt.field).Matches(tt.value); got != tt.want {
+			t.Errorf("%s=%q: got %v, want %v", tt.field, tt.value, got, tt.want)
+		}
+	}
+
+	if task.Priority() != "High" {
+		t.Errorf("unexpected priority %q", task.Priority())
+	}
+	if got := task.CustomField("Labels").String(); got != "UX, Backend" {
+		t.Errorf("unexpected multi-enum display %q", got)
+	}
+}
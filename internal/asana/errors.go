@@ -0,0 +1,154 @@
+package asana
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ErrNoToken is returned when a request is attempted without an API token
+var ErrNoToken = errors.New("ASANA_TOKEN not set")
+
+// ErrorDetail is one entry of the `errors` array Asana returns on failure
+type ErrorDetail struct {
+	Message string `json:"message"`
+	Help    string `json:"help,omitempty"`
+	Phrase  string `json:"phrase,omitempty"`
+}
+
+// APIError is returned for every non-2xx response from the Asana API
+type APIError struct {
+	StatusCode int           `json:"status"`
+	Method     string        `json:"method"`
+	Path       string        `json:"path"`
+	Errors     []ErrorDetail `json:"errors,omitempty"`
+	// Body holds the raw response when it was not an Asana error document
+	Body string `json:"body,omitempty"`
+}
+
+// newAPIError builds an APIError from a failed response
+func newAPIError(method, endpoint string, status int, body []byte) *APIError {
+	path := endpoint
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+
+	apiErr := &APIError{
+		StatusCode: status,
+		Method:     method,
+		Path:       path,
+	}
+
+	var doc struct {
+		Errors []ErrorDetail `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &doc); err == nil && len(doc.Errors) > 0 {
+		apiErr.Errors = doc.Errors
+	} else {
+		apiErr.Body = strings.TrimSpace(string(body))
+	}
+
+	return apiErr
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API error (%d) %s %s: %s", e.StatusCode, e.Method, e.Path, e.Message())
+}
+
+// Message joins the error messages Asana returned, falling back to the
+// HTTP status text
+func (e *APIError) Message() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, d := range e.Errors {
+		if d.Message != "" {
+			msgs = append(msgs, d.Message)
+		}
+	}
+	if len(msgs) > 0 {
+		return strings.Join(msgs, "; ")
+	}
+	if e.Body != "" {
+		return e.Body
+	}
+	return http.StatusText(e.StatusCode)
+}
+
+// Code returns a stable, machine-readable identifier for the error class
+func (e *APIError) Code() string {
+	switch {
+	case e.StatusCode == http.StatusBadRequest:
+		return "invalid_request"
+	case e.StatusCode == http.StatusUnauthorized:
+		return "unauthorized"
+	case e.StatusCode == http.StatusPaymentRequired:
+		return "payment_required"
+	case e.StatusCode == http.StatusForbidden:
+		return "forbidden"
+	case e.StatusCode == http.StatusNotFound:
+		return "not_found"
+	case e.StatusCode == http.StatusTooManyRequests:
+		return "rate_limited"
+	case e.StatusCode >= 500:
+		return "server_error"
+	}
+	return "api_error"
+}
+
+func asAPIError(err error) (*APIError, bool) {
+	var apiErr *APIError
+	ok := errors.As(err, &apiErr)
+	return apiErr, ok
+}
+
+// IsNotFound reports whether err is a 404 from the API
+func IsNotFound(err error) bool {
+	apiErr, ok := asAPIError(err)
+	return ok && apiErr.StatusCode == http.StatusNotFound
+}
+
+// IsRateLimited reports whether err is a 429 from the API
+func IsRateLimited(err error) bool {
+	apiErr, ok := asAPIError(err)
+	return ok && apiErr.StatusCode == http.StatusTooManyRequests
+}
+
+// IsAuth reports whether err was caused by a missing, invalid or
+// insufficiently privileged token
+func IsAuth(err error) bool {
+	if errors.Is(err, ErrNoToken) {
+		return true
+	}
+	apiErr, ok := asAPIError(err)
+	return ok && (apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden)
+}
+
+// IsInvalid reports whether err is a 400, typically an unknown or
+// malformed field
+func IsInvalid(err error) bool {
+	apiErr, ok := asAPIError(err)
+	return ok && apiErr.StatusCode == http.StatusBadRequest
+}
+
+// ErrorCode returns a machine-readable code for any error returned by the
+// client, for use in scripted output
+func ErrorCode(err error) string {
+	if err == nil {
+		return ""
+	}
+	if errors.Is(err, ErrNoToken) {
+		return "no_token"
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "timeout"
+	}
+	if errors.Is(err, context.Canceled) {
+		return "canceled"
+	}
+	if apiErr, ok := asAPIError(err); ok {
+		return apiErr.Code()
+	}
+	return "error"
+}
@@ -0,0 +1,87 @@
+package asana
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNewAPIErrorParsesAsanaErrors(t *testing.T) {
+	body := []byte(`{"errors":[{"message":"task: Not a recognized ID: 123","help":"For more information on API status codes...","phrase":"6 sad squid snuggle softly"}]}`)
+	err := newAPIError("GET", "/tasks/123?opt_fields=name", http.StatusNotFound, body)
+
+	if err.Path != "/tasks/123" {
+		t.Errorf("query string should be stripped from path: %s", err.Path)
+	}
+	if len(err.Errors) != 1 || err.Errors[0].Phrase != "6 sad squid snuggle softly" {
+		t.Errorf("errors not parsed: %+v", err.Errors)
+	}
+	if err.Code() != "not_found" {
+		t.Errorf("unexpected code: %s", err.Code())
+	}
+	if !strings.Contains(err.Error(), "Not a recognized ID") {
+		t.Errorf("message missing from error string: %s", err.Error())
+	}
+}
+
+func TestNewAPIErrorKeepsRawBody(t *testing.T) {
+	err := newAPIError("PUT", "/tasks/1", http.StatusBadGateway, []byte("<html>bad gateway</html>"))
+	if err.Body != "<html>bad gateway</html>" || err.Message() != err.Body {
+		t.Errorf("raw body not kept: %+v", err)
+	}
+	if err.Code() != "server_error" {
+		t.Errorf("unexpected code: %s", err.Code())
+	}
+}
+
+func TestErrorHelpers(t *testing.T) {
+	tests := []struct {
+		status      int
+		notFound    bool
+		rateLimited bool
+		auth        bool
+		invalid     bool
+	}{
+		{http.StatusNotFound, true, false, false, false},
+		{http.StatusTooManyRequests, false, true, false, false},
+		{http.StatusUnauthorized, false, false, true, false},
+		{http.StatusForbidden, false, false, true, false},
+		{http.StatusBadRequest, false, false, false, true},
+	}
+
+	for _, tt := range tests {
+		// Wrap to make sure helpers see through fmt.Errorf("%w")
+		err := fmt.Errorf("loading task: %w", newAPIError("GET", "/tasks/1", tt.status, nil))
+		if IsNotFound(err) != tt.notFound || IsRateLimited(err) != tt.rateLimited ||
+			IsAuth(err) != tt.auth || IsInvalid(err) != tt.invalid {
+			t.Errorf("status %d: helpers returned wrong classification", tt.status)
+		}
+	}
+
+	if !IsAuth(ErrNoToken) || ErrorCode(ErrNoToken) != "no_token" {
+		t.Error("ErrNoToken should be classified as an auth error")
+	}
+	if ErrorCode(fmt.Errorf("boom")) != "error" {
+		t.Error("plain errors should have the generic code")
+	}
+}
+
+func TestClientReturnsAPIError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"errors":[{"message":"Forbidden"}]}`))
+	}))
+	defer srv.Close()
+
+	_, err := newTestClient(srv.URL).GetTask(context.Background(), "42")
+	apiErr, ok := asAPIError(err)
+	if !ok {
+		t.Fatalf("expected *APIError, got %T", err)
+	}
+	if apiErr.Method != "GET" || apiErr.Path != "/tasks/42" || apiErr.StatusCode != http.StatusForbidden {
+		t.Errorf("unexpected error fields: %+v", apiErr)
+	}
+}
@@ -0,0 +1,221 @@
+package asana
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+)
+
+// Asana returns compact records (gid and name only) unless the fields to
+// include are listed in opt_fields. These are the fields requested by
+// default for each resource type; they cover everything the models decode.
+var (
+	DefaultTaskFields = []string{
+		"name",
+		"notes",
+		"completed",
+		"due_on",
+		"due_at",
+		"assignee.name",
+		"assignee.email",
+		"assignee_status",
+		"projects.name",
+		"tags.name",
+		"parent.name",
+		"num_subtasks",
+		"dependencies.name",
+		"dependents.name",
+		"memberships.project.name",
+		"memberships.section.name",
+		"custom_fields.name",
+		"custom_fields.resource_subtype",
+		"custom_fields.display_value",
+		"custom_fields.text_value",
+		"custom_fields.number_value",
+		"custom_fields.enum_value.name",
+		"custom_fields.multi_enum_values.name",
+		"custom_fields.date_value",
+		"custom_fields.people_value.name",
+		"created_at",
+		"modified_at",
+	}
+
+	// CompactTaskFields are requested where only a task's identity and
+	// state matter, such as when walking dependencies
+	CompactTaskFields = []string{
+		"name",
+		"completed",
+	}
+
+	DefaultProjectFields = []string{
+		"name",
+		"notes",
+		"color",
+		"archived",
+		"owner.name",
+		"team.name",
+		"default_view",
+		"due_on",
+		"public",
+		"created_at",
+		"modified_at",
+	}
+
+	DefaultJobFields = []string{
+		"status",
+		"resource_subtype",
+		"new_project.name",
+		"new_task.name",
+	}
+
+	DefaultTagFields = []string{
+		"name",
+		"color",
+		"notes",
+	}
+
+	DefaultSectionFields = []string{
+		"name",
+	}
+
+	DefaultUserFields = []string{
+		"name",
+		"email",
+	}
+
+	// DefaultCustomFieldFields describe a custom field definition, including
+	// the enum options needed to resolve option names
+	DefaultCustomFieldFields = []string{
+		"name",
+		"resource_subtype",
+		"precision",
+		"enum_options.name",
+		"enum_options.color",
+		"enum_options.enabled",
+	}
+
+	DefaultAttachmentFields = []string{
+		"name",
+		"host",
+		"size",
+		"download_url",
+		"permanent_url",
+		"view_url",
+		"created_at",
+	}
+
+	DefaultStoryFields = []string{
+		"created_at",
+		"created_by.name",
+		"type",
+		"resource_subtype",
+		"text",
+	}
+)
+
+// ParseFields splits a comma-separated --fields value into field names,
+// dropping blanks and surrounding whitespace
+func ParseFields(s string) []string {
+	var fields []string
+	for _, f := range strings.Split(s, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// PrefixFields qualifies fields with the path of a nested record, e.g.
+// PrefixFields("custom_fields", []string{"name"}) is "custom_fields.name"
+func PrefixFields(prefix string, fields []string) []string {
+	prefixed := make([]string, len(fields))
+	for i, f := range fields {
+		prefixed[i] = prefix + "." + f
+	}
+	return prefixed
+}
+
+// setFields sets opt_fields on a query, using defaults unless the caller
+// already chose fields (e.g. via the filters map) or passed some explicitly
+func setFields(q url.Values, fields []string, defaults []string) url.Values {
+	if q == nil {
+		q = url.Values{}
+	}
+	if len(fields) > 0 {
+		q.Set("opt_fields", strings.Join(fields, ","))
+	} else if q.Get("opt_fields") == "" {
+		q.Set("opt_fields", strings.Join(defaults, ","))
+	}
+	return q
+}
+
+// withFields appends an opt_fields query string to a single-record endpoint
+func withFields(endpoint string, fields []string, defaults []string) string {
+	return endpoint + "?" + setFields(nil, fields, defaults).Encode()
+}
+
+// SelectFields reduces a record, or a slice of records, to the given
+// fields plus gid. Dotted fields such as "assignee.name" select within
+// nested objects. It is used to emit exactly the fields passed to --fields.
+func SelectFields(v interface{}, fields []string) interface{} {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var generic interface{}
+	if err := json.Unmarshal(raw, &generic); err != nil {
+		return v
+	}
+	return parseFieldTree(fields).apply(generic)
+}
+
+// fieldTree is a parsed field list: "assignee.name,name" becomes
+// {"assignee": {"name": {}}, "name": {}}
+type fieldTree map[string]fieldTree
+
+func parseFieldTree(fields []string) fieldTree {
+	tree := fieldTree{}
+	for _, field := range fields {
+		node := tree
+		for _, part := range strings.Split(field, ".") {
+			next, ok := node[part]
+			if !ok {
+				next = fieldTree{}
+				node[part] = next
+			}
+			node = next
+		}
+	}
+	return tree
+}
+
+func (t fieldTree) apply(v interface{}) interface{} {
+	switch x := v.(type) {
+	case []interface{}:
+		out := make([]interface{}, len(x))
+		for i, item := range x {
+			out[i] = t.apply(item)
+		}
+		return out
+
+	case map[string]interface{}:
+		out := map[string]interface{}{}
+		if gid, ok := x["gid"]; ok {
+			out["gid"] = gid
+		}
+		for name, sub := range t {
+			val, ok := x[name]
+			if !ok {
+				continue
+			}
+			if len(sub) == 0 {
+				out[name] = val
+			} else {
+				out[name] = sub.apply(val)
+			}
+		}
+		return out
+	}
+
+	return v
+}
@@ -0,0 +1,53 @@
+package asana
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseFields(t *testing.T) {
+	got := ParseFields(" name, due_on ,,assignee.name ")
+	want := []string{"name", "due_on", "assignee.name"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseFields = %v, want %v", got, want)
+	}
+	if ParseFields("") != nil {
+		t.Error("empty input should yield no fields")
+	}
+}
+
+func TestSetFields(t *testing.T) {
+	q := setFields(nil, nil, DefaultTaskFields)
+	if q.Get("opt_fields") != strings.Join(DefaultTaskFields, ",") {
+		t.Errorf("defaults not applied: %s", q.Get("opt_fields"))
+	}
+
+	q = setFields(filterValues(map[string]string{"opt_fields": "name"}), nil, DefaultTaskFields)
+	if q.Get("opt_fields") != "name" {
+		t.Errorf("caller's opt_fields overridden: %s", q.Get("opt_fields"))
+	}
+
+	q = setFields(nil, []string{"name", "due_on"}, DefaultTaskFields)
+	if q.Get("opt_fields") != "name,due_on" {
+		t.Errorf("explicit fields not applied: %s", q.Get("opt_fields"))
+	}
+}
+
+func TestSelectFields(t *testing.T) {
+	tasks := []Task{{
+		GID:      "1",
+		Name:     "Ship",
+		Assignee: &User{GID: "u1", Name: "Ada", Email: "ada@example.com"},
+	}}
+
+	got := SelectFields(tasks, []string{"name", "assignee.name"})
+	want := []interface{}{map[string]interface{}{
+		"gid":      "1",
+		"name":     "Ship",
+		"assignee": map[string]interface{}{"gid": "u1", "name": "Ada"},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SelectFields = %#v, want %#v", got, want)
+	}
+}
@@ -37,36 +37,62 @@ func (ct *CustomTime) UnmarshalJSON(b []byte) error {
 
 // Task represents an Asana task
 type Task struct {
-	GID             string      `json:"gid"`
-	Name            string      `json:"name"`
-	Description     string      `json:"description"`
-	Completed       bool        `json:"completed"`
-	DueDate         *CustomTime `json:"due_on,omitempty"`
-	DueAt           *CustomTime `json:"due_at,omitempty"`
-	Priority        string      `json:"priority_value,omitempty"`
-	Status          string      `json:"status,omitempty"`
-	AssigneeStatus  string      `json:"assignee_status,omitempty"`
-	Assignee        *User       `json:"assignee,omitempty"`
-	Projects        []Project   `json:"projects,omitempty"`
-	Tags            []Tag       `json:"tags,omitempty"`
-	Dependencies    []Task      `json:"dependencies,omitempty"`
-	Attachments     []Attachment `json:"attachments,omitempty"`
-	CreatedAt       time.Time   `json:"created_at"`
-	ModifiedAt      time.Time   `json:"modified_at"`
+	GID            string             `json:"gid"`
+	Name           string             `json:"name"`
+	Description    string             `json:"notes"`
+	Completed      bool               `json:"completed"`
+	DueDate        *CustomTime        `json:"due_on,omitempty"`
+	DueAt          *CustomTime        `json:"due_at,omitempty"`
+	Status         string             `json:"status,omitempty"`
+	AssigneeStatus string             `json:"assignee_status,omitempty"`
+	Assignee       *User              `json:"assignee,omitempty"`
+	Projects       []Project          `json:"projects,omitempty"`
+	Tags           []Tag              `json:"tags,omitempty"`
+	Parent         *Task              `json:"parent,omitempty"`
+	NumSubtasks    int                `json:"num_subtasks,omitempty"`
+	Dependencies   []Task             `json:"dependencies,omitempty"`
+	Dependents     []Task             `json:"dependents,omitempty"`
+	CustomFields   []CustomFieldValue `json:"custom_fields,omitempty"`
+	Memberships    []Membership       `json:"memberships,omitempty"`
+	Attachments    []Attachment       `json:"attachments,omitempty"`
+	CreatedAt      time.Time          `json:"created_at"`
+	ModifiedAt     time.Time          `json:"modified_at"`
 }
 
 // Project represents an Asana project
 type Project struct {
-	GID             string    `json:"gid"`
-	Name            string    `json:"name"`
-	Description     string    `json:"description"`
-	Owner           *User     `json:"owner,omitempty"`
-	Status          string    `json:"status"`
-	Color           string    `json:"color"`
-	CreatedAt       time.Time `json:"created_at"`
-	ModifiedAt      time.Time `json:"modified_at"`
-	Archived        bool      `json:"archived"`
-	TaskCount       int       `json:"task_count,omitempty"`
+	GID         string      `json:"gid"`
+	Name        string      `json:"name"`
+	Description string      `json:"notes"`
+	Owner       *User       `json:"owner,omitempty"`
+	Team        *Team       `json:"team,omitempty"`
+	Status      string      `json:"status"`
+	Color       string      `json:"color"`
+	DefaultView string      `json:"default_view,omitempty"`
+	DueOn       *CustomTime `json:"due_on,omitempty"`
+	Public      bool        `json:"public"`
+	CreatedAt   time.Time   `json:"created_at"`
+	ModifiedAt  time.Time   `json:"modified_at"`
+	Archived    bool        `json:"archived"`
+	TaskCount   int         `json:"task_count,omitempty"`
+}
+
+// Project colors accepted by Asana
+var ProjectColors = []string{
+	"dark-pink", "dark-green", "dark-blue", "dark-red", "dark-teal",
+	"dark-brown", "dark-orange", "dark-purple", "dark-warm-gray",
+	"light-pink", "light-green", "light-blue", "light-red", "light-teal",
+	"light-brown", "light-orange", "light-purple", "light-warm-gray",
+	"none",
+}
+
+// Project default views accepted by Asana
+var ProjectViews = []string{"list", "board", "calendar", "timeline"}
+
+// Membership places a task in a project and, within it, a section
+type Membership struct {
+	Project *Project `json:"project,omitempty"`
+	Section *Section `json:"section,omitempty"`
 }
 
 // Section represents a project section
@@ -78,11 +104,11 @@ type Section struct {
 
 // User represents an Asana user
 type User struct {
-	GID       string `json:"gid"`
-	Name      string `json:"name"`
-	Email     string `json:"email"`
-	Avatar    string `json:"photo,omitempty"`
-	Timezone  string `json:"timezone,omitempty"`
+	GID      string `json:"gid"`
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Avatar   string `json:"photo,omitempty"`
+	Timezone string `json:"timezone,omitempty"`
 }
 
 // Team represents an Asana team
@@ -101,44 +127,211 @@ type Workspace struct {
 
 // Tag represents an Asana tag
 type Tag struct {
-	GID  string `json:"gid"`
-	Name string `json:"name"`
+	GID   string `json:"gid"`
+	Name  string `json:"name"`
+	Color string `json:"color,omitempty"`
+	Notes string `json:"notes,omitempty"`
 }
 
-// Attachment represents a task attachment
+// Attachment represents a file attached to a task. DownloadURL is a
+// short-lived link to the file contents; it is empty for attachments hosted
+// elsewhere (Google Drive, Dropbox, ...), which only have a ViewURL.
 type Attachment struct {
-	GID  string `json:"gid"`
-	Name string `json:"name"`
-	URL  string `json:"url,omitempty"`
+	GID          string    `json:"gid"`
+	Name         string    `json:"name"`
+	Host         string    `json:"host,omitempty"`
+	Size         int64     `json:"size,omitempty"`
+	DownloadURL  string    `json:"download_url,omitempty"`
+	PermanentURL string    `json:"permanent_url,omitempty"`
+	ViewURL      string    `json:"view_url,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// Custom field types, as reported in a custom field's resource_subtype
+const (
+	CustomFieldText      = "text"
+	CustomFieldNumber    = "number"
+	CustomFieldEnum      = "enum"
+	CustomFieldMultiEnum = "multi_enum"
+	CustomFieldDate      = "date"
+	CustomFieldPeople    = "people"
+)
+
+// CustomField is a custom field definition, such as "Story Points" or a
+// "Priority" drop-down
+type CustomField struct {
+	GID         string       `json:"gid"`
+	Name        string       `json:"name"`
+	Type        string       `json:"resource_subtype,omitempty"`
+	Precision   int          `json:"precision,omitempty"`
+	EnumOptions []EnumOption `json:"enum_options,omitempty"`
+}
+
+// EnumOption is one choice of an enum or multi-enum custom field
+type EnumOption struct {
+	GID     string `json:"gid"`
+	Name    string `json:"name"`
+	Color   string `json:"color,omitempty"`
+	Enabled bool   `json:"enabled,omitempty"`
+}
+
+// DateValue is the value of a date custom field. DateTime is only set when
+// the field includes a time.
+type DateValue struct {
+	Date     string `json:"date"`
+	DateTime string `json:"date_time,omitempty"`
+}
+
+// CustomFieldValue is a custom field as it appears on a task: the
+// definition plus whichever value field matches its type
+type CustomFieldValue struct {
+	CustomField
+	DisplayValue    string       `json:"display_value,omitempty"`
+	TextValue       *string      `json:"text_value,omitempty"`
+	NumberValue     *float64     `json:"number_value,omitempty"`
+	EnumValue       *EnumOption  `json:"enum_value,omitempty"`
+	MultiEnumValues []EnumOption `json:"multi_enum_values,omitempty"`
+	DateValue       *DateValue   `json:"date_value,omitempty"`
+	PeopleValue     []User       `json:"people_value,omitempty"`
+}
+
+// Story represents an entry in a task's activity feed: either a comment
+// or a system event such as an assignment or due date change
+type Story struct {
+	GID             string    `json:"gid"`
+	CreatedAt       time.Time `json:"created_at"`
+	CreatedBy       *User     `json:"created_by,omitempty"`
+	Type            string    `json:"type,omitempty"`
+	ResourceSubtype string    `json:"resource_subtype,omitempty"`
+	Text            string    `json:"text"`
+}
+
+// IsComment reports whether the story was written by a person rather than
+// generated by Asana
+func (s *Story) IsComment() bool {
+	return s.Type == "comment" || s.ResourceSubtype == "comment_added"
+}
+
+// MembershipRequest names a project, and optionally a section of it, for a
+// new task
+type MembershipRequest struct {
+	Project string `json:"project"`
+	Section string `json:"section,omitempty"`
+}
+
+// SectionRequest creates or renames a section. InsertBefore/InsertAfter
+// position a new section relative to an existing one; by default it is
+// added at the end of the project.
+type SectionRequest struct {
+	Name         string `json:"name"`
+	InsertBefore string `json:"insert_before,omitempty"`
+	InsertAfter  string `json:"insert_after,omitempty"`
+}
+
+// SetParentRequest moves a task under a new parent. An empty Parent turns
+// the task back into a top-level task. InsertBefore/InsertAfter position it
+// among its new siblings; by default it is added at the end.
+type SetParentRequest struct {
+	Parent       string
+	InsertBefore string
+	InsertAfter  string
 }
 
 // TaskCreateRequest for creating tasks
 type TaskCreateRequest struct {
 	Name        string   `json:"name"`
-	Description string   `json:"description,omitempty"`
+	Description string   `json:"notes,omitempty"`
 	Projects    []string `json:"projects,omitempty"`
-	Section     string   `json:"section,omitempty"`
+	Parent      string   `json:"parent,omitempty"`
 	Assignee    string   `json:"assignee,omitempty"`
 	DueOn       string   `json:"due_on,omitempty"`
 	DueAt       string   `json:"due_at,omitempty"`
-	Priority    string   `json:"priority_value,omitempty"`
 	Tags        []string `json:"tags,omitempty"`
+
+	// Memberships places the new task in specific sections
+	Memberships []MembershipRequest `json:"memberships,omitempty"`
+
+	// CustomFields maps custom field GIDs to values, as built by
+	// ResolveCustomFields
+	CustomFields map[string]interface{} `json:"custom_fields,omitempty"`
 }
 
 // TaskUpdateRequest for updating tasks
 type TaskUpdateRequest struct {
 	Name           string `json:"name,omitempty"`
-	Description    string `json:"description,omitempty"`
+	Description    string `json:"notes,omitempty"`
 	Completed      *bool  `json:"completed,omitempty"`
 	Assignee       string `json:"assignee,omitempty"`
 	DueOn          string `json:"due_on,omitempty"`
 	DueAt          string `json:"due_at,omitempty"`
-	Priority       string `json:"priority_value,omitempty"`
 	AssigneeStatus string `json:"assignee_status,omitempty"`
 	Status         string `json:"status,omitempty"`
+
+	CustomFields map[string]interface{} `json:"custom_fields,omitempty"`
+}
+
+// ProjectCreateRequest for creating projects. Team is required in
+// organization workspaces.
+type ProjectCreateRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"notes,omitempty"`
+	Workspace   string `json:"workspace,omitempty"`
+	Team        string `json:"team,omitempty"`
+	Color       string `json:"color,omitempty"`
+	DefaultView string `json:"default_view,omitempty"`
+	DueOn       string `json:"due_on,omitempty"`
+	Public      *bool  `json:"public,omitempty"`
+}
+
+// ProjectUpdateRequest for updating projects
+type ProjectUpdateRequest struct {
+	Name        string `json:"name,omitempty"`
+	Description string `json:"notes,omitempty"`
+	Team        string `json:"team,omitempty"`
+	Color       string `json:"color,omitempty"`
+	DefaultView string `json:"default_view,omitempty"`
+	DueOn       string `json:"due_on,omitempty"`
+	Public      *bool  `json:"public,omitempty"`
+	Archived    *bool  `json:"archived,omitempty"`
+}
+
+// ProjectDuplicateRequest for duplicating a project. Include lists the
+// optional parts to copy, e.g. "members", "notes", "task_notes",
+// "task_assignee", "task_subtasks", "task_attachments", "task_dates",
+// "task_dependencies", "task_followers", "task_tags" and "task_projects".
+type ProjectDuplicateRequest struct {
+	Name    string `json:"name"`
+	Team    string `json:"team,omitempty"`
+	Include string `json:"include,omitempty"`
+}
+
+// TagRequest for creating or updating tags. Workspace is only used when
+// creating; Color takes the same values as project colors.
+type TagRequest struct {
+	Name      string `json:"name,omitempty"`
+	Color     string `json:"color,omitempty"`
+	Notes     string `json:"notes,omitempty"`
+	Workspace string `json:"workspace,omitempty"`
+}
+
+// Job tracks an asynchronous operation such as a project duplication
+type Job struct {
+	GID        string   `json:"gid"`
+	Status     string   `json:"status"`
+	Type       string   `json:"resource_subtype,omitempty"`
+	NewProject *Project `json:"new_project,omitempty"`
+	NewTask    *Task    `json:"new_task,omitempty"`
 }
 
+// Job statuses
+const (
+	JobNotStarted = "not_started"
+	JobInProgress = "in_progress"
+	JobSucceeded  = "succeeded"
+	JobFailed     = "failed"
+)
+
 // WorkspaceUpdateRequest for updating workspaces
 type WorkspaceUpdateRequest struct {
 	Name string `json:"name,omitempty"`
-}
\ No newline at end of file
+}
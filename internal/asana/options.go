@@ -0,0 +1,136 @@
+package asana
+
+import (
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultBaseURL is the Asana REST API root
+	DefaultBaseURL = "https://app.asana.com/api/1.0"
+	// DefaultUserAgent is sent when no WithUserAgent option is given
+	DefaultUserAgent = "asana-cli"
+
+	// maxTraceBody limits how much of a request or response body is logged
+	maxTraceBody = 4096
+)
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithBaseURL points the client at a different API root, such as a local
+// stand-in server or a corporate gateway
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = strings.TrimRight(baseURL, "/")
+	}
+}
+
+// WithHTTPClient replaces the underlying http.Client, e.g. to supply a
+// custom transport or proxy configuration
+func WithHTTPClient(h *http.Client) Option {
+	return func(c *Client) {
+		if h != nil {
+			c.http = h
+		}
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request
+func WithUserAgent(ua string) Option {
+	return func(c *Client) {
+		c.userAgent = ua
+	}
+}
+
+// WithLogger enables request/response tracing to the given logger. The
+// Authorization header is always masked.
+func WithLogger(l *log.Logger) Option {
+	return func(c *Client) {
+		c.logger = l
+	}
+}
+
+// WithDryRun makes the client skip every request other than a GET, passing
+// it to record instead and answering as if it had succeeded with empty
+// data. Batches made only of reads are still sent. record may be called
+// from several goroutines at once.
+func WithDryRun(record func(PlannedRequest)) Option {
+	return func(c *Client) {
+		c.dryRun = record
+	}
+}
+
+// traceRequest logs an outgoing request
+func (c *Client) traceRequest(req *http.Request, payload []byte) {
+	if c.logger == nil {
+		return
+	}
+
+	c.logger.Printf("--> %s %s", req.Method, req.URL.String())
+	for _, line := range redactHeaders(req.Header) {
+		c.logger.Printf("    %s", line)
+	}
+	if len(payload) > 0 {
+		if ct := req.Header.Get("Content-Type"); strings.HasPrefix(ct, "multipart/") {
+			// File uploads are binary; their size is what's useful
+			c.logger.Printf("    <%d bytes of %s>", len(payload), strings.SplitN(ct, ";", 2)[0])
+		} else {
+			c.logger.Printf("    %s", truncateBody(payload))
+		}
+	}
+}
+
+// traceResponse logs a response, or the transport error that replaced it
+func (c *Client) traceResponse(req *http.Request, resp *http.Response, body []byte, err error, elapsed time.Duration) {
+	if c.logger == nil {
+		return
+	}
+
+	if err != nil {
+		c.logger.Printf("<-- %s %s failed after %s: %v", req.Method, req.URL.Path, elapsed.Round(time.Millisecond), err)
+		return
+	}
+
+	c.logger.Printf("<-- %s %s %s (%s)", req.Method, req.URL.Path, resp.Status, elapsed.Round(time.Millisecond))
+	for _, line := range redactHeaders(resp.Header) {
+		c.logger.Printf("    %s", line)
+	}
+	if len(body) > 0 {
+		c.logger.Printf("    %s", truncateBody(body))
+	}
+}
+
+// redactHeaders renders headers one per line with credentials masked
+func redactHeaders(h http.Header) []string {
+	lines := make([]string, 0, len(h))
+	for name, values := range h {
+		for _, v := range values {
+			if strings.EqualFold(name, "Authorization") {
+				v = redactAuthorization(v)
+			}
+			lines = append(lines, name+": "+v)
+		}
+	}
+	sort.Strings(lines)
+	return lines
+}
+
+// redactAuthorization keeps the auth scheme but hides the credential
+func redactAuthorization(v string) string {
+	scheme, _, found := strings.Cut(v, " ")
+	if !found {
+		return "****"
+	}
+	return scheme + " ****"
+}
+
+func truncateBody(b []byte) string {
+	if len(b) <= maxTraceBody {
+		return string(b)
+	}
+	return string(b[:maxTraceBody]) + "... (truncated)"
+}
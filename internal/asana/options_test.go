@@ -0,0 +1,108 @@
+package asana
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestOptionsOverrideDefaults(t *testing.T) {
+	var gotUA string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotUA = r.Header.Get("User-Agent")
+		w.Write([]byte(`{"data":{"gid":"me","name":"Me"}}`))
+	}))
+	defer srv.Close()
+
+	httpClient := &http.Client{}
+	client := NewClient("test-token",
+		WithBaseURL(srv.URL+"/"),
+		WithHTTPClient(httpClient),
+		WithUserAgent("asana-cli/test"),
+	)
+
+	if client.baseURL != srv.URL {
+		t.Errorf("trailing slash not trimmed: %s", client.baseURL)
+	}
+	if client.http != httpClient {
+		t.Error("http client not replaced")
+	}
+
+	if _, err := client.GetMe(context.Background()); err != nil {
+		t.Fatalf("GetMe failed: %v", err)
+	}
+	if gotUA != "asana-cli/test" {
+		t.Errorf("unexpected User-Agent: %q", gotUA)
+	}
+}
+
+func TestWithLoggerRedactsToken(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":{"gid":"1","name":"Traced"}}`))
+	}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	client := NewClient("super-secret-token", WithBaseURL(srv.URL), WithLogger(log.New(&buf, "", 0)))
+
+	if _, err := client.UpdateTask(context.Background(), "1", &TaskUpdateRequest{Name: "Traced"}); err != nil {
+		t.Fatalf("UpdateTask failed: %v", err)
+	}
+
+	trace := buf.String()
+	if strings.Contains(trace, "super-secret-token") {
+		t.Fatalf("token leaked into trace:\n%s", trace)
+	}
+	for _, want := range []string{"--> PUT ", "Authorization: Bearer ****", `"name":"Traced"`, "<-- PUT /tasks/1 200 OK"} {
+		if !strings.Contains(trace, want) {
+			t.Errorf("trace missing %q:\n%s", want, trace)
+		}
+	}
+}
+
+func TestWithDryRunSkipsWrites(t *testing.T) {
+	var methods []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		w.Write([]byte(`{"data":{"gid":"1","name":"Read"}}`))
+	}))
+	defer srv.Close()
+
+	var planned []PlannedRequest
+	client := NewClient("test-token", WithBaseURL(srv.URL), WithDryRun(func(req PlannedRequest) {
+		planned = append(planned, req)
+	}))
+	ctx := context.Background()
+
+	task, err := client.UpdateTask(ctx, "1", &TaskUpdateRequest{Name: "Renamed"})
+	if err != nil {
+		t.Fatalf("UpdateTask failed: %v", err)
+	}
+	if task == nil || task.GID != "" {
+		t.Errorf("expected an empty task, got %+v", task)
+	}
+	if err := client.DeleteTask(ctx, "1"); err != nil {
+		t.Fatalf("DeleteTask failed: %v", err)
+	}
+	if got, err := client.GetTask(ctx, "1"); err != nil || got.Name != "Read" {
+		t.Errorf("GetTask should still be sent, got %+v, %v", got, err)
+	}
+
+	if strings.Join(methods, ",") != "GET" {
+		t.Errorf("expected only the GET to be sent, got %v", methods)
+	}
+	if len(planned) != 2 || planned[0].Method != "PUT" || !strings.HasPrefix(planned[0].Endpoint, "/tasks/1?opt_fields=") || planned[1].Endpoint != "/tasks/1" {
+		t.Fatalf("unexpected planned requests: %+v", planned)
+	}
+	if payload := string(planned[0].Payload.(json.RawMessage)); payload != `{"data":{"name":"Renamed"}}` {
+		t.Errorf("unexpected payload: %s", payload)
+	}
+	if planned[1].Payload != nil {
+		t.Errorf("DELETE should have no payload, got %v", planned[1].Payload)
+	}
+}
@@ -0,0 +1,177 @@
+package asana
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strconv"
+)
+
+const (
+	// DefaultPageSize is the number of records requested per page when the
+	// client has not been configured otherwise.
+	DefaultPageSize = 100
+	// MaxPageSize is the largest page Asana will return for a list endpoint.
+	MaxPageSize = 100
+)
+
+// nextPage is the pagination cursor Asana attaches to list responses
+type nextPage struct {
+	Offset string `json:"offset"`
+	Path   string `json:"path"`
+	URI    string `json:"uri"`
+}
+
+// listPage is a single page of a paginated list response
+type listPage[T any] struct {
+	Data     []T       `json:"data"`
+	NextPage *nextPage `json:"next_page"`
+}
+
+// Iterator streams the records of a paginated list endpoint, fetching the
+// next page from the API only once the current one has been consumed.
+//
+//	it := client.IterTasks(ctx, projectGID, nil)
+//	for it.Next() {
+//		task := it.Value()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type Iterator[T any] struct {
+	ctx      context.Context
+	client   *Client
+	endpoint string
+	query    url.Values
+
+	buf     []T
+	pos     int
+	count   int
+	offset  string
+	started bool
+	done    bool
+
+	current T
+	err     error
+}
+
+func newIterator[T any](ctx context.Context, c *Client, endpoint string, query url.Values) *Iterator[T] {
+	if query == nil {
+		query = url.Values{}
+	}
+	return &Iterator[T]{
+		ctx:      ctx,
+		client:   c,
+		endpoint: endpoint,
+		query:    query,
+	}
+}
+
+// Next advances to the next record, fetching another page if needed.
+// It returns false once every page has been read, the client's max-items
+// cap has been reached, or a request fails.
+func (it *Iterator[T]) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	if max := it.client.maxItems; max > 0 && it.count >= max {
+		return false
+	}
+
+	for it.pos >= len(it.buf) {
+		if it.done {
+			return false
+		}
+		if err := it.fetch(); err != nil {
+			it.err = err
+			return false
+		}
+	}
+
+	it.current = it.buf[it.pos]
+	it.pos++
+	it.count++
+	return true
+}
+
+// Value returns the record the last call to Next advanced to
+func (it *Iterator[T]) Value() T {
+	return it.current
+}
+
+// Err returns the error that stopped iteration, if any
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// Count returns the number of records yielded so far
+func (it *Iterator[T]) Count() int {
+	return it.count
+}
+
+func (it *Iterator[T]) fetch() error {
+	if it.started && it.offset == "" {
+		it.done = true
+		return nil
+	}
+	it.started = true
+
+	limit := it.client.pageSize
+	if max := it.client.maxItems; max > 0 && max-it.count < limit {
+		limit = max - it.count
+	}
+
+	q := url.Values{}
+	for k, v := range it.query {
+		q[k] = v
+	}
+	q.Set("limit", strconv.Itoa(limit))
+	if it.offset != "" {
+		q.Set("offset", it.offset)
+	}
+
+	body, err := it.client.do(it.ctx, "GET", it.endpoint+"?"+q.Encode(), nil)
+	if err != nil {
+		return err
+	}
+
+	var page listPage[T]
+	if err := json.Unmarshal(body, &page); err != nil {
+		return err
+	}
+
+	it.buf = page.Data
+	it.pos = 0
+	it.offset = ""
+	if page.NextPage != nil {
+		it.offset = page.NextPage.Offset
+	}
+	if it.offset == "" {
+		it.done = true
+	}
+
+	return nil
+}
+
+// collect drains an iterator into a slice
+func collect[T any](it *Iterator[T]) ([]T, error) {
+	items := make([]T, 0)
+	for it.Next() {
+		items = append(items, it.Value())
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// filterValues converts the map-based filters accepted by list methods
+// into query parameters
+func filterValues(filters map[string]string) url.Values {
+	q := url.Values{}
+	for k, v := range filters {
+		q.Set(k, v)
+	}
+	return q
+}
@@ -0,0 +1,194 @@
+package asana
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RetryPolicy controls how failed requests are retried
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the first attempt
+	MaxRetries int
+	// BaseDelay is the backoff before the first retry; it doubles each attempt
+	BaseDelay time.Duration
+	// MaxDelay caps the computed backoff (Retry-After is honored as sent)
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy retries rate limits, server errors and network
+// failures up to four times with exponential backoff
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 4,
+	BaseDelay:  500 * time.Millisecond,
+	MaxDelay:   30 * time.Second,
+}
+
+const (
+	// DefaultMaxConcurrent is the default number of in-flight requests
+	// allowed per client. It matches Asana's concurrent write limit.
+	DefaultMaxConcurrent = 15
+)
+
+// isIdempotent reports whether a request with this method can be safely
+// sent more than once. POST creates resources, so it is never retried.
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// shouldRetry reports whether a failed attempt is worth retrying. err is a
+// transport error; otherwise status is the HTTP status that was returned.
+// rateLimited allows a request that is not idempotent to be retried when it
+// was rate limited, for callers such as Batch that know a 429 means none of
+// it ran.
+func shouldRetry(method string, status int, err error, rateLimited bool) bool {
+	if rateLimited && status == http.StatusTooManyRequests {
+		return true
+	}
+	if !isIdempotent(method) {
+		return false
+	}
+	if err != nil {
+		return true
+	}
+	return status == http.StatusTooManyRequests || status >= 500
+}
+
+// backoff returns how long to wait before retry number attempt (0-based).
+// A Retry-After header from the server always wins over the computed delay.
+func (p RetryPolicy) backoff(attempt int, header http.Header) time.Duration {
+	if d, ok := parseRetryAfter(header); ok {
+		return d
+	}
+
+	delay := p.BaseDelay << uint(attempt)
+	if delay <= 0 || delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+
+	// Equal jitter: wait at least half the delay so retries from
+	// concurrent callers spread out without collapsing to zero
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// parseRetryAfter reads a Retry-After header given either as a number of
+// seconds or as an HTTP date
+func parseRetryAfter(header http.Header) (time.Duration, bool) {
+	if header == nil {
+		return 0, false
+	}
+	v := header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			secs = 0
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+
+	if t, err := http.ParseTime(v); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+
+	return 0, false
+}
+
+// limiter bounds the number of concurrent requests and, optionally, the
+// number of requests started per minute
+type limiter struct {
+	slots chan struct{}
+
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+func newLimiter(maxConcurrent, perMinute int) *limiter {
+	l := &limiter{}
+	if maxConcurrent > 0 {
+		l.slots = make(chan struct{}, maxConcurrent)
+	}
+	if perMinute > 0 {
+		l.interval = time.Minute / time.Duration(perMinute)
+	}
+	return l
+}
+
+// acquire blocks until a request may start or ctx is done, and returns a
+// func that releases its concurrency slot
+func (l *limiter) acquire(ctx context.Context) (func(), error) {
+	if l.slots != nil {
+		select {
+		case l.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	release := func() {
+		if l.slots != nil {
+			<-l.slots
+		}
+	}
+
+	if err := sleepContext(ctx, l.reserve()); err != nil {
+		release()
+		return nil, err
+	}
+
+	return release, nil
+}
+
+// sleepContext waits for d or until ctx is done, whichever comes first
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// reserve claims the next start time on the per-minute schedule and
+// returns how long the caller has to wait for it
+func (l *limiter) reserve() time.Duration {
+	if l.interval == 0 {
+		return 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	wait := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	return wait
+}
@@ -0,0 +1,196 @@
+package asana
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newFlakyServer(t *testing.T, failures int32, status int, calls *int32) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(calls, 1)
+		if n <= failures {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(status)
+			w.Write([]byte(`{"errors":[{"message":"try again"}]}`))
+			return
+		}
+		w.Write([]byte(`{"data":{"gid":"1","name":"Task"}}`))
+	}))
+}
+
+func newTestClient(baseURL string) *Client {
+	client := NewClient("test-token")
+	client.baseURL = baseURL
+	client.SetRetryPolicy(RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond})
+	return client
+}
+
+func TestDoRetriesIdempotentRequests(t *testing.T) {
+	for _, status := range []int{http.StatusTooManyRequests, http.StatusServiceUnavailable} {
+		var calls int32
+		srv := newFlakyServer(t, 2, status, &calls)
+
+		task, err := newTestClient(srv.URL).GetTask(context.Background(), "1")
+		srv.Close()
+
+		if err != nil {
+			t.Fatalf("status %d: expected success after retries, got %v", status, err)
+		}
+		if task.GID != "1" {
+			t.Errorf("unexpected task: %+v", task)
+		}
+		if calls != 3 {
+			t.Errorf("status %d: expected 3 attempts, got %d", status, calls)
+		}
+	}
+}
+
+func TestDoDoesNotRetryPost(t *testing.T) {
+	var calls int32
+	srv := newFlakyServer(t, 1, http.StatusServiceUnavailable, &calls)
+	defer srv.Close()
+
+	_, err := newTestClient(srv.URL).CreateTask(context.Background(), &TaskCreateRequest{Name: "x"})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if calls != 1 {
+		t.Errorf("POST should not be retried, got %d attempts", calls)
+	}
+}
+
+func TestDoDoesNotRetryRateLimitedPost(t *testing.T) {
+	var calls int32
+	srv := newFlakyServer(t, 1, http.StatusTooManyRequests, &calls)
+	defer srv.Close()
+
+	if _, err := newTestClient(srv.URL).CreateTask(context.Background(), &TaskCreateRequest{Name: "x"}); !IsRateLimited(err) {
+		t.Fatalf("expected a rate limit error, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("POST should not be retried, got %d attempts", calls)
+	}
+}
+
+func TestBatchRetriesRateLimit(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"data":[{"status_code":200,"body":{"data":{"gid":"1","name":"Task"}}}]}`))
+	}))
+	defer srv.Close()
+
+	results, err := newTestClient(srv.URL).Batch(context.Background(), []BatchAction{CompleteTaskAction("1")})
+	if err != nil {
+		t.Fatalf("expected the rate-limited batch to be retried, got %v", err)
+	}
+	if task, err := results[0].Task(); err != nil || task.Name != "Task" {
+		t.Errorf("unexpected result: %+v, %v", task, err)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 attempts, got %d", calls)
+	}
+}
+
+func TestDoGivesUpAfterMaxRetries(t *testing.T) {
+	var calls int32
+	srv := newFlakyServer(t, 100, http.StatusBadGateway, &calls)
+	defer srv.Close()
+
+	_, err := newTestClient(srv.URL).GetTask(context.Background(), "1")
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if calls != 4 {
+		t.Errorf("expected 1 attempt + 3 retries, got %d", calls)
+	}
+}
+
+func TestDoDoesNotRetryClientErrors(t *testing.T) {
+	var calls int32
+	srv := newFlakyServer(t, 1, http.StatusNotFound, &calls)
+	defer srv.Close()
+
+	if _, err := newTestClient(srv.URL).GetTask(context.Background(), "1"); err == nil {
+		t.Fatal("expected error")
+	}
+	if calls != 1 {
+		t.Errorf("404 should not be retried, got %d attempts", calls)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	h := http.Header{}
+	if _, ok := parseRetryAfter(h); ok {
+		t.Error("missing header should not parse")
+	}
+
+	h.Set("Retry-After", "7")
+	if d, ok := parseRetryAfter(h); !ok || d != 7*time.Second {
+		t.Errorf("seconds form: got %v, %v", d, ok)
+	}
+
+	h.Set("Retry-After", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+	if d, ok := parseRetryAfter(h); !ok || d < 59*time.Minute {
+		t.Errorf("date form: got %v, %v", d, ok)
+	}
+}
+
+func TestBackoffStaysWithinBounds(t *testing.T) {
+	p := RetryPolicy{MaxRetries: 5, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	for attempt := 0; attempt < 8; attempt++ {
+		d := p.backoff(attempt, nil)
+		if d > p.MaxDelay {
+			t.Errorf("attempt %d: backoff %v exceeds max", attempt, d)
+		}
+		if attempt == 0 && d < 50*time.Millisecond {
+			t.Errorf("attempt 0: backoff %v below half the base delay", d)
+		}
+	}
+}
+
+func TestDoStopsRetryingWhenContextCancelled(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	client := newTestClient(srv.URL)
+	client.SetRetryPolicy(RetryPolicy{MaxRetries: 10, BaseDelay: time.Second, MaxDelay: time.Second})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.GetTasks(ctx, "p1", nil)
+	if ErrorCode(err) != "timeout" {
+		t.Fatalf("expected timeout, got %v", err)
+	}
+	if time.Since(start) > 500*time.Millisecond {
+		t.Errorf("backoff did not observe context deadline")
+	}
+}
+
+func TestLimiterSpacesRequests(t *testing.T) {
+	l := newLimiter(1, 600) // one request every 100ms
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		release, err := l.acquire(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		release()
+	}
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Errorf("3 requests at 600/min took only %v", elapsed)
+	}
+}
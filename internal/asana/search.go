@@ -0,0 +1,192 @@
+package asana
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Sort orders accepted by the search API
+var SearchSortOrders = []string{"modified_at", "created_at", "completed_at", "due_date", "likes"}
+
+// SearchOptions are the parameters of Asana's advanced task search. Empty
+// fields are left out of the query; dates are YYYY-MM-DD.
+type SearchOptions struct {
+	Text string
+
+	// Tasks assigned to any of these users ("me" or GIDs)
+	Assignees []string
+	// Tasks in any of these projects or sections
+	Projects []string
+	Sections []string
+	// Tasks with any of Tags, and with all of AllTags
+	Tags    []string
+	AllTags []string
+
+	DueBefore      string
+	DueAfter       string
+	DueOn          string
+	CreatedBefore  string
+	CreatedAfter   string
+	ModifiedBefore string
+	ModifiedAfter  string
+
+	Completed     *bool
+	IsSubtask     *bool
+	HasAttachment *bool
+
+	CustomFields []CustomFieldFilter
+
+	// SortBy is one of SearchSortOrders; Asana sorts by modified_at,
+	// newest first, by default
+	SortBy        string
+	SortAscending bool
+}
+
+// Custom field filter operators, named after the search parameters
+const (
+	FilterValue       = "value"
+	FilterIsSet       = "is_set"
+	FilterContains    = "contains"
+	FilterLessThan    = "less_than"
+	FilterGreaterThan = "greater_than"
+)
+
+// CustomFieldFilter restricts search results by a custom field, e.g.
+// {GID: "123", Op: FilterLessThan, Value: "5"}
+type CustomFieldFilter struct {
+	GID   string
+	Op    string
+	Value string
+}
+
+// Values encodes the options as search query parameters
+func (o *SearchOptions) Values() url.Values {
+	q := url.Values{}
+	set := func(key, value string) {
+		if value != "" {
+			q.Set(key, value)
+		}
+	}
+	list := func(key string, values []string) {
+		set(key, strings.Join(values, ","))
+	}
+	flag := func(key string, value *bool) {
+		if value != nil {
+			q.Set(key, strconv.FormatBool(*value))
+		}
+	}
+
+	set("text", o.Text)
+	list("assignee.any", o.Assignees)
+	list("projects.any", o.Projects)
+	list("sections.any", o.Sections)
+	list("tags.any", o.Tags)
+	list("tags.all", o.AllTags)
+	set("due_on.before", o.DueBefore)
+	set("due_on.after", o.DueAfter)
+	set("due_on", o.DueOn)
+	set("created_on.before", o.CreatedBefore)
+	set("created_on.after", o.CreatedAfter)
+	set("modified_on.before", o.ModifiedBefore)
+	set("modified_on.after", o.ModifiedAfter)
+	flag("completed", o.Completed)
+	flag("is_subtask", o.IsSubtask)
+	flag("has_attachment", o.HasAttachment)
+	for _, f := range o.CustomFields {
+		q.Set(fmt.Sprintf("custom_fields.%s.%s", f.GID, f.Op), f.Value)
+	}
+	set("sort_by", o.SortBy)
+	if o.SortAscending {
+		q.Set("sort_ascending", "true")
+	}
+	return q
+}
+
+// ParseCustomFieldFilter turns a filter expression into a search filter,
+// resolving the field and enum option names against defs:
+//
+//	Name=value   field equals value (an enum option name for enum fields)
+//	Name=        field is not set
+//	Name=*       field is set
+//	Name<n       number field less than n
+//	Name>n       number field greater than n
+//	Name~text    text field contains text
+func ParseCustomFieldFilter(defs []CustomField, expr string) (CustomFieldFilter, error) {
+	i := strings.IndexAny(expr, "=<>~")
+	if i <= 0 {
+		return CustomFieldFilter{}, fmt.Errorf("invalid custom field filter %q, expected Name=value, Name<n, Name>n or Name~text", expr)
+	}
+	name, op, value := strings.TrimSpace(expr[:i]), expr[i], strings.TrimSpace(expr[i+1:])
+
+	def := findCustomField(defs, name)
+	if def == nil {
+		return CustomFieldFilter{}, fmt.Errorf("unknown custom field %q (available: %s)", name, customFieldNames(defs))
+	}
+	filter := CustomFieldFilter{GID: def.GID}
+
+	switch op {
+	case '<', '>':
+		if def.Type != CustomFieldNumber {
+			return filter, fmt.Errorf("%s is a %s field; < and > only work on number fields", def.Name, def.Type)
+		}
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return filter, fmt.Errorf("%s: %q is not a number", def.Name, value)
+		}
+		filter.Op, filter.Value = FilterLessThan, value
+		if op == '>' {
+			filter.Op = FilterGreaterThan
+		}
+	case '~':
+		if def.Type != CustomFieldText {
+			return filter, fmt.Errorf("%s is a %s field; ~ only works on text fields", def.Name, def.Type)
+		}
+		filter.Op, filter.Value = FilterContains, value
+	case '=':
+		switch {
+		case value == "":
+			filter.Op, filter.Value = FilterIsSet, "false"
+		case value == "*":
+			filter.Op, filter.Value = FilterIsSet, "true"
+		case def.Type == CustomFieldEnum || def.Type == CustomFieldMultiEnum:
+			opt, err := def.option(value)
+			if err != nil {
+				return filter, fmt.Errorf("custom field %q: %w", def.Name, err)
+			}
+			filter.Op, filter.Value = FilterValue, opt.GID
+		case def.Type == CustomFieldNumber:
+			if _, err := strconv.ParseFloat(value, 64); err != nil {
+				return filter, fmt.Errorf("%s: %q is not a number", def.Name, value)
+			}
+			filter.Op, filter.Value = FilterValue, value
+		case def.Type == CustomFieldText:
+			filter.Op, filter.Value = FilterValue, value
+		default:
+			return filter, fmt.Errorf("%s is a %s field; it can only be filtered on being set (=*) or not (=)", def.Name, def.Type)
+		}
+	}
+	return filter, nil
+}
+
+// SearchTasks runs an advanced search, optionally requesting specific
+// fields instead of DefaultTaskFields
+func (c *Client) SearchTasks(ctx context.Context, workspaceGID string, opts *SearchOptions, fields ...string) ([]Task, error) {
+	return collect(c.IterSearchTasks(ctx, workspaceGID, opts, fields...))
+}
+
+// IterSearchTasks streams advanced search results page by page
+// GET /workspaces/{workspace_gid}/tasks/search
+func (c *Client) IterSearchTasks(ctx context.Context, workspaceGID string, opts *SearchOptions, fields ...string) *Iterator[Task] {
+	endpoint := fmt.Sprintf("/workspaces/%s/tasks/search", workspaceGID)
+	return newIterator[Task](ctx, c, endpoint, setFields(opts.Values(), fields, DefaultTaskFields))
+}
+
+// GetWorkspaceCustomFields retrieves the custom fields defined in a
+// workspace
+// GET /workspaces/{workspace_gid}/custom_fields
+func (c *Client) GetWorkspaceCustomFields(ctx context.Context, workspaceGID string) ([]CustomField, error) {
+	endpoint := fmt.Sprintf("/workspaces/%s/custom_fields", workspaceGID)
+	return collect(newIterator[CustomField](ctx, c, endpoint, setFields(nil, nil, DefaultCustomFieldFields)))
+}
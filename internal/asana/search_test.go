@@ -0,0 +1,79 @@
+package asana
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSearchOptionsValues(t *testing.T) {
+	yes, no := true, false
+	opts := &SearchOptions{
+		Text:          "login",
+		Assignees:     []string{"me", "42"},
+		Projects:      []string{"100"},
+		AllTags:       []string{"7", "8"},
+		DueBefore:     "2026-07-01",
+		Completed:     &no,
+		HasAttachment: &yes,
+		CustomFields:  []CustomFieldFilter{{GID: "1", Op: FilterGreaterThan, Value: "3"}},
+		SortBy:        "due_date",
+		SortAscending: true,
+	}
+
+	got := opts.Values()
+	want := map[string]string{
+		"text":                         "login",
+		"assignee.any":                 "me,42",
+		"projects.any":                 "100",
+		"tags.all":                     "7,8",
+		"due_on.before":                "2026-07-01",
+		"completed":                    "false",
+		"has_attachment":               "true",
+		"custom_fields.1.greater_than": "3",
+		"sort_by":                      "due_date",
+		"sort_ascending":               "true",
+	}
+	if len(got) != len(want) {
+		t.Errorf("got %d parameters, want %d: %v", len(got), len(want), got)
+	}
+	for k, v := range want {
+		if got.Get(k) != v {
+			t.Errorf("%s = %q, want %q", k, got.Get(k), v)
+		}
+	}
+}
+
+func TestParseCustomFieldFilter(t *testing.T) {
+	defs := testCustomFields()
+	for expr, want := range map[string]CustomFieldFilter{
+		"priority=High":    {GID: "2", Op: FilterValue, Value: "21"},
+		"Labels=backend":   {GID: "3", Op: FilterValue, Value: "32"},
+		"Story Points>3":   {GID: "1", Op: FilterGreaterThan, Value: "3"},
+		"Story Points<2.5": {GID: "1", Op: FilterLessThan, Value: "2.5"},
+		"Sprint~12":        {GID: "6", Op: FilterContains, Value: "12"},
+		"Sprint=12":        {GID: "6", Op: FilterValue, Value: "12"},
+		"Launch=*":         {GID: "4", Op: FilterIsSet, Value: "true"},
+		"Reviewers=":       {GID: "5", Op: FilterIsSet, Value: "false"},
+	} {
+		got, err := ParseCustomFieldFilter(defs, expr)
+		if err != nil {
+			t.Errorf("%s: %v", expr, err)
+		} else if got != want {
+			t.Errorf("%s = %+v, want %+v", expr, got, want)
+		}
+	}
+
+	for expr, msg := range map[string]string{
+		"Points":            "invalid custom field filter",
+		"Effort=3":          `unknown custom field "Effort"`,
+		"Priority=Urgent":   `no option "Urgent"`,
+		"Sprint>3":          "only work on number fields",
+		"Story Points~1":    "only works on text fields",
+		"Story Points=abc":  "is not a number",
+		"Launch=2026-01-01": "can only be filtered on being set",
+	} {
+		if _, err := ParseCustomFieldFilter(defs, expr); err == nil || !strings.Contains(err.Error(), msg) {
+			t.Errorf("%s: got error %v, want %q", expr, err, msg)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package asanatest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+
+	"github.com/TheCoolRobot/asana-cli/internal/asana"
+)
+
+// batch runs each action of a POST /batch as its own request and returns
+// their statuses and bodies in order, the way Asana does
+func (s *Server) batch(w http.ResponseWriter, r *http.Request) {
+	var envelope struct {
+		Data struct {
+			Actions []asana.BatchAction `json:"actions"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&envelope); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("request body is not valid JSON: %v", err))
+		return
+	}
+	actions := envelope.Data.Actions
+	if len(actions) == 0 || len(actions) > asana.MaxBatchActions {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("actions: Must contain between 1 and %d actions", asana.MaxBatchActions))
+		return
+	}
+
+	type result struct {
+		StatusCode int               `json:"status_code"`
+		Headers    map[string]string `json:"headers"`
+		Body       json.RawMessage   `json:"body"`
+	}
+	results := make([]result, len(actions))
+	for i, action := range actions {
+		target := action.RelativePath
+		if action.Options != nil && len(action.Options.Fields) > 0 {
+			target += "?" + url.Values{"opt_fields": {strings.Join(action.Options.Fields, ",")}}.Encode()
+		}
+		var body []byte
+		if action.Data != nil {
+			body, _ = json.Marshal(map[string]interface{}{"data": action.Data})
+		}
+
+		req := httptest.NewRequest(strings.ToUpper(action.Method), target, bytes.NewReader(body))
+		req.Header.Set("Authorization", r.Header.Get("Authorization"))
+		rec := httptest.NewRecorder()
+		s.dispatch(rec, req)
+
+		respBody := bytes.TrimSpace(rec.Body.Bytes())
+		if !json.Valid(respBody) {
+			// e.g. the mux's plain-text 404 for an unknown route
+			respBody, _ = json.Marshal(map[string]interface{}{
+				"errors": []map[string]string{{"message": string(respBody)}},
+			})
+		}
+		results[i] = result{
+			StatusCode: rec.Code,
+			Headers:    map[string]string{},
+			Body:       respBody,
+		}
+	}
+	writeData(w, http.StatusOK, results)
+}
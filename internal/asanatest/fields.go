@@ -0,0 +1,38 @@
+package asanatest
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/TheCoolRobot/asana-cli/internal/asana"
+)
+
+// selectFields shapes a response record the way Asana does: only the
+// fields listed in opt_fields (plus gid) are returned. Without opt_fields,
+// list endpoints return compact records and single-record endpoints return
+// everything.
+func selectFields(r *http.Request, v interface{}, compact bool) interface{} {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var generic interface{}
+	if err := json.Unmarshal(raw, &generic); err != nil {
+		return v
+	}
+
+	optFields := r.URL.Query().Get("opt_fields")
+	if optFields == "" {
+		if !compact {
+			return generic
+		}
+		optFields = "name"
+	}
+
+	return asana.SelectFields(generic, asana.ParseFields(optFields))
+}
+
+// writeRecord writes a single-record response shaped by opt_fields
+func writeRecord(w http.ResponseWriter, r *http.Request, status int, data interface{}) {
+	writeData(w, status, selectFields(r, data, false))
+}
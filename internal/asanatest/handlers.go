@@ -0,0 +1,1235 @@
+package asanatest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TheCoolRobot/asana-cli/internal/asana"
+)
+
+func (s *Server) routes() {
+	s.handle("GET /users/me", s.getMe)
+	s.handle("GET /users/{gid}", s.getUser)
+	s.handle("GET /users/{gid}/user_task_list", s.getUserTaskList)
+	s.handle("GET /users/{gid}/teams", s.getUserTeams)
+
+	s.handle("GET /workspaces", s.getWorkspaces)
+	s.handle("PUT /workspaces/{gid}", s.updateWorkspace)
+	s.handle("GET /workspaces/{gid}/tasks", s.getWorkspaceTasks)
+	s.handle("GET /workspaces/{gid}/tasks/search", s.searchTasks)
+	s.handle("GET /workspaces/{gid}/teams", s.getTeams)
+	s.handle("GET /workspaces/{gid}/custom_fields", s.getWorkspaceCustomFields)
+
+	s.handle("GET /projects", s.getProjects)
+	s.handle("POST /projects", s.createProject)
+	s.handle("GET /projects/{gid}", s.getProject)
+	s.handle("PUT /projects/{gid}", s.updateProject)
+	s.handle("DELETE /projects/{gid}", s.deleteProject)
+	s.handle("POST /projects/{gid}/duplicate", s.duplicateProject)
+	s.handle("GET /projects/{gid}/tasks", s.getProjectTasks)
+	s.handle("GET /projects/{gid}/sections", s.getSections)
+	s.handle("POST /projects/{gid}/sections", s.createSection)
+	s.handle("POST /projects/{gid}/sections/insert", s.insertSection)
+	s.handle("GET /projects/{gid}/custom_field_settings", s.getCustomFieldSettings)
+
+	s.handle("GET /tags", s.getTags)
+	s.handle("POST /tags", s.createTag)
+	s.handle("GET /tags/{gid}", s.getTag)
+	s.handle("PUT /tags/{gid}", s.updateTag)
+	s.handle("DELETE /tags/{gid}", s.deleteTag)
+
+	s.handle("GET /sections/{gid}", s.getSection)
+	s.handle("PUT /sections/{gid}", s.updateSection)
+	s.handle("DELETE /sections/{gid}", s.deleteSection)
+	s.handle("GET /sections/{gid}/tasks", s.getSectionTasks)
+	s.handle("POST /sections/{gid}/addTask", s.addTaskToSection)
+
+	s.handle("POST /tasks", s.createTask)
+	s.handle("GET /tasks/{gid}", s.getTask)
+	s.handle("PUT /tasks/{gid}", s.updateTask)
+	s.handle("DELETE /tasks/{gid}", s.deleteTask)
+	s.handle("GET /tasks/{gid}/subtasks", s.getSubtasks)
+	s.handle("POST /tasks/{gid}/setParent", s.setParent)
+	s.handle("POST /tasks/{gid}/addTag", s.addTag)
+	s.handle("POST /tasks/{gid}/removeTag", s.removeTag)
+	s.handle("GET /tasks/{gid}/dependencies", s.getDependencies)
+	s.handle("GET /tasks/{gid}/dependents", s.getDependents)
+	s.handle("POST /tasks/{gid}/addDependencies", s.addDependencies)
+	s.handle("POST /tasks/{gid}/removeDependencies", s.removeDependencies)
+	s.handle("GET /tasks/{gid}/attachments", s.getAttachments)
+	s.handle("POST /tasks/{gid}/attachments", s.uploadAttachment)
+	s.handle("GET /attachments/{gid}", s.getAttachment)
+	s.handle("GET /files/{gid}", s.downloadFile)
+	s.handle("GET /tasks/{gid}/stories", s.getStories)
+	s.handle("POST /tasks/{gid}/stories", s.createStory)
+
+	s.handle("GET /jobs/{gid}", s.getJob)
+
+	// Batch actions lock the store one by one
+	s.mux.HandleFunc("POST /batch", s.batch)
+}
+
+func (s *Server) getMe(w http.ResponseWriter, r *http.Request) {
+	writeRecord(w, r, http.StatusOK, s.store.users[s.store.me])
+}
+
+func (s *Server) getUser(w http.ResponseWriter, r *http.Request) {
+	u, ok := s.store.user(r.PathValue("gid"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "user: Not a recognized ID: "+r.PathValue("gid"))
+		return
+	}
+	writeRecord(w, r, http.StatusOK, u)
+}
+
+func (s *Server) getUserTaskList(w http.ResponseWriter, r *http.Request) {
+	u, ok := s.store.user(r.PathValue("gid"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "user: Not a recognized ID: "+r.PathValue("gid"))
+		return
+	}
+
+	tasks := make([]asana.Task, 0)
+	for _, gid := range s.store.taskOrder {
+		t := s.store.tasks[gid]
+		if t.Assignee != nil && t.Assignee.GID == u.GID {
+			tasks = append(tasks, t.Task)
+		}
+	}
+	writeList(w, r, tasks)
+}
+
+func (s *Server) getUserTeams(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.store.user(r.PathValue("gid")); !ok {
+		writeError(w, http.StatusNotFound, "user: Not a recognized ID: "+r.PathValue("gid"))
+		return
+	}
+	writeList(w, r, []asana.Team{})
+}
+
+func (s *Server) getWorkspaces(w http.ResponseWriter, r *http.Request) {
+	workspaces := make([]asana.Workspace, 0, len(s.store.workspaces))
+	for _, ws := range s.store.workspaces {
+		workspaces = append(workspaces, *ws)
+	}
+	writeList(w, r, workspaces)
+}
+
+func (s *Server) updateWorkspace(w http.ResponseWriter, r *http.Request) {
+	ws, ok := s.store.workspace(r.PathValue("gid"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "workspace: Not a recognized ID: "+r.PathValue("gid"))
+		return
+	}
+
+	data, err := readData(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if raw, ok := data["name"]; ok {
+		json.Unmarshal(raw, &ws.Name)
+	}
+	writeRecord(w, r, http.StatusOK, ws)
+}
+
+func (s *Server) getProjects(w http.ResponseWriter, r *http.Request) {
+	wsGID := r.URL.Query().Get("workspace")
+	if wsGID == "" {
+		writeError(w, http.StatusBadRequest, "workspace: Missing input")
+		return
+	}
+
+	q := r.URL.Query()
+	projects := make([]asana.Project, 0)
+	for _, gid := range s.store.projectOrder {
+		p := s.store.projects[gid]
+		if p.workspace != wsGID {
+			continue
+		}
+		if archived := q.Get("archived"); archived != "" && strconv.FormatBool(p.Archived) != archived {
+			continue
+		}
+		if teamGID := q.Get("team"); teamGID != "" && (p.Team == nil || p.Team.GID != teamGID) {
+			continue
+		}
+		projects = append(projects, p.Project)
+	}
+	writeList(w, r, projects)
+}
+
+func (s *Server) getTeams(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.store.workspace(r.PathValue("gid")); !ok {
+		writeError(w, http.StatusNotFound, "workspace: Not a recognized ID: "+r.PathValue("gid"))
+		return
+	}
+
+	teams := make([]asana.Team, 0)
+	for _, t := range s.store.teams {
+		if t.workspace == r.PathValue("gid") {
+			teams = append(teams, t.Team)
+		}
+	}
+	writeList(w, r, teams)
+}
+
+func (s *Server) getProject(w http.ResponseWriter, r *http.Request) {
+	p, ok := s.store.projects[r.PathValue("gid")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "project: Not a recognized ID: "+r.PathValue("gid"))
+		return
+	}
+	writeRecord(w, r, http.StatusOK, p.Project)
+}
+
+func (s *Server) createProject(w http.ResponseWriter, r *http.Request) {
+	data, err := readData(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	now := time.Now().UTC()
+	p := &project{Project: asana.Project{GID: s.store.newGID(), CreatedAt: now, ModifiedAt: now}}
+
+	var wsGID, teamGID string
+	json.Unmarshal(data["workspace"], &wsGID)
+	json.Unmarshal(data["team"], &teamGID)
+	if teamGID != "" {
+		tm, ok := s.store.team(teamGID)
+		if !ok {
+			writeError(w, http.StatusBadRequest, "team: Not a recognized ID: "+teamGID)
+			return
+		}
+		p.Team = &asana.Team{GID: tm.GID, Name: tm.Name}
+		wsGID = tm.workspace
+	}
+	if _, ok := s.store.workspace(wsGID); !ok {
+		writeError(w, http.StatusBadRequest, "workspace: Missing input")
+		return
+	}
+	p.workspace = wsGID
+
+	if err := applyProjectFields(p, data); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if p.Name == "" {
+		writeError(w, http.StatusBadRequest, "name: Missing input")
+		return
+	}
+
+	// Asana gives every new project a first section
+	sec := &section{
+		Section: asana.Section{GID: s.store.newGID(), Name: "Untitled section", ProjectID: p.GID},
+		project: p.GID,
+	}
+	s.store.sections[sec.GID] = sec
+	p.sections = []string{sec.GID}
+
+	s.store.projects[p.GID] = p
+	s.store.projectOrder = append(s.store.projectOrder, p.GID)
+	writeRecord(w, r, http.StatusCreated, p.Project)
+}
+
+func (s *Server) updateProject(w http.ResponseWriter, r *http.Request) {
+	p, ok := s.store.projects[r.PathValue("gid")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "project: Not a recognized ID: "+r.PathValue("gid"))
+		return
+	}
+
+	data, err := readData(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Validate on a copy so a rejected update leaves the project intact
+	updated := *p
+	if raw, ok := data["team"]; ok {
+		var teamGID string
+		json.Unmarshal(raw, &teamGID)
+		tm, ok := s.store.team(teamGID)
+		if !ok || tm.workspace != p.workspace {
+			writeError(w, http.StatusBadRequest, "team: Not a recognized ID: "+teamGID)
+			return
+		}
+		updated.Team = &asana.Team{GID: tm.GID, Name: tm.Name}
+	}
+	if err := applyProjectFields(&updated, data); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	updated.ModifiedAt = time.Now().UTC()
+	*p = updated
+
+	for _, t := range s.store.projectTasks(p.GID) {
+		for i := range t.Projects {
+			if t.Projects[i].GID == p.GID {
+				t.Projects[i].Name = p.Name
+			}
+		}
+		s.store.refreshMemberships(t)
+	}
+	writeRecord(w, r, http.StatusOK, p.Project)
+}
+
+func (s *Server) deleteProject(w http.ResponseWriter, r *http.Request) {
+	p, ok := s.store.projects[r.PathValue("gid")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "project: Not a recognized ID: "+r.PathValue("gid"))
+		return
+	}
+	s.store.deleteProject(p)
+	writeData(w, http.StatusOK, struct{}{})
+}
+
+func (s *Server) duplicateProject(w http.ResponseWriter, r *http.Request) {
+	src, ok := s.store.projects[r.PathValue("gid")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "project: Not a recognized ID: "+r.PathValue("gid"))
+		return
+	}
+
+	var req asana.ProjectDuplicateRequest
+	if err := readInto(r, &req); err != nil || strings.TrimSpace(req.Name) == "" {
+		writeError(w, http.StatusBadRequest, "name: Missing input")
+		return
+	}
+	if _, ok := s.store.team(req.Team); req.Team != "" && !ok {
+		writeError(w, http.StatusBadRequest, "team: Not a recognized ID: "+req.Team)
+		return
+	}
+	include := make(map[string]bool)
+	for _, part := range strings.Split(req.Include, ",") {
+		include[strings.TrimSpace(part)] = true
+	}
+
+	p := s.store.duplicateProject(src, req.Name, req.Team, include)
+
+	// The copy is made at once, but like Asana's the job only reports
+	// success when it is next polled
+	job := &asana.Job{
+		GID:        s.store.newGID(),
+		Status:     asana.JobInProgress,
+		Type:       "duplicate_project",
+		NewProject: &asana.Project{GID: p.GID, Name: p.Name},
+	}
+	s.store.jobs[job.GID] = job
+	writeRecord(w, r, http.StatusCreated, *job)
+}
+
+func (s *Server) getJob(w http.ResponseWriter, r *http.Request) {
+	job, ok := s.store.jobs[r.PathValue("gid")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "job: Not a recognized ID: "+r.PathValue("gid"))
+		return
+	}
+	job.Status = asana.JobSucceeded
+	writeRecord(w, r, http.StatusOK, *job)
+}
+
+// applyProjectFields copies the writable fields in data onto p
+func applyProjectFields(p *project, data map[string]json.RawMessage) error {
+	if raw, ok := data["name"]; ok {
+		json.Unmarshal(raw, &p.Name)
+	}
+	if raw, ok := data["notes"]; ok {
+		json.Unmarshal(raw, &p.Description)
+	}
+	if raw, ok := data["color"]; ok {
+		json.Unmarshal(raw, &p.Color)
+		if !contains(asana.ProjectColors, p.Color) {
+			return fmt.Errorf("color: Invalid value %q", p.Color)
+		}
+	}
+	if raw, ok := data["default_view"]; ok {
+		json.Unmarshal(raw, &p.DefaultView)
+		if !contains(asana.ProjectViews, p.DefaultView) {
+			return fmt.Errorf("default_view: Invalid value %q", p.DefaultView)
+		}
+	}
+	if raw, ok := data["due_on"]; ok {
+		if string(raw) == "null" {
+			p.DueOn = nil
+		} else if err := json.Unmarshal(raw, &p.DueOn); err != nil {
+			return fmt.Errorf("due_on: Invalid date: %s", raw)
+		}
+	}
+	if raw, ok := data["public"]; ok {
+		json.Unmarshal(raw, &p.Public)
+	}
+	if raw, ok := data["archived"]; ok {
+		json.Unmarshal(raw, &p.Archived)
+	}
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) getProjectTasks(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.store.projects[r.PathValue("gid")]; !ok {
+		writeError(w, http.StatusNotFound, "project: Not a recognized ID: "+r.PathValue("gid"))
+		return
+	}
+
+	tasks, err := s.filterTasks(r, s.store.projectTasks(r.PathValue("gid")))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeList(w, r, tasks)
+}
+
+func (s *Server) getWorkspaceTasks(w http.ResponseWriter, r *http.Request) {
+	wsGID := r.PathValue("gid")
+	if _, ok := s.store.workspace(wsGID); !ok {
+		writeError(w, http.StatusNotFound, "workspace: Not a recognized ID: "+wsGID)
+		return
+	}
+
+	var candidates []*task
+	for _, gid := range s.store.taskOrder {
+		if t := s.store.tasks[gid]; t.workspace == wsGID {
+			candidates = append(candidates, t)
+		}
+	}
+
+	tasks, err := s.filterTasks(r, candidates)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeList(w, r, tasks)
+}
+
+// filterTasks applies the completed_since and assignee query filters
+func (s *Server) filterTasks(r *http.Request, candidates []*task) ([]asana.Task, error) {
+	q := r.URL.Query()
+
+	var assignee *asana.User
+	if ref := q.Get("assignee"); ref != "" {
+		u, ok := s.store.user(ref)
+		if !ok {
+			return nil, fmt.Errorf("assignee: Not a recognized ID: %s", ref)
+		}
+		assignee = u
+	}
+
+	incompleteOnly := q.Get("completed_since") == "now"
+
+	tasks := make([]asana.Task, 0, len(candidates))
+	for _, t := range candidates {
+		if incompleteOnly && t.Completed {
+			continue
+		}
+		if assignee != nil && (t.Assignee == nil || t.Assignee.GID != assignee.GID) {
+			continue
+		}
+		tasks = append(tasks, t.Task)
+	}
+	return tasks, nil
+}
+
+func (s *Server) getSections(w http.ResponseWriter, r *http.Request) {
+	p, ok := s.store.projects[r.PathValue("gid")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "project: Not a recognized ID: "+r.PathValue("gid"))
+		return
+	}
+
+	sections := make([]asana.Section, 0, len(p.sections))
+	for _, gid := range p.sections {
+		sections = append(sections, s.store.sections[gid].Section)
+	}
+	writeList(w, r, sections)
+}
+
+func (s *Server) createSection(w http.ResponseWriter, r *http.Request) {
+	p, ok := s.store.projects[r.PathValue("gid")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "project: Not a recognized ID: "+r.PathValue("gid"))
+		return
+	}
+
+	var req asana.SectionRequest
+	if err := readInto(r, &req); err != nil || strings.TrimSpace(req.Name) == "" {
+		writeError(w, http.StatusBadRequest, "name: Missing input")
+		return
+	}
+
+	sec := &section{
+		Section: asana.Section{GID: s.store.newGID(), Name: req.Name, ProjectID: p.GID},
+		project: p.GID,
+	}
+	s.store.sections[sec.GID] = sec
+	p.sections = insertAt(p.sections, sec.GID, req.InsertBefore, req.InsertAfter)
+	writeRecord(w, r, http.StatusCreated, sec.Section)
+}
+
+func (s *Server) insertSection(w http.ResponseWriter, r *http.Request) {
+	p, ok := s.store.projects[r.PathValue("gid")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "project: Not a recognized ID: "+r.PathValue("gid"))
+		return
+	}
+
+	var req struct {
+		Section       string `json:"section"`
+		BeforeSection string `json:"before_section"`
+		AfterSection  string `json:"after_section"`
+	}
+	if err := readInto(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if (req.BeforeSection == "") == (req.AfterSection == "") {
+		writeError(w, http.StatusBadRequest, "Exactly one of before_section or after_section must be specified")
+		return
+	}
+	for _, gid := range []string{req.Section, req.BeforeSection + req.AfterSection} {
+		if sec, ok := s.store.sections[gid]; !ok || sec.project != p.GID {
+			writeError(w, http.StatusBadRequest, "section: Not a recognized ID in this project: "+gid)
+			return
+		}
+	}
+
+	p.sections = insertAt(without(p.sections, req.Section), req.Section, req.BeforeSection, req.AfterSection)
+	writeData(w, http.StatusOK, struct{}{})
+}
+
+func (s *Server) getTags(w http.ResponseWriter, r *http.Request) {
+	wsGID := r.URL.Query().Get("workspace")
+	if wsGID == "" {
+		writeError(w, http.StatusBadRequest, "workspace: Missing input")
+		return
+	}
+
+	tags := make([]asana.Tag, 0)
+	for _, gid := range s.store.tagOrder {
+		if tg := s.store.tags[gid]; tg.workspace == wsGID {
+			tags = append(tags, tg.Tag)
+		}
+	}
+	writeList(w, r, tags)
+}
+
+func (s *Server) createTag(w http.ResponseWriter, r *http.Request) {
+	var req asana.TagRequest
+	if err := readInto(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if _, ok := s.store.workspace(req.Workspace); !ok {
+		writeError(w, http.StatusBadRequest, "workspace: Missing input")
+		return
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		writeError(w, http.StatusBadRequest, "name: Missing input")
+		return
+	}
+	if req.Color != "" && !contains(asana.ProjectColors, req.Color) {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("color: Invalid value %q", req.Color))
+		return
+	}
+
+	tg := &tag{
+		Tag:       asana.Tag{GID: s.store.newGID(), Name: req.Name, Color: req.Color, Notes: req.Notes},
+		workspace: req.Workspace,
+	}
+	s.store.tags[tg.GID] = tg
+	s.store.tagOrder = append(s.store.tagOrder, tg.GID)
+	writeRecord(w, r, http.StatusCreated, tg.Tag)
+}
+
+func (s *Server) getTag(w http.ResponseWriter, r *http.Request) {
+	tg, ok := s.store.tags[r.PathValue("gid")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "tag: Not a recognized ID: "+r.PathValue("gid"))
+		return
+	}
+	writeRecord(w, r, http.StatusOK, tg.Tag)
+}
+
+func (s *Server) updateTag(w http.ResponseWriter, r *http.Request) {
+	tg, ok := s.store.tags[r.PathValue("gid")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "tag: Not a recognized ID: "+r.PathValue("gid"))
+		return
+	}
+
+	var req asana.TagRequest
+	if err := readInto(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.Color != "" && !contains(asana.ProjectColors, req.Color) {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("color: Invalid value %q", req.Color))
+		return
+	}
+	if req.Name != "" {
+		tg.Name = req.Name
+	}
+	if req.Color != "" {
+		tg.Color = req.Color
+	}
+	if req.Notes != "" {
+		tg.Notes = req.Notes
+	}
+
+	for _, t := range s.store.tasks {
+		for i := range t.Tags {
+			if t.Tags[i].GID == tg.GID {
+				t.Tags[i].Name = tg.Name
+			}
+		}
+	}
+	writeRecord(w, r, http.StatusOK, tg.Tag)
+}
+
+func (s *Server) deleteTag(w http.ResponseWriter, r *http.Request) {
+	tg, ok := s.store.tags[r.PathValue("gid")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "tag: Not a recognized ID: "+r.PathValue("gid"))
+		return
+	}
+
+	for _, t := range s.store.tasks {
+		s.store.removeTag(t, tg.GID)
+	}
+	delete(s.store.tags, tg.GID)
+	s.store.tagOrder = without(s.store.tagOrder, tg.GID)
+	writeData(w, http.StatusOK, struct{}{})
+}
+
+func (s *Server) addTag(w http.ResponseWriter, r *http.Request) {
+	s.changeTag(w, r, func(t *task, tg *tag) { s.store.addTag(t, tg) })
+}
+
+func (s *Server) removeTag(w http.ResponseWriter, r *http.Request) {
+	s.changeTag(w, r, func(t *task, tg *tag) { s.store.removeTag(t, tg.GID) })
+}
+
+func (s *Server) changeTag(w http.ResponseWriter, r *http.Request, change func(t *task, tg *tag)) {
+	t, ok := s.store.tasks[r.PathValue("gid")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "task: Not a recognized ID: "+r.PathValue("gid"))
+		return
+	}
+
+	var req struct {
+		Tag string `json:"tag"`
+	}
+	if err := readInto(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	tg, ok := s.store.tags[req.Tag]
+	if !ok {
+		writeError(w, http.StatusBadRequest, "tag: Not a recognized ID: "+req.Tag)
+		return
+	}
+
+	change(t, tg)
+	t.ModifiedAt = time.Now().UTC()
+	writeData(w, http.StatusOK, struct{}{})
+}
+
+func (s *Server) getSection(w http.ResponseWriter, r *http.Request) {
+	sec, ok := s.store.sections[r.PathValue("gid")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "section: Not a recognized ID: "+r.PathValue("gid"))
+		return
+	}
+	writeRecord(w, r, http.StatusOK, sec.Section)
+}
+
+func (s *Server) updateSection(w http.ResponseWriter, r *http.Request) {
+	sec, ok := s.store.sections[r.PathValue("gid")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "section: Not a recognized ID: "+r.PathValue("gid"))
+		return
+	}
+
+	var req asana.SectionRequest
+	if err := readInto(r, &req); err != nil || strings.TrimSpace(req.Name) == "" {
+		writeError(w, http.StatusBadRequest, "name: Missing input")
+		return
+	}
+	sec.Name = req.Name
+	for _, gid := range sec.tasks {
+		s.store.refreshMemberships(s.store.tasks[gid])
+	}
+	writeRecord(w, r, http.StatusOK, sec.Section)
+}
+
+func (s *Server) deleteSection(w http.ResponseWriter, r *http.Request) {
+	sec, ok := s.store.sections[r.PathValue("gid")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "section: Not a recognized ID: "+r.PathValue("gid"))
+		return
+	}
+	if len(sec.tasks) > 0 {
+		writeError(w, http.StatusBadRequest, "Sections must be empty to be deleted.")
+		return
+	}
+	p := s.store.projects[sec.project]
+	if p != nil && len(p.sections) == 1 {
+		writeError(w, http.StatusBadRequest, "You cannot delete the last section of a project.")
+		return
+	}
+
+	if p != nil {
+		p.sections = without(p.sections, sec.GID)
+	}
+	delete(s.store.sections, sec.GID)
+	writeData(w, http.StatusOK, struct{}{})
+}
+
+func (s *Server) getSectionTasks(w http.ResponseWriter, r *http.Request) {
+	sec, ok := s.store.sections[r.PathValue("gid")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "section: Not a recognized ID: "+r.PathValue("gid"))
+		return
+	}
+
+	tasks := make([]asana.Task, 0, len(sec.tasks))
+	for _, gid := range sec.tasks {
+		tasks = append(tasks, s.store.tasks[gid].Task)
+	}
+	writeList(w, r, tasks)
+}
+
+func (s *Server) addTaskToSection(w http.ResponseWriter, r *http.Request) {
+	sec, ok := s.store.sections[r.PathValue("gid")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "section: Not a recognized ID: "+r.PathValue("gid"))
+		return
+	}
+
+	var req struct {
+		Task         string `json:"task"`
+		InsertBefore string `json:"insert_before"`
+		InsertAfter  string `json:"insert_after"`
+	}
+	if err := readInto(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	t, ok := s.store.tasks[req.Task]
+	if !ok {
+		writeError(w, http.StatusBadRequest, "task: Not a recognized ID: "+req.Task)
+		return
+	}
+
+	s.store.addToSection(t, sec, req.InsertBefore, req.InsertAfter)
+	writeData(w, http.StatusOK, struct{}{})
+}
+
+func (s *Server) getWorkspaceCustomFields(w http.ResponseWriter, r *http.Request) {
+	wsGID := r.PathValue("gid")
+	if _, ok := s.store.workspace(wsGID); !ok {
+		writeError(w, http.StatusNotFound, "workspace: Not a recognized ID: "+wsGID)
+		return
+	}
+
+	// Custom fields belong to the workspace of the projects using them
+	fields := make([]asana.CustomField, 0)
+	seen := make(map[string]bool)
+	for _, gid := range s.store.projectOrder {
+		p := s.store.projects[gid]
+		if p.workspace != wsGID {
+			continue
+		}
+		for _, fgid := range p.customFields {
+			if !seen[fgid] {
+				seen[fgid] = true
+				fields = append(fields, *s.store.customFields[fgid])
+			}
+		}
+	}
+	writeList(w, r, fields)
+}
+
+func (s *Server) getCustomFieldSettings(w http.ResponseWriter, r *http.Request) {
+	p, ok := s.store.projects[r.PathValue("gid")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "project: Not a recognized ID: "+r.PathValue("gid"))
+		return
+	}
+
+	type setting struct {
+		GID         string            `json:"gid"`
+		CustomField asana.CustomField `json:"custom_field"`
+	}
+	settings := make([]setting, 0, len(p.customFields))
+	for _, gid := range p.customFields {
+		settings = append(settings, setting{GID: p.GID + "-" + gid, CustomField: *s.store.customFields[gid]})
+	}
+	writeList(w, r, settings)
+}
+
+func (s *Server) getTask(w http.ResponseWriter, r *http.Request) {
+	t, ok := s.store.tasks[r.PathValue("gid")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "task: Not a recognized ID: "+r.PathValue("gid"))
+		return
+	}
+	writeRecord(w, r, http.StatusOK, t.Task)
+}
+
+func (s *Server) createTask(w http.ResponseWriter, r *http.Request) {
+	data, err := readData(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	now := time.Now().UTC()
+	t := &task{Task: asana.Task{GID: s.store.newGID(), CreatedAt: now, ModifiedAt: now}}
+
+	if raw, ok := data["projects"]; ok {
+		var gids []string
+		if err := json.Unmarshal(raw, &gids); err != nil {
+			writeError(w, http.StatusBadRequest, "projects: Not an array")
+			return
+		}
+		for _, gid := range gids {
+			p, ok := s.store.projects[gid]
+			if !ok {
+				writeError(w, http.StatusBadRequest, "projects: Not a recognized ID: "+gid)
+				return
+			}
+			t.Projects = append(t.Projects, compactProject(p))
+			t.workspace = p.workspace
+			s.store.addProjectFields(t, p)
+		}
+	}
+
+	var placements []*section
+	if raw, ok := data["memberships"]; ok {
+		var memberships []asana.MembershipRequest
+		if err := json.Unmarshal(raw, &memberships); err != nil {
+			writeError(w, http.StatusBadRequest, "memberships: Not an array")
+			return
+		}
+		for _, m := range memberships {
+			p, ok := s.store.projects[m.Project]
+			if !ok {
+				writeError(w, http.StatusBadRequest, "memberships: Not a recognized project ID: "+m.Project)
+				return
+			}
+			if !t.inProject(p.GID) {
+				t.Projects = append(t.Projects, compactProject(p))
+				t.workspace = p.workspace
+				s.store.addProjectFields(t, p)
+			}
+			if m.Section == "" {
+				continue
+			}
+			sec, ok := s.store.sections[m.Section]
+			if !ok || sec.project != m.Project {
+				writeError(w, http.StatusBadRequest, "memberships: Section "+m.Section+" is not in project "+m.Project)
+				return
+			}
+			placements = append(placements, sec)
+		}
+	}
+
+	if err := s.applyTaskFields(t, data); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if t.Name == "" {
+		writeError(w, http.StatusBadRequest, "name: Missing input")
+		return
+	}
+
+	if raw, ok := data["tags"]; ok {
+		var gids []string
+		if err := json.Unmarshal(raw, &gids); err != nil {
+			writeError(w, http.StatusBadRequest, "tags: Not an array")
+			return
+		}
+		for _, gid := range gids {
+			tg, ok := s.store.tags[gid]
+			if !ok {
+				writeError(w, http.StatusBadRequest, "tags: Not a recognized ID: "+gid)
+				return
+			}
+			s.store.addTag(t, tg)
+		}
+	}
+
+	if raw, ok := data["parent"]; ok {
+		var gid string
+		json.Unmarshal(raw, &gid)
+		if err := s.store.setParent(t, gid, "", ""); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	s.store.tasks[t.GID] = t
+	s.store.taskOrder = append(s.store.taskOrder, t.GID)
+	for _, sec := range placements {
+		s.store.addToSection(t, sec, "", "")
+	}
+	s.store.refreshMemberships(t)
+	writeRecord(w, r, http.StatusCreated, t.Task)
+}
+
+func (s *Server) updateTask(w http.ResponseWriter, r *http.Request) {
+	t, ok := s.store.tasks[r.PathValue("gid")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "task: Not a recognized ID: "+r.PathValue("gid"))
+		return
+	}
+
+	data, err := readData(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Validate against a copy so a bad field leaves the task untouched
+	updated := *t
+	if err := s.applyTaskFields(&updated, data); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	updated.ModifiedAt = time.Now().UTC()
+	*t = updated
+
+	writeRecord(w, r, http.StatusOK, t.Task)
+}
+
+func (s *Server) deleteTask(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.store.tasks[r.PathValue("gid")]; !ok {
+		writeError(w, http.StatusNotFound, "task: Not a recognized ID: "+r.PathValue("gid"))
+		return
+	}
+	s.store.deleteTask(r.PathValue("gid"))
+	writeData(w, http.StatusOK, struct{}{})
+}
+
+func (s *Server) getSubtasks(w http.ResponseWriter, r *http.Request) {
+	t, ok := s.store.tasks[r.PathValue("gid")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "task: Not a recognized ID: "+r.PathValue("gid"))
+		return
+	}
+
+	tasks := make([]asana.Task, 0, len(t.subtasks))
+	for _, gid := range t.subtasks {
+		tasks = append(tasks, s.store.tasks[gid].Task)
+	}
+	writeList(w, r, tasks)
+}
+
+func (s *Server) setParent(w http.ResponseWriter, r *http.Request) {
+	t, ok := s.store.tasks[r.PathValue("gid")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "task: Not a recognized ID: "+r.PathValue("gid"))
+		return
+	}
+
+	data, err := readData(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	raw, ok := data["parent"]
+	if !ok {
+		writeError(w, http.StatusBadRequest, "parent: Missing input")
+		return
+	}
+
+	var parent, before, after *string
+	json.Unmarshal(raw, &parent)
+	json.Unmarshal(data["insert_before"], &before)
+	json.Unmarshal(data["insert_after"], &after)
+	deref := func(p *string) string {
+		if p == nil {
+			return ""
+		}
+		return *p
+	}
+
+	if err := s.store.setParent(t, deref(parent), deref(before), deref(after)); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	t.ModifiedAt = time.Now().UTC()
+	writeRecord(w, r, http.StatusOK, t.Task)
+}
+
+func (s *Server) getDependencies(w http.ResponseWriter, r *http.Request) {
+	t, ok := s.store.tasks[r.PathValue("gid")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "task: Not a recognized ID: "+r.PathValue("gid"))
+		return
+	}
+	writeList(w, r, s.store.resolveTasks(t.Dependencies))
+}
+
+func (s *Server) getDependents(w http.ResponseWriter, r *http.Request) {
+	t, ok := s.store.tasks[r.PathValue("gid")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "task: Not a recognized ID: "+r.PathValue("gid"))
+		return
+	}
+	writeList(w, r, s.store.resolveTasks(t.Dependents))
+}
+
+// resolveTasks swaps compact task references for the full records
+func (st *store) resolveTasks(refs []asana.Task) []asana.Task {
+	tasks := make([]asana.Task, 0, len(refs))
+	for _, ref := range refs {
+		if t, ok := st.tasks[ref.GID]; ok {
+			tasks = append(tasks, t.Task)
+		}
+	}
+	return tasks
+}
+
+func (s *Server) addDependencies(w http.ResponseWriter, r *http.Request) {
+	s.changeDependencies(w, r, func(t, dep *task) error {
+		return s.store.addDependency(t, dep)
+	})
+}
+
+func (s *Server) removeDependencies(w http.ResponseWriter, r *http.Request) {
+	s.changeDependencies(w, r, func(t, dep *task) error {
+		s.store.removeDependency(t, dep.GID)
+		return nil
+	})
+}
+
+// changeDependencies validates an add/removeDependencies payload and
+// applies change to each listed dependency
+func (s *Server) changeDependencies(w http.ResponseWriter, r *http.Request, change func(t, dep *task) error) {
+	t, ok := s.store.tasks[r.PathValue("gid")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "task: Not a recognized ID: "+r.PathValue("gid"))
+		return
+	}
+
+	data, err := readData(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var gids []string
+	if err := json.Unmarshal(data["dependencies"], &gids); err != nil || len(gids) == 0 {
+		writeError(w, http.StatusBadRequest, "dependencies: Missing input")
+		return
+	}
+
+	for _, gid := range gids {
+		dep, ok := s.store.tasks[gid]
+		if !ok {
+			writeError(w, http.StatusBadRequest, "dependencies: Not a recognized ID: "+gid)
+			return
+		}
+		if err := change(t, dep); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	writeData(w, http.StatusOK, struct{}{})
+}
+
+// applyTaskFields copies the writable fields present in a create or update
+// payload onto a task
+func (s *Server) applyTaskFields(t *task, data map[string]json.RawMessage) error {
+	strField := func(name string, dst *string) error {
+		raw, ok := data[name]
+		if !ok {
+			return nil
+		}
+		if err := json.Unmarshal(raw, dst); err != nil {
+			return fmt.Errorf("%s: Not a string", name)
+		}
+		return nil
+	}
+
+	for name, dst := range map[string]*string{
+		"name":            &t.Name,
+		"description":     &t.Description,
+		"notes":           &t.Description,
+		"status":          &t.Status,
+		"assignee_status": &t.AssigneeStatus,
+	} {
+		if err := strField(name, dst); err != nil {
+			return err
+		}
+	}
+
+	if raw, ok := data["completed"]; ok {
+		if err := json.Unmarshal(raw, &t.Completed); err != nil {
+			return fmt.Errorf("completed: Not a boolean")
+		}
+	}
+
+	if raw, ok := data["assignee"]; ok {
+		var ref *string
+		if err := json.Unmarshal(raw, &ref); err != nil {
+			return fmt.Errorf("assignee: Not a string")
+		}
+		if ref == nil || *ref == "" {
+			t.Assignee = nil
+		} else {
+			u, ok := s.store.user(*ref)
+			if !ok {
+				return fmt.Errorf("assignee: Not a recognized ID: %s", *ref)
+			}
+			t.Assignee = compactUser(u)
+		}
+	}
+
+	if raw, ok := data["custom_fields"]; ok {
+		var values map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &values); err != nil {
+			return fmt.Errorf("custom_fields: Not an object")
+		}
+		// Copy the values so a rejected update leaves the original intact
+		t.CustomFields = append([]asana.CustomFieldValue(nil), t.CustomFields...)
+		for gid, value := range values {
+			if err := s.store.setCustomField(t, gid, value); err != nil {
+				return err
+			}
+		}
+	}
+
+	for name, dst := range map[string]**asana.CustomTime{
+		"due_on": &t.DueDate,
+		"due_at": &t.DueAt,
+	} {
+		raw, ok := data[name]
+		if !ok {
+			continue
+		}
+		if string(raw) == "null" {
+			*dst = nil
+			continue
+		}
+		var ct asana.CustomTime
+		if err := json.Unmarshal(raw, &ct); err != nil {
+			return fmt.Errorf("%s: Not a valid date", name)
+		}
+		*dst = &ct
+	}
+
+	return nil
+}
+
+func (s *Server) getStories(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.store.tasks[r.PathValue("gid")]; !ok {
+		writeError(w, http.StatusNotFound, "task: Not a recognized ID: "+r.PathValue("gid"))
+		return
+	}
+
+	stories := append([]asana.Story{}, s.store.stories[r.PathValue("gid")]...)
+	writeList(w, r, stories)
+}
+
+func (s *Server) createStory(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.store.tasks[r.PathValue("gid")]; !ok {
+		writeError(w, http.StatusNotFound, "task: Not a recognized ID: "+r.PathValue("gid"))
+		return
+	}
+
+	data, err := readData(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var text string
+	if raw, ok := data["text"]; ok {
+		json.Unmarshal(raw, &text)
+	}
+	if strings.TrimSpace(text) == "" {
+		writeError(w, http.StatusBadRequest, "text: Missing input")
+		return
+	}
+
+	story := s.store.addStory(r.PathValue("gid"), asana.Story{Text: text})
+	writeRecord(w, r, http.StatusCreated, story)
+}
+
+func (s *Server) getAttachments(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.store.tasks[r.PathValue("gid")]; !ok {
+		writeError(w, http.StatusNotFound, "task: Not a recognized ID: "+r.PathValue("gid"))
+		return
+	}
+
+	attachments := make([]asana.Attachment, 0)
+	for _, gid := range s.store.taskAttachments[r.PathValue("gid")] {
+		attachments = append(attachments, s.store.attachments[gid].Attachment)
+	}
+	writeList(w, r, attachments)
+}
+
+func (s *Server) getAttachment(w http.ResponseWriter, r *http.Request) {
+	a, ok := s.store.attachments[r.PathValue("gid")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "attachment: Not a recognized ID: "+r.PathValue("gid"))
+		return
+	}
+	writeRecord(w, r, http.StatusOK, a.Attachment)
+}
+
+func (s *Server) uploadAttachment(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.store.tasks[r.PathValue("gid")]; !ok {
+		writeError(w, http.StatusNotFound, "task: Not a recognized ID: "+r.PathValue("gid"))
+		return
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "file: Missing input")
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if len(data) > asana.MaxAttachmentSize {
+		writeError(w, http.StatusRequestEntityTooLarge, "file: File is too large")
+		return
+	}
+
+	a := s.addAttachment(r.PathValue("gid"), header.Filename, header.Header.Get("Content-Type"), data)
+	writeRecord(w, r, http.StatusOK, a)
+}
+
+func (s *Server) downloadFile(w http.ResponseWriter, r *http.Request) {
+	a, ok := s.store.attachments[r.PathValue("gid")]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	if a.contentType != "" {
+		w.Header().Set("Content-Type", a.contentType)
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(len(a.data)))
+	w.Write(a.data)
+}
@@ -0,0 +1,294 @@
+package asanatest
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TheCoolRobot/asana-cli/internal/asana"
+)
+
+// searchTasks implements the workspace task search, supporting the
+// parameters the client sends: text, assignee/projects/sections/tags
+// filters, date ranges, completed, is_subtask, has_attachment, custom field
+// filters and sort_by/sort_ascending
+func (s *Server) searchTasks(w http.ResponseWriter, r *http.Request) {
+	wsGID := r.PathValue("gid")
+	if _, ok := s.store.workspace(wsGID); !ok {
+		writeError(w, http.StatusNotFound, "workspace: Not a recognized ID: "+wsGID)
+		return
+	}
+
+	q := r.URL.Query()
+	matches, err := s.searchFilters(q)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var found []*task
+	for _, gid := range s.store.taskOrder {
+		t := s.store.tasks[gid]
+		if t.workspace != wsGID {
+			continue
+		}
+		ok := true
+		for _, match := range matches {
+			if ok = match(t); !ok {
+				break
+			}
+		}
+		if ok {
+			found = append(found, t)
+		}
+	}
+
+	if err := sortSearchResults(found, q.Get("sort_by"), q.Get("sort_ascending") == "true"); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tasks := make([]asana.Task, len(found))
+	for i, t := range found {
+		tasks[i] = t.Task
+	}
+	writeList(w, r, tasks)
+}
+
+// searchFilters builds a predicate for each search parameter in q
+func (s *Server) searchFilters(q url.Values) ([]func(t *task) bool, error) {
+	var matches []func(t *task) bool
+	add := func(m func(t *task) bool) { matches = append(matches, m) }
+
+	if text := strings.ToLower(q.Get("text")); text != "" {
+		add(func(t *task) bool {
+			return strings.Contains(strings.ToLower(t.Name+" "+t.Description), text)
+		})
+	}
+
+	if refs := splitList(q.Get("assignee.any")); len(refs) > 0 {
+		gids := make(map[string]bool)
+		for _, ref := range refs {
+			u, ok := s.store.user(ref)
+			if !ok {
+				return nil, fmt.Errorf("assignee.any: Not a recognized ID: %s", ref)
+			}
+			gids[u.GID] = true
+		}
+		add(func(t *task) bool { return t.Assignee != nil && gids[t.Assignee.GID] })
+	}
+
+	if gids := splitList(q.Get("projects.any")); len(gids) > 0 {
+		add(func(t *task) bool {
+			for _, gid := range gids {
+				if t.inProject(gid) {
+					return true
+				}
+			}
+			return false
+		})
+	}
+
+	if gids := splitList(q.Get("sections.any")); len(gids) > 0 {
+		add(func(t *task) bool {
+			for _, gid := range gids {
+				if contains(t.sections, gid) {
+					return true
+				}
+			}
+			return false
+		})
+	}
+
+	if gids := splitList(q.Get("tags.any")); len(gids) > 0 {
+		add(func(t *task) bool {
+			for _, gid := range gids {
+				if hasTag(t, gid) {
+					return true
+				}
+			}
+			return false
+		})
+	}
+	if gids := splitList(q.Get("tags.all")); len(gids) > 0 {
+		add(func(t *task) bool {
+			for _, gid := range gids {
+				if !hasTag(t, gid) {
+					return false
+				}
+			}
+			return true
+		})
+	}
+
+	dueOn := func(t *task) string {
+		if t.DueDate == nil || t.DueDate.IsZero() {
+			return ""
+		}
+		return t.DueDate.Format("2006-01-02")
+	}
+	for param, date := range map[string]func(t *task) string{
+		"due_on":      dueOn,
+		"created_on":  func(t *task) string { return t.CreatedAt.UTC().Format("2006-01-02") },
+		"modified_on": func(t *task) string { return t.ModifiedAt.UTC().Format("2006-01-02") },
+	} {
+		for _, suffix := range []string{"", ".before", ".after"} {
+			want := q.Get(param + suffix)
+			if want == "" {
+				continue
+			}
+			if _, err := time.Parse("2006-01-02", want); err != nil {
+				return nil, fmt.Errorf("%s%s: Not a valid date: %s", param, suffix, want)
+			}
+			date, suffix := date, suffix
+			add(func(t *task) bool {
+				got := date(t)
+				switch {
+				case got == "":
+					return false
+				case suffix == ".before":
+					return got < want
+				case suffix == ".after":
+					return got > want
+				}
+				return got == want
+			})
+		}
+	}
+
+	for param, value := range map[string]func(t *task) bool{
+		"completed":      func(t *task) bool { return t.Completed },
+		"is_subtask":     func(t *task) bool { return t.Parent != nil },
+		"has_attachment": func(t *task) bool { return len(s.store.taskAttachments[t.GID]) > 0 },
+	} {
+		if raw := q.Get(param); raw != "" {
+			want, err := strconv.ParseBool(raw)
+			if err != nil {
+				return nil, fmt.Errorf("%s: Not a boolean: %s", param, raw)
+			}
+			value := value
+			add(func(t *task) bool { return value(t) == want })
+		}
+	}
+
+	for key := range q {
+		if !strings.HasPrefix(key, "custom_fields.") {
+			continue
+		}
+		m, err := s.customFieldFilter(key, q.Get(key))
+		if err != nil {
+			return nil, err
+		}
+		add(m)
+	}
+
+	return matches, nil
+}
+
+// customFieldFilter handles a custom_fields.{gid}.{op} search parameter
+func (s *Server) customFieldFilter(key, value string) (func(t *task) bool, error) {
+	parts := strings.Split(key, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%s: Not a recognized parameter", key)
+	}
+	gid, op := parts[1], parts[2]
+	if _, ok := s.store.customFields[gid]; !ok {
+		return nil, fmt.Errorf("%s: Not a recognized custom field ID: %s", key, gid)
+	}
+
+	var number float64
+	if op == asana.FilterLessThan || op == asana.FilterGreaterThan {
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s: Not a number: %s", key, value)
+		}
+		number = n
+	}
+
+	return func(t *task) bool {
+		f := t.CustomField(gid)
+		switch op {
+		case asana.FilterIsSet:
+			return (f != nil && f.IsSet()) == (value == "true")
+		case asana.FilterLessThan:
+			return f != nil && f.NumberValue != nil && *f.NumberValue < number
+		case asana.FilterGreaterThan:
+			return f != nil && f.NumberValue != nil && *f.NumberValue > number
+		case asana.FilterContains:
+			return f != nil && f.TextValue != nil && strings.Contains(strings.ToLower(*f.TextValue), strings.ToLower(value))
+		case asana.FilterValue:
+			if f == nil {
+				return false
+			}
+			switch {
+			case f.EnumValue != nil:
+				return f.EnumValue.GID == value
+			case f.NumberValue != nil:
+				n, err := strconv.ParseFloat(value, 64)
+				return err == nil && *f.NumberValue == n
+			case f.TextValue != nil:
+				return *f.TextValue == value
+			}
+			for _, o := range f.MultiEnumValues {
+				if o.GID == value {
+					return true
+				}
+			}
+		}
+		return false
+	}, nil
+}
+
+// sortSearchResults orders tasks the way the search API does: by
+// modified_at, newest first, unless sort_by says otherwise
+func sortSearchResults(tasks []*task, sortBy string, ascending bool) error {
+	var less func(a, b *task) bool
+	switch sortBy {
+	case "", "modified_at", "completed_at", "likes":
+		less = func(a, b *task) bool { return a.ModifiedAt.Before(b.ModifiedAt) }
+	case "created_at":
+		less = func(a, b *task) bool { return a.CreatedAt.Before(b.CreatedAt) }
+	case "due_date":
+		// Tasks without a due date sort last either way
+		due := func(t *task) time.Time {
+			if t.DueDate == nil || t.DueDate.IsZero() {
+				if ascending {
+					return time.Date(9999, 1, 1, 0, 0, 0, 0, time.UTC)
+				}
+				return time.Time{}
+			}
+			return t.DueDate.Time
+		}
+		less = func(a, b *task) bool { return due(a).Before(due(b)) }
+	default:
+		return fmt.Errorf("sort_by: Not a valid sort order: %s", sortBy)
+	}
+
+	sort.SliceStable(tasks, func(i, j int) bool {
+		if ascending {
+			return less(tasks[i], tasks[j])
+		}
+		return less(tasks[j], tasks[i])
+	})
+	return nil
+}
+
+func hasTag(t *task, gid string) bool {
+	for _, tag := range t.Tags {
+		if tag.GID == gid {
+			return true
+		}
+	}
+	return false
+}
+
+func splitList(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
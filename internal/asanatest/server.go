@@ -0,0 +1,280 @@
+// Package asanatest provides an in-process fake of the Asana REST API for
+// tests. It implements the subset of endpoints used by asana.Client on top
+// of an in-memory store, paginates list responses the way Asana does and
+// can be told to fail specific requests.
+//
+//	srv := asanatest.NewServer(t)
+//	ws := srv.AddWorkspace("Acme")
+//	proj := srv.AddProject(ws.GID, "Roadmap")
+//	srv.AddTask(proj.GID, asana.Task{Name: "Ship it"})
+//
+//	client := srv.Client()
+//	tasks, err := client.GetTasks(ctx, proj.GID, nil)
+package asanatest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/TheCoolRobot/asana-cli/internal/asana"
+)
+
+// Request is a request the server received, recorded for assertions
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Body   []byte
+}
+
+// Failure describes an error the server should return instead of handling
+// a matching request
+type Failure struct {
+	// Method and Path select the requests to fail. An empty Method matches
+	// any method; Path matches exactly, or as a prefix when it ends in "*".
+	Method string
+	Path   string
+	// Status is the HTTP status to return
+	Status int
+	// Message is returned in Asana's errors[].message
+	Message string
+	// RetryAfter, if set, is sent as the Retry-After header
+	RetryAfter string
+	// Times is how many requests to fail; zero means every matching request
+	Times int
+}
+
+func (f *Failure) matches(r *http.Request) bool {
+	if f.Method != "" && f.Method != r.Method {
+		return false
+	}
+	if strings.HasSuffix(f.Path, "*") {
+		return strings.HasPrefix(r.URL.Path, strings.TrimSuffix(f.Path, "*"))
+	}
+	return f.Path == r.URL.Path
+}
+
+// Server is a fake Asana API backed by an in-memory store
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	store    *store
+	failures []*Failure
+	requests []Request
+	mux      *http.ServeMux
+}
+
+// NewServer starts a fake server that is shut down when the test ends
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+
+	s := &Server{
+		store: newStore(),
+		mux:   http.NewServeMux(),
+	}
+	s.routes()
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+// Client returns an asana.Client pointed at the fake server
+func (s *Server) Client(opts ...asana.Option) *asana.Client {
+	opts = append([]asana.Option{asana.WithBaseURL(s.URL)}, opts...)
+	return asana.NewClient("test-token", opts...)
+}
+
+// Fail registers a failure to inject into matching requests
+func (s *Server) Fail(f Failure) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	failure := f
+	s.failures = append(s.failures, &failure)
+}
+
+// Requests returns every request received so far
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// ResetRequests clears the recorded request log
+func (s *Server) ResetRequests() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = nil
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.Query(),
+		Body:   body,
+	})
+	s.mu.Unlock()
+
+	s.dispatch(w, r)
+}
+
+// dispatch checks a request's token and injected failures, then routes
+// it. Batch actions come through here too, without being recorded.
+func (s *Server) dispatch(w http.ResponseWriter, r *http.Request) {
+	// File downloads use pre-signed URLs rather than the API token
+	if strings.HasPrefix(r.URL.Path, "/files/") {
+		s.mux.ServeHTTP(w, r)
+		return
+	}
+
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+		writeError(w, http.StatusUnauthorized, "Not Authorized")
+		return
+	}
+
+	s.mu.Lock()
+	failure := s.takeFailure(r)
+	s.mu.Unlock()
+
+	if failure != nil {
+		if failure.RetryAfter != "" {
+			w.Header().Set("Retry-After", failure.RetryAfter)
+		}
+		msg := failure.Message
+		if msg == "" {
+			msg = http.StatusText(failure.Status)
+		}
+		writeError(w, failure.Status, msg)
+		return
+	}
+
+	s.mux.ServeHTTP(w, r)
+}
+
+// takeFailure returns the first registered failure matching r, consuming
+// one of its remaining uses. Callers must hold s.mu.
+func (s *Server) takeFailure(r *http.Request) *Failure {
+	for i, f := range s.failures {
+		if !f.matches(r) {
+			continue
+		}
+		if f.Times > 0 {
+			f.Times--
+			if f.Times == 0 {
+				s.failures = append(s.failures[:i], s.failures[i+1:]...)
+			}
+		}
+		return f
+	}
+	return nil
+}
+
+// handle registers a route whose handler runs with the store locked
+func (s *Server) handle(pattern string, h func(w http.ResponseWriter, r *http.Request)) {
+	s.mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		h(w, r)
+	})
+}
+
+// writeData writes a single-record response
+func writeData(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+}
+
+// writeList writes a list response, paginated by the limit and offset
+// query parameters the same way Asana does
+func writeList[T any](w http.ResponseWriter, r *http.Request, items []T) {
+	q := r.URL.Query()
+
+	start := 0
+	if offset := q.Get("offset"); offset != "" {
+		n, err := strconv.Atoi(offset)
+		if err != nil || n < 0 || n > len(items) {
+			writeError(w, http.StatusBadRequest, "offset: Your pagination token is invalid.")
+			return
+		}
+		start = n
+	}
+
+	end := len(items)
+	if limit := q.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 || n > asana.MaxPageSize {
+			writeError(w, http.StatusBadRequest, "limit: Must be between 1 and 100")
+			return
+		}
+		if start+n < end {
+			end = start + n
+		}
+	}
+
+	resp := map[string]interface{}{
+		"data":      selectFields(r, items[start:end], true),
+		"next_page": nil,
+	}
+	if end < len(items) {
+		offset := strconv.Itoa(end)
+		resp["next_page"] = map[string]string{
+			"offset": offset,
+			"path":   r.URL.Path + "?limit=" + q.Get("limit") + "&offset=" + offset,
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"errors": []map[string]string{{
+			"message": msg,
+			"help":    "For more information on API status codes and how to handle them, read the docs on errors: https://developers.asana.com/docs/errors",
+		}},
+	})
+}
+
+// readData decodes the {"data": {...}} envelope of a request body into a
+// map of raw fields, so handlers can tell which fields were sent
+func readData(r *http.Request) (map[string]json.RawMessage, error) {
+	var envelope struct {
+		Data map[string]json.RawMessage `json:"data"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&envelope); err != nil {
+		return nil, fmt.Errorf("request body is not valid JSON: %w", err)
+	}
+	if envelope.Data == nil {
+		return nil, fmt.Errorf("data: Missing input")
+	}
+	return envelope.Data, nil
+}
+
+// readInto decodes the {"data": {...}} envelope of a request body into v
+func readInto(r *http.Request, v interface{}) error {
+	data, err := readData(r)
+	if err != nil {
+		return err
+	}
+	raw, _ := json.Marshal(data)
+	return json.Unmarshal(raw, v)
+}
@@ -0,0 +1,609 @@
+package asanatest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TheCoolRobot/asana-cli/internal/asana"
+)
+
+func TestTaskCRUD(t *testing.T) {
+	srv := NewServer(t)
+	ws := srv.AddWorkspace("Acme")
+	proj := srv.AddProject(ws.GID, "Roadmap")
+	client := srv.Client()
+	ctx := context.Background()
+
+	created, err := client.CreateTask(ctx, &asana.TaskCreateRequest{
+		Name:     "Write tests",
+		Projects: []string{proj.GID},
+		Assignee: "me",
+		DueOn:    "2026-03-01",
+	})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	if created.Assignee == nil || created.Assignee.GID != srv.Me().GID {
+		t.Errorf("assignee not resolved: %+v", created.Assignee)
+	}
+
+	done := true
+	if _, err := client.UpdateTask(ctx, created.GID, &asana.TaskUpdateRequest{Completed: &done}); err != nil {
+		t.Fatalf("UpdateTask failed: %v", err)
+	}
+	got, err := client.GetTask(ctx, created.GID)
+	if err != nil {
+		t.Fatalf("GetTask failed: %v", err)
+	}
+	if !got.Completed || got.DueDate == nil || got.DueDate.Format("2006-01-02") != "2026-03-01" {
+		t.Errorf("unexpected task state: %+v", got)
+	}
+
+	if err := client.DeleteTask(ctx, created.GID); err != nil {
+		t.Fatalf("DeleteTask failed: %v", err)
+	}
+	if _, err := client.GetTask(ctx, created.GID); !asana.IsNotFound(err) {
+		t.Errorf("expected not found after delete, got %v", err)
+	}
+}
+
+func TestListPaginationAndFilters(t *testing.T) {
+	srv := NewServer(t)
+	ws := srv.AddWorkspace("Acme")
+	proj := srv.AddProject(ws.GID, "Big")
+	for i := 0; i < 230; i++ {
+		srv.AddTask(proj.GID, asana.Task{Name: fmt.Sprintf("Task %d", i), Completed: i%2 == 0})
+	}
+
+	client := srv.Client()
+	tasks, err := client.GetTasks(context.Background(), proj.GID, nil)
+	if err != nil {
+		t.Fatalf("GetTasks failed: %v", err)
+	}
+	if len(tasks) != 230 {
+		t.Errorf("expected 230 tasks, got %d", len(tasks))
+	}
+
+	pages := 0
+	for _, req := range srv.Requests() {
+		if req.Path == "/projects/"+proj.GID+"/tasks" {
+			pages++
+		}
+	}
+	if pages != 3 {
+		t.Errorf("expected 3 page requests, got %d", pages)
+	}
+
+	incomplete, err := client.GetTasks(context.Background(), proj.GID, map[string]string{"completed_since": "now"})
+	if err != nil {
+		t.Fatalf("GetTasks failed: %v", err)
+	}
+	if len(incomplete) != 115 {
+		t.Errorf("expected 115 incomplete tasks, got %d", len(incomplete))
+	}
+}
+
+func TestSearch(t *testing.T) {
+	srv := NewServer(t)
+	ws := srv.AddWorkspace("Acme")
+	proj := srv.AddProject(ws.GID, "Roadmap")
+	srv.AddTask(proj.GID, asana.Task{Name: "Fix login bug"})
+	srv.AddTask(proj.GID, asana.Task{Name: "Write docs"})
+
+	tasks, err := srv.Client().Search(context.Background(), ws.GID, "BUG")
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].Name != "Fix login bug" {
+		t.Errorf("unexpected results: %+v", tasks)
+	}
+}
+
+func TestFailureInjection(t *testing.T) {
+	srv := NewServer(t)
+	task := srv.AddTask("", asana.Task{Name: "Flaky"})
+
+	srv.Fail(Failure{Method: "GET", Path: "/tasks/*", Status: http.StatusTooManyRequests, RetryAfter: "0", Times: 2})
+
+	client := srv.Client()
+	client.SetRetryPolicy(asana.RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond})
+
+	got, err := client.GetTask(context.Background(), task.GID)
+	if err != nil {
+		t.Fatalf("expected retries to succeed, got %v", err)
+	}
+	if got.Name != "Flaky" {
+		t.Errorf("unexpected task: %+v", got)
+	}
+	if n := len(srv.Requests()); n != 3 {
+		t.Errorf("expected 3 requests, got %d", n)
+	}
+
+	srv.Fail(Failure{Path: "/users/me", Status: http.StatusForbidden, Message: "nope"})
+	if _, err := client.GetMe(context.Background()); !asana.IsAuth(err) {
+		t.Errorf("expected auth error, got %v", err)
+	}
+}
+
+func TestRejectsMissingToken(t *testing.T) {
+	srv := NewServer(t)
+
+	resp, err := http.Get(srv.URL + "/users/me")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d", resp.StatusCode)
+	}
+}
+
+func TestStories(t *testing.T) {
+	srv := NewServer(t)
+	task := srv.AddTask("", asana.Task{Name: "Discuss"})
+	srv.AddStory(task.GID, asana.Story{Type: "system", ResourceSubtype: "assigned", Text: "assigned to you"})
+
+	client := srv.Client()
+	ctx := context.Background()
+
+	story, err := client.AddComment(ctx, task.GID, "Looks good")
+	if err != nil {
+		t.Fatalf("AddComment failed: %v", err)
+	}
+	if !story.IsComment() || story.CreatedBy == nil || story.CreatedBy.GID != srv.Me().GID {
+		t.Errorf("unexpected comment: %+v", story)
+	}
+
+	stories, err := client.GetStories(ctx, task.GID)
+	if err != nil {
+		t.Fatalf("GetStories failed: %v", err)
+	}
+	if len(stories) != 2 || stories[0].IsComment() || stories[1].Text != "Looks good" {
+		t.Errorf("unexpected stories: %+v", stories)
+	}
+
+	if _, err := client.AddComment(ctx, "404", "hello"); !asana.IsNotFound(err) {
+		t.Errorf("expected not found, got %v", err)
+	}
+}
+
+func TestSubtasks(t *testing.T) {
+	srv := NewServer(t)
+	parent := srv.AddTask("", asana.Task{Name: "Epic"})
+	first := srv.AddSubtask(parent.GID, asana.Task{Name: "First"})
+	client := srv.Client()
+	ctx := context.Background()
+
+	second, err := client.CreateTask(ctx, &asana.TaskCreateRequest{Name: "Second", Parent: parent.GID})
+	if err != nil {
+		t.Fatalf("CreateTask with parent failed: %v", err)
+	}
+	if second.Parent == nil || second.Parent.GID != parent.GID {
+		t.Errorf("parent not set: %+v", second.Parent)
+	}
+
+	if _, err := client.SetParent(ctx, second.GID, &asana.SetParentRequest{Parent: parent.GID, InsertBefore: first.GID}); err != nil {
+		t.Fatalf("SetParent failed: %v", err)
+	}
+	subtasks, err := client.GetSubtasks(ctx, parent.GID)
+	if err != nil {
+		t.Fatalf("GetSubtasks failed: %v", err)
+	}
+	if len(subtasks) != 2 || subtasks[0].Name != "Second" || subtasks[1].Name != "First" {
+		t.Errorf("unexpected subtask order: %+v", subtasks)
+	}
+
+	if _, err := client.SetParent(ctx, parent.GID, &asana.SetParentRequest{Parent: first.GID}); !asana.IsInvalid(err) {
+		t.Errorf("expected a cycle to be rejected, got %v", err)
+	}
+
+	moved, err := client.SetParent(ctx, first.GID, &asana.SetParentRequest{})
+	if err != nil {
+		t.Fatalf("SetParent to top level failed: %v", err)
+	}
+	if moved.Parent != nil {
+		t.Errorf("expected a top-level task, got parent %+v", moved.Parent)
+	}
+	if got, _ := client.GetTask(ctx, parent.GID); got.NumSubtasks != 1 {
+		t.Errorf("expected 1 subtask left, got %d", got.NumSubtasks)
+	}
+}
+
+func TestDependencies(t *testing.T) {
+	srv := NewServer(t)
+	design := srv.AddTask("", asana.Task{Name: "Design"})
+	build := srv.AddTask("", asana.Task{Name: "Build"})
+	client := srv.Client()
+	ctx := context.Background()
+
+	if err := client.AddDependencies(ctx, build.GID, []string{design.GID}); err != nil {
+		t.Fatalf("AddDependencies failed: %v", err)
+	}
+	deps, err := client.GetDependencies(ctx, build.GID)
+	if err != nil {
+		t.Fatalf("GetDependencies failed: %v", err)
+	}
+	if len(deps) != 1 || deps[0].GID != design.GID {
+		t.Errorf("unexpected dependencies: %+v", deps)
+	}
+	dependents, err := client.GetDependents(ctx, design.GID)
+	if err != nil {
+		t.Fatalf("GetDependents failed: %v", err)
+	}
+	if len(dependents) != 1 || dependents[0].GID != build.GID {
+		t.Errorf("unexpected dependents: %+v", dependents)
+	}
+
+	if err := client.AddDependencies(ctx, design.GID, []string{build.GID}); !asana.IsInvalid(err) {
+		t.Errorf("expected a cycle to be rejected, got %v", err)
+	}
+
+	if err := client.RemoveDependencies(ctx, build.GID, []string{design.GID}); err != nil {
+		t.Fatalf("RemoveDependencies failed: %v", err)
+	}
+	if got, _ := srv.Task(design.GID); len(got.Dependents) != 0 {
+		t.Errorf("dependent not removed: %+v", got.Dependents)
+	}
+}
+
+func TestSections(t *testing.T) {
+	srv := NewServer(t)
+	ws := srv.AddWorkspace("Acme")
+	proj := srv.AddProject(ws.GID, "Roadmap")
+	todo := srv.AddSection(proj.GID, "To do")
+	task := srv.AddTask(proj.GID, asana.Task{Name: "Write docs"})
+	client := srv.Client()
+	ctx := context.Background()
+
+	done, err := client.CreateSection(ctx, proj.GID, &asana.SectionRequest{Name: "Done"})
+	if err != nil {
+		t.Fatalf("CreateSection failed: %v", err)
+	}
+	doing, err := client.CreateSection(ctx, proj.GID, &asana.SectionRequest{Name: "Doing", InsertBefore: done.GID})
+	if err != nil {
+		t.Fatalf("CreateSection failed: %v", err)
+	}
+	if got := sectionNames(srv.Sections(proj.GID)); got != "To do,Doing,Done" {
+		t.Errorf("sections = %s", got)
+	}
+
+	if err := client.MoveSection(ctx, proj.GID, todo.GID, "", done.GID); err != nil {
+		t.Fatalf("MoveSection failed: %v", err)
+	}
+	if got := sectionNames(srv.Sections(proj.GID)); got != "Doing,Done,To do" {
+		t.Errorf("sections after move = %s", got)
+	}
+
+	if _, err := client.RenameSection(ctx, todo.GID, "Backlog"); err != nil {
+		t.Fatalf("RenameSection failed: %v", err)
+	}
+
+	if err := client.AddTaskToSection(ctx, todo.GID, task.GID, "", ""); err != nil {
+		t.Fatalf("AddTaskToSection failed: %v", err)
+	}
+	if err := client.AddTaskToSection(ctx, doing.GID, task.GID, "", ""); err != nil {
+		t.Fatalf("AddTaskToSection failed: %v", err)
+	}
+	if len(srv.SectionTasks(todo.GID)) != 0 || len(srv.SectionTasks(doing.GID)) != 1 {
+		t.Errorf("task not moved between sections")
+	}
+	got, err := client.GetTask(ctx, task.GID)
+	if err != nil {
+		t.Fatalf("GetTask failed: %v", err)
+	}
+	if len(got.Memberships) != 1 || got.Memberships[0].Section == nil || got.Memberships[0].Section.Name != "Doing" {
+		t.Errorf("unexpected memberships: %+v", got.Memberships)
+	}
+
+	if err := client.DeleteSection(ctx, doing.GID); !asana.IsInvalid(err) {
+		t.Errorf("expected deleting a non-empty section to fail, got %v", err)
+	}
+	if err := client.DeleteSection(ctx, todo.GID); err != nil {
+		t.Fatalf("DeleteSection failed: %v", err)
+	}
+	if got := sectionNames(srv.Sections(proj.GID)); got != "Doing,Done" {
+		t.Errorf("sections after delete = %s", got)
+	}
+
+	created, err := client.CreateTask(ctx, &asana.TaskCreateRequest{
+		Name:        "Release",
+		Memberships: []asana.MembershipRequest{{Project: proj.GID, Section: done.GID}},
+	})
+	if err != nil {
+		t.Fatalf("CreateTask with memberships failed: %v", err)
+	}
+	if tasks := srv.SectionTasks(done.GID); len(tasks) != 1 || tasks[0] != created.GID {
+		t.Errorf("created task not placed in section: %v", tasks)
+	}
+}
+
+func sectionNames(sections []asana.Section) string {
+	names := make([]string, len(sections))
+	for i, sec := range sections {
+		names[i] = sec.Name
+	}
+	return strings.Join(names, ",")
+}
+
+func TestProjects(t *testing.T) {
+	srv := NewServer(t)
+	ws := srv.AddWorkspace("Acme")
+	eng := srv.AddTeam(ws.GID, "Engineering")
+	client := srv.Client()
+	ctx := context.Background()
+
+	public := true
+	p, err := client.CreateProject(ctx, &asana.ProjectCreateRequest{
+		Name:        "Launch",
+		Team:        eng.GID,
+		Color:       "dark-blue",
+		DefaultView: "board",
+		DueOn:       "2026-12-01",
+		Public:      &public,
+	})
+	if err != nil {
+		t.Fatalf("CreateProject failed: %v", err)
+	}
+	if p.Team == nil || p.Team.Name != "Engineering" || p.DefaultView != "board" || !p.Public || p.DueOn == nil {
+		t.Errorf("unexpected project: %+v", p)
+	}
+	if _, err := client.CreateProject(ctx, &asana.ProjectCreateRequest{Name: "Bad", Workspace: ws.GID, Color: "plaid"}); !asana.IsInvalid(err) {
+		t.Errorf("expected an invalid color to be rejected, got %v", err)
+	}
+
+	task := srv.AddTask(p.GID, asana.Task{Name: "Write press release", Description: "Draft"})
+	if _, err := client.UpdateProject(ctx, p.GID, &asana.ProjectUpdateRequest{Name: "Launch v2"}); err != nil {
+		t.Fatalf("UpdateProject failed: %v", err)
+	}
+	if got, _ := srv.Task(task.GID); got.Projects[0].Name != "Launch v2" {
+		t.Errorf("task's project not renamed: %+v", got.Projects)
+	}
+
+	if _, err := client.ArchiveProject(ctx, p.GID, true); err != nil {
+		t.Fatalf("ArchiveProject failed: %v", err)
+	}
+	active, err := client.GetProjectsFiltered(ctx, ws.GID, map[string]string{"archived": "false"})
+	if err != nil {
+		t.Fatalf("GetProjectsFiltered failed: %v", err)
+	}
+	if len(active) != 0 {
+		t.Errorf("archived project listed as active: %+v", active)
+	}
+
+	job, err := client.DuplicateProject(ctx, p.GID, &asana.ProjectDuplicateRequest{Name: "Launch copy", Include: "task_notes"})
+	if err != nil {
+		t.Fatalf("DuplicateProject failed: %v", err)
+	}
+	if job.Status != asana.JobInProgress {
+		t.Errorf("job status = %s", job.Status)
+	}
+	job, err = client.WaitForJob(ctx, job, time.Millisecond)
+	if err != nil || job.Status != asana.JobSucceeded {
+		t.Fatalf("WaitForJob = %+v, %v", job, err)
+	}
+	copied := srv.Tasks(job.NewProject.GID)
+	if len(copied) != 1 || copied[0].Name != "Write press release" || copied[0].Description != "Draft" {
+		t.Errorf("unexpected copied tasks: %+v", copied)
+	}
+
+	if err := client.DeleteProject(ctx, p.GID); err != nil {
+		t.Fatalf("DeleteProject failed: %v", err)
+	}
+	if _, ok := srv.Project(p.GID); ok {
+		t.Error("project not deleted")
+	}
+	if got, _ := srv.Task(task.GID); len(got.Projects) != 0 {
+		t.Errorf("task still in deleted project: %+v", got.Projects)
+	}
+}
+
+func TestTags(t *testing.T) {
+	srv := NewServer(t)
+	ws := srv.AddWorkspace("Acme")
+	task := srv.AddTask("", asana.Task{Name: "Fix login"})
+	client := srv.Client()
+	ctx := context.Background()
+
+	bug, err := client.CreateTag(ctx, &asana.TagRequest{Name: "bug", Color: "dark-red", Workspace: ws.GID})
+	if err != nil {
+		t.Fatalf("CreateTag failed: %v", err)
+	}
+	if err := client.AddTag(ctx, task.GID, bug.GID); err != nil {
+		t.Fatalf("AddTag failed: %v", err)
+	}
+	if _, err := client.UpdateTag(ctx, bug.GID, &asana.TagRequest{Name: "defect"}); err != nil {
+		t.Fatalf("UpdateTag failed: %v", err)
+	}
+	got, _ := srv.Task(task.GID)
+	if len(got.Tags) != 1 || got.Tags[0].Name != "defect" {
+		t.Errorf("unexpected task tags: %+v", got.Tags)
+	}
+
+	tags, err := client.GetTags(ctx, ws.GID)
+	if err != nil {
+		t.Fatalf("GetTags failed: %v", err)
+	}
+	if len(tags) != 1 || tags[0].Color != "dark-red" {
+		t.Errorf("unexpected tags: %+v", tags)
+	}
+
+	if err := client.RemoveTag(ctx, task.GID, bug.GID); err != nil {
+		t.Fatalf("RemoveTag failed: %v", err)
+	}
+	if got, _ := srv.Task(task.GID); len(got.Tags) != 0 {
+		t.Errorf("tag not removed: %+v", got.Tags)
+	}
+
+	srv.TagTask(task.GID, bug.GID)
+	if err := client.DeleteTag(ctx, bug.GID); err != nil {
+		t.Fatalf("DeleteTag failed: %v", err)
+	}
+	if got, _ := srv.Task(task.GID); len(got.Tags) != 0 {
+		t.Errorf("deleted tag still on task: %+v", got.Tags)
+	}
+	if err := client.AddTag(ctx, task.GID, bug.GID); !asana.IsInvalid(err) {
+		t.Errorf("expected adding a deleted tag to fail, got %v", err)
+	}
+}
+
+func TestCustomFields(t *testing.T) {
+	srv := NewServer(t)
+	ws := srv.AddWorkspace("Acme")
+	proj := srv.AddProject(ws.GID, "Roadmap")
+	points := srv.AddCustomField(proj.GID, asana.CustomField{Name: "Story Points", Type: asana.CustomFieldNumber})
+	priority := srv.AddCustomField(proj.GID, asana.CustomField{Name: "Priority", Type: asana.CustomFieldEnum, EnumOptions: []asana.EnumOption{{Name: "High"}, {Name: "Low"}}})
+	client := srv.Client()
+	ctx := context.Background()
+
+	defs, err := client.GetCustomFieldSettings(ctx, proj.GID)
+	if err != nil {
+		t.Fatalf("GetCustomFieldSettings failed: %v", err)
+	}
+	if len(defs) != 2 || defs[1].Name != "Priority" || len(defs[1].EnumOptions) != 2 {
+		t.Fatalf("unexpected definitions: %+v", defs)
+	}
+
+	values, err := asana.ResolveCustomFields(defs, []string{"Story Points=3", "Priority=high"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	created, err := client.CreateTask(ctx, &asana.TaskCreateRequest{Name: "Estimate", Projects: []string{proj.GID}, CustomFields: values})
+	if err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	if created.Priority() != "High" || created.CustomField("Story Points").String() != "3" {
+		t.Errorf("custom fields not set: %+v", created.CustomFields)
+	}
+
+	if _, err := client.UpdateTask(ctx, created.GID, &asana.TaskUpdateRequest{CustomFields: map[string]interface{}{priority.GID: "bogus"}}); !asana.IsInvalid(err) {
+		t.Errorf("expected invalid enum option to be rejected, got %v", err)
+	}
+	if _, err := client.UpdateTask(ctx, created.GID, &asana.TaskUpdateRequest{CustomFields: map[string]interface{}{points.GID: nil}}); err != nil {
+		t.Fatalf("UpdateTask failed: %v", err)
+	}
+
+	got, err := client.GetTaskCustomFields(ctx, created.GID)
+	if err != nil {
+		t.Fatalf("GetTaskCustomFields failed: %v", err)
+	}
+	if len(got) != 2 {
+		t.Errorf("unexpected task custom fields: %+v", got)
+	}
+	stored, _ := srv.Task(created.GID)
+	if stored.CustomField("Story Points").IsSet() || stored.Priority() != "High" {
+		t.Errorf("unexpected stored values: %+v", stored.CustomFields)
+	}
+}
+
+func TestAttachments(t *testing.T) {
+	srv := NewServer(t)
+	task := srv.AddTask("", asana.Task{Name: "Bug report"})
+	client := srv.Client()
+	ctx := context.Background()
+
+	att, err := client.UploadAttachment(ctx, task.GID, "crash.txt", strings.NewReader("panic: oops\n"))
+	if err != nil {
+		t.Fatalf("UploadAttachment failed: %v", err)
+	}
+	if att.Name != "crash.txt" || att.Size != 12 {
+		t.Errorf("unexpected attachment: %+v", att)
+	}
+	if _, contentType, _ := srv.AttachmentData(att.GID); !strings.HasPrefix(contentType, "text/plain") {
+		t.Errorf("unexpected uploaded content type %q", contentType)
+	}
+
+	attachments, err := client.GetAttachments(ctx, task.GID)
+	if err != nil || len(attachments) != 1 {
+		t.Fatalf("GetAttachments: %v %+v", err, attachments)
+	}
+
+	var buf bytes.Buffer
+	if _, err := client.DownloadAttachment(ctx, &attachments[0], &buf); err != nil {
+		t.Fatalf("DownloadAttachment failed: %v", err)
+	}
+	if buf.String() != "panic: oops\n" {
+		t.Errorf("unexpected contents %q", buf.String())
+	}
+	for _, req := range srv.Requests() {
+		if strings.HasPrefix(req.Path, "/files/") && req.Method != "GET" {
+			t.Errorf("unexpected file request %s %s", req.Method, req.Path)
+		}
+	}
+
+	big := bytes.NewReader(make([]byte, asana.MaxAttachmentSize+1))
+	if _, err := client.UploadAttachment(ctx, task.GID, "big.bin", big); !errors.Is(err, asana.ErrAttachmentTooLarge) {
+		t.Errorf("expected ErrAttachmentTooLarge, got %v", err)
+	}
+}
+
+func TestBatch(t *testing.T) {
+	srv := NewServer(t)
+	ws := srv.AddWorkspace("Acme")
+	proj := srv.AddProject(ws.GID, "Roadmap")
+	bug := srv.AddTag(ws.GID, "bug")
+	client := srv.Client()
+	ctx := context.Background()
+
+	var tasks []asana.Task
+	var actions []asana.BatchAction
+	for i := 0; i < 12; i++ {
+		task := srv.AddTask(proj.GID, asana.Task{Name: fmt.Sprintf("Task %d", i)})
+		tasks = append(tasks, task)
+		actions = append(actions, asana.CompleteTaskAction(task.GID))
+	}
+	actions = append(actions,
+		asana.AddTagAction(tasks[0].GID, bug.GID),
+		asana.CompleteTaskAction("404404"),
+		asana.GetTaskAction(tasks[1].GID, "name", "completed"),
+	)
+
+	srv.ResetRequests()
+	results, err := client.Batch(ctx, actions)
+	if err != nil {
+		t.Fatalf("Batch failed: %v", err)
+	}
+	if n := len(srv.Requests()); n != 2 {
+		t.Errorf("expected 15 actions in 2 requests, got %d", n)
+	}
+	if len(results) != len(actions) {
+		t.Fatalf("expected %d results, got %d", len(actions), len(results))
+	}
+
+	for i, task := range tasks {
+		got, err := results[i].Task()
+		if err != nil || got.GID != task.GID || !got.Completed {
+			t.Errorf("result %d: %+v, %v", i, got, err)
+		}
+	}
+	if r := results[12]; r.Err != nil || r.StatusCode != http.StatusOK {
+		t.Errorf("addTag result: %+v", r)
+	}
+	if stored, _ := srv.Task(tasks[0].GID); len(stored.Tags) != 1 {
+		t.Errorf("task not tagged: %+v", stored.Tags)
+	}
+	if r := results[13]; !asana.IsNotFound(r.Err) {
+		t.Errorf("expected not found for the missing task, got %+v", r)
+	}
+	if got, err := results[14].Task(); err != nil || got.Name != "Task 1" || got.Assignee != nil {
+		t.Errorf("get result: %+v, %v", got, err)
+	}
+
+	// Injected failures hit single actions; a failed request fails all of its own
+	srv.Fail(Failure{Method: "PUT", Path: "/tasks/" + tasks[2].GID, Status: http.StatusForbidden})
+	results, err = client.Batch(ctx, []asana.BatchAction{asana.CompleteTaskAction(tasks[2].GID), asana.CompleteTaskAction(tasks[3].GID)})
+	if err != nil || !asana.IsAuth(results[0].Err) || results[1].Err != nil {
+		t.Errorf("expected only the first action to fail, got %v: %+v", err, results)
+	}
+
+	srv.Fail(Failure{Path: "/batch", Status: http.StatusBadRequest, Times: 1})
+	results, err = client.Batch(ctx, []asana.BatchAction{asana.CompleteTaskAction(tasks[3].GID)})
+	if !asana.IsInvalid(err) || !asana.IsInvalid(results[0].Err) {
+		t.Errorf("expected the request's error on every action, got %v: %+v", err, results)
+	}
+}
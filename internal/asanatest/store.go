@@ -0,0 +1,957 @@
+package asanatest
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/TheCoolRobot/asana-cli/internal/asana"
+)
+
+// store is the in-memory state behind the fake API. Records are kept by
+// GID with separate order slices so list endpoints return them in a stable,
+// insertion order, like Asana does.
+type store struct {
+	nextGID int
+	me      string
+
+	users      map[string]*asana.User
+	workspaces []*asana.Workspace
+	teams      []*team
+
+	projects     map[string]*project
+	projectOrder []string
+
+	sections map[string]*section
+
+	tags     map[string]*tag
+	tagOrder []string
+
+	customFields map[string]*asana.CustomField
+
+	tasks     map[string]*task
+	taskOrder []string
+
+	stories map[string][]asana.Story
+
+	attachments     map[string]*attachment
+	taskAttachments map[string][]string
+
+	jobs map[string]*asana.Job
+}
+
+type tag struct {
+	asana.Tag
+	workspace string
+}
+
+type team struct {
+	asana.Team
+	workspace string
+}
+
+type attachment struct {
+	asana.Attachment
+	task        string
+	contentType string
+	data        []byte
+}
+
+type project struct {
+	asana.Project
+	workspace    string
+	sections     []string
+	customFields []string
+}
+
+type section struct {
+	asana.Section
+	project string
+	tasks   []string
+}
+
+type task struct {
+	asana.Task
+	workspace string
+	sections  []string
+	subtasks  []string
+}
+
+func newStore() *store {
+	st := &store{
+		nextGID:  1000,
+		users:    make(map[string]*asana.User),
+		projects: make(map[string]*project),
+		sections: make(map[string]*section),
+
+		customFields: make(map[string]*asana.CustomField),
+		tasks:        make(map[string]*task),
+		stories:      make(map[string][]asana.Story),
+
+		attachments:     make(map[string]*attachment),
+		taskAttachments: make(map[string][]string),
+
+		tags: make(map[string]*tag),
+		jobs: make(map[string]*asana.Job),
+	}
+
+	me := st.addUser(asana.User{Name: "Test User", Email: "test@example.com"})
+	st.me = me.GID
+
+	return st
+}
+
+func (st *store) newGID() string {
+	st.nextGID++
+	return strconv.Itoa(st.nextGID)
+}
+
+func (st *store) addUser(u asana.User) *asana.User {
+	if u.GID == "" {
+		u.GID = st.newGID()
+	}
+	st.users[u.GID] = &u
+	return &u
+}
+
+// user resolves a user reference as accepted by the API ("me" or a GID)
+func (st *store) user(ref string) (*asana.User, bool) {
+	if ref == "me" {
+		ref = st.me
+	}
+	u, ok := st.users[ref]
+	return u, ok
+}
+
+func (st *store) workspace(gid string) (*asana.Workspace, bool) {
+	for _, ws := range st.workspaces {
+		if ws.GID == gid {
+			return ws, true
+		}
+	}
+	return nil, false
+}
+
+func (st *store) team(gid string) (*team, bool) {
+	for _, t := range st.teams {
+		if t.GID == gid {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+// addTag tags a task, doing nothing if it already has the tag
+func (st *store) addTag(t *task, tg *tag) {
+	for _, have := range t.Tags {
+		if have.GID == tg.GID {
+			return
+		}
+	}
+	t.Tags = append(t.Tags, asana.Tag{GID: tg.GID, Name: tg.Name})
+}
+
+// removeTag untags a task
+func (st *store) removeTag(t *task, tagGID string) {
+	tags := make([]asana.Tag, 0, len(t.Tags))
+	for _, have := range t.Tags {
+		if have.GID != tagGID {
+			tags = append(tags, have)
+		}
+	}
+	t.Tags = tags
+}
+
+// deleteProject removes a project, its sections and its memberships. Tasks
+// stay behind, like tasks Asana moves out of a deleted project.
+func (st *store) deleteProject(p *project) {
+	for _, gid := range p.sections {
+		delete(st.sections, gid)
+	}
+	for _, t := range st.projectTasks(p.GID) {
+		projects := t.Projects[:0]
+		for _, tp := range t.Projects {
+			if tp.GID != p.GID {
+				projects = append(projects, tp)
+			}
+		}
+		t.Projects = projects
+		sections := t.sections[:0]
+		for _, gid := range t.sections {
+			if _, ok := st.sections[gid]; ok {
+				sections = append(sections, gid)
+			}
+		}
+		t.sections = sections
+		st.refreshMemberships(t)
+	}
+	delete(st.projects, p.GID)
+	st.projectOrder = without(st.projectOrder, p.GID)
+}
+
+// duplicateProject copies a project with its sections and tasks. include
+// holds the optional parts to copy, as accepted by the duplicate endpoint.
+func (st *store) duplicateProject(src *project, name, teamGID string, include map[string]bool) *project {
+	now := time.Now().UTC()
+	p := &project{
+		Project: asana.Project{
+			GID:         st.newGID(),
+			Name:        name,
+			Team:        src.Team,
+			Color:       src.Color,
+			DefaultView: src.DefaultView,
+			Public:      src.Public,
+			CreatedAt:   now,
+			ModifiedAt:  now,
+		},
+		workspace:    src.workspace,
+		customFields: append([]string(nil), src.customFields...),
+	}
+	if include["notes"] {
+		p.Description = src.Description
+	}
+	if tm, ok := st.team(teamGID); ok {
+		p.Team = &asana.Team{GID: tm.GID, Name: tm.Name}
+	}
+	st.projects[p.GID] = p
+	st.projectOrder = append(st.projectOrder, p.GID)
+
+	copies := make(map[string]string)
+	for _, gid := range src.sections {
+		sec := &section{
+			Section: asana.Section{GID: st.newGID(), Name: st.sections[gid].Name, ProjectID: p.GID},
+			project: p.GID,
+		}
+		st.sections[sec.GID] = sec
+		p.sections = append(p.sections, sec.GID)
+		copies[gid] = sec.GID
+	}
+
+	for _, t := range st.projectTasks(src.GID) {
+		c := &task{
+			Task: asana.Task{
+				GID:        st.newGID(),
+				Name:       t.Name,
+				Projects:   []asana.Project{compactProject(p)},
+				CreatedAt:  now,
+				ModifiedAt: now,
+			},
+			workspace: t.workspace,
+		}
+		if include["task_notes"] {
+			c.Description = t.Description
+		}
+		if include["task_assignee"] {
+			c.Assignee = t.Assignee
+		}
+		if include["task_dates"] {
+			c.DueDate, c.DueAt = t.DueDate, t.DueAt
+		}
+		if include["task_tags"] {
+			c.Tags = t.Tags
+		}
+		st.addProjectFields(c, p)
+		st.tasks[c.GID] = c
+		st.taskOrder = append(st.taskOrder, c.GID)
+		for _, gid := range t.sections {
+			if dup, ok := copies[gid]; ok {
+				st.addToSection(c, st.sections[dup], "", "")
+			}
+		}
+		st.refreshMemberships(c)
+	}
+	return p
+}
+
+// projectTasks returns the tasks in a project, in creation order
+func (st *store) projectTasks(projectGID string) []*task {
+	var tasks []*task
+	for _, gid := range st.taskOrder {
+		t := st.tasks[gid]
+		if t.inProject(projectGID) {
+			tasks = append(tasks, t)
+		}
+	}
+	return tasks
+}
+
+func (t *task) inProject(projectGID string) bool {
+	for _, p := range t.Projects {
+		if p.GID == projectGID {
+			return true
+		}
+	}
+	return false
+}
+
+// deleteTask removes a task along with its subtasks, as Asana does
+func (st *store) deleteTask(gid string) {
+	t, ok := st.tasks[gid]
+	if !ok {
+		return
+	}
+	for _, sub := range t.subtasks {
+		st.deleteTask(sub)
+	}
+	st.detach(t)
+	for _, sec := range t.sections {
+		if s, ok := st.sections[sec]; ok {
+			s.tasks = without(s.tasks, gid)
+		}
+	}
+	for _, dep := range t.Dependencies {
+		st.removeDependency(t, dep.GID)
+	}
+	for _, dep := range t.Dependents {
+		if d, ok := st.tasks[dep.GID]; ok {
+			st.removeDependency(d, gid)
+		}
+	}
+
+	delete(st.tasks, gid)
+	delete(st.stories, gid)
+	for _, a := range st.taskAttachments[gid] {
+		delete(st.attachments, a)
+	}
+	delete(st.taskAttachments, gid)
+	for i, g := range st.taskOrder {
+		if g == gid {
+			st.taskOrder = append(st.taskOrder[:i], st.taskOrder[i+1:]...)
+			break
+		}
+	}
+}
+
+// setParent moves t under parentGID ("" for top level), placed before or
+// after a sibling when one is given and at the end otherwise
+func (st *store) setParent(t *task, parentGID, before, after string) error {
+	var parent *task
+	if parentGID != "" {
+		p, ok := st.tasks[parentGID]
+		if !ok {
+			return fmt.Errorf("parent: Not a recognized ID: %s", parentGID)
+		}
+		for a := p; a != nil; a = st.parentOf(a) {
+			if a.GID == t.GID {
+				return fmt.Errorf("parent: Cannot make a task a subtask of itself or its descendants")
+			}
+		}
+		parent = p
+	}
+
+	st.detach(t)
+	if parent == nil {
+		return nil
+	}
+
+	parent.subtasks = insertAt(parent.subtasks, t.GID, before, after)
+	parent.NumSubtasks = len(parent.subtasks)
+
+	t.Parent = &asana.Task{GID: parent.GID, Name: parent.Name}
+	if t.workspace == "" {
+		t.workspace = parent.workspace
+	}
+	return nil
+}
+
+// detach removes t from its parent's subtasks, making it top level
+func (st *store) detach(t *task) {
+	if p := st.parentOf(t); p != nil {
+		p.subtasks = without(p.subtasks, t.GID)
+		p.NumSubtasks = len(p.subtasks)
+	}
+	t.Parent = nil
+}
+
+func (st *store) parentOf(t *task) *task {
+	if t.Parent == nil {
+		return nil
+	}
+	return st.tasks[t.Parent.GID]
+}
+
+// addToSection moves t into sec, out of any other section of the same
+// project, adding it to the project if needed. The task is placed before or
+// after a task in the section when one is given and at the end otherwise.
+func (st *store) addToSection(t *task, sec *section, before, after string) {
+	if p, ok := st.projects[sec.project]; ok && !t.inProject(p.GID) {
+		t.Projects = append(t.Projects, compactProject(p))
+		if t.workspace == "" {
+			t.workspace = p.workspace
+		}
+		st.addProjectFields(t, p)
+	}
+
+	for _, gid := range t.sections {
+		if other := st.sections[gid]; other.project == sec.project {
+			other.tasks = without(other.tasks, t.GID)
+			t.sections = without(t.sections, gid)
+		}
+	}
+	t.sections = append(t.sections, sec.GID)
+	sec.tasks = insertAt(sec.tasks, t.GID, before, after)
+	st.refreshMemberships(t)
+}
+
+// refreshMemberships rebuilds a task's memberships from its projects and
+// sections
+func (st *store) refreshMemberships(t *task) {
+	t.Memberships = nil
+	for _, p := range t.Projects {
+		m := asana.Membership{Project: &asana.Project{GID: p.GID, Name: p.Name}}
+		for _, gid := range t.sections {
+			if sec := st.sections[gid]; sec.project == p.GID {
+				m.Section = &asana.Section{GID: sec.GID, Name: sec.Name}
+			}
+		}
+		t.Memberships = append(t.Memberships, m)
+	}
+}
+
+// insertAt inserts gid into list before or after another entry, or at the
+// end when neither is given or found
+func insertAt(list []string, gid, before, after string) []string {
+	pos := len(list)
+	for i, g := range list {
+		if g == before {
+			pos = i
+		} else if g == after {
+			pos = i + 1
+		}
+	}
+	return append(list[:pos], append([]string{gid}, list[pos:]...)...)
+}
+
+func without(list []string, gid string) []string {
+	out := list[:0]
+	for _, g := range list {
+		if g != gid {
+			out = append(out, g)
+		}
+	}
+	return out
+}
+
+// addDependency marks t as blocked by dependency, refusing self
+// references and cycles as the API does
+func (st *store) addDependency(t, dependency *task) error {
+	if st.dependsOn(dependency, t.GID, map[string]bool{}) {
+		return fmt.Errorf("dependencies: Cannot add %s as a dependency of %s: it would create a cycle", dependency.GID, t.GID)
+	}
+	for _, d := range t.Dependencies {
+		if d.GID == dependency.GID {
+			return nil
+		}
+	}
+	t.Dependencies = append(t.Dependencies, asana.Task{GID: dependency.GID, Name: dependency.Name})
+	dependency.Dependents = append(dependency.Dependents, asana.Task{GID: t.GID, Name: t.Name})
+	return nil
+}
+
+// dependsOn reports whether t is, or is transitively blocked by, gid
+func (st *store) dependsOn(t *task, gid string, seen map[string]bool) bool {
+	if t.GID == gid {
+		return true
+	}
+	if seen[t.GID] {
+		return false
+	}
+	seen[t.GID] = true
+	for _, d := range t.Dependencies {
+		if next, ok := st.tasks[d.GID]; ok && st.dependsOn(next, gid, seen) {
+			return true
+		}
+	}
+	return false
+}
+
+func (st *store) removeDependency(t *task, dependencyGID string) {
+	t.Dependencies = withoutTask(t.Dependencies, dependencyGID)
+	if d, ok := st.tasks[dependencyGID]; ok {
+		d.Dependents = withoutTask(d.Dependents, t.GID)
+	}
+}
+
+func withoutTask(tasks []asana.Task, gid string) []asana.Task {
+	out := tasks[:0]
+	for _, t := range tasks {
+		if t.GID != gid {
+			out = append(out, t)
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// addProjectFields gives a task an empty value for each of a project's
+// custom fields it doesn't already have, as Asana does when a task is added
+// to a project
+func (st *store) addProjectFields(t *task, p *project) {
+	for _, gid := range p.customFields {
+		if t.CustomField(gid) == nil {
+			t.CustomFields = append(t.CustomFields, asana.CustomFieldValue{CustomField: *st.customFields[gid]})
+		}
+	}
+}
+
+// setCustomField assigns a value from a request payload to one of a task's
+// custom fields, validating it against the field's type
+func (st *store) setCustomField(t *task, gid string, raw json.RawMessage) error {
+	f := t.CustomField(gid)
+	if f == nil {
+		return fmt.Errorf("custom_fields: Custom field with ID %s is not on given object", gid)
+	}
+
+	// Clear the previous value, keeping the definition
+	*f = asana.CustomFieldValue{CustomField: f.CustomField}
+	if string(raw) == "null" {
+		return nil
+	}
+
+	invalid := fmt.Errorf("custom_fields: Invalid value for %s field %s", f.Type, f.Name)
+	option := func(gid string) (*asana.EnumOption, bool) {
+		for _, o := range f.EnumOptions {
+			if o.GID == gid {
+				return &asana.EnumOption{GID: o.GID, Name: o.Name, Color: o.Color, Enabled: o.Enabled}, true
+			}
+		}
+		return nil, false
+	}
+
+	switch f.Type {
+	case asana.CustomFieldNumber:
+		var n float64
+		if err := json.Unmarshal(raw, &n); err != nil {
+			return invalid
+		}
+		f.NumberValue = &n
+
+	case asana.CustomFieldEnum:
+		var gid string
+		json.Unmarshal(raw, &gid)
+		o, ok := option(gid)
+		if !ok {
+			return invalid
+		}
+		f.EnumValue = o
+
+	case asana.CustomFieldMultiEnum:
+		var gids []string
+		if err := json.Unmarshal(raw, &gids); err != nil {
+			return invalid
+		}
+		for _, gid := range gids {
+			o, ok := option(gid)
+			if !ok {
+				return invalid
+			}
+			f.MultiEnumValues = append(f.MultiEnumValues, *o)
+		}
+
+	case asana.CustomFieldDate:
+		var d asana.DateValue
+		if err := json.Unmarshal(raw, &d); err != nil || (d.Date == "" && d.DateTime == "") {
+			return invalid
+		}
+		if d.Date == "" && len(d.DateTime) >= 10 {
+			d.Date = d.DateTime[:10]
+		}
+		f.DateValue = &d
+
+	case asana.CustomFieldPeople:
+		var gids []string
+		if err := json.Unmarshal(raw, &gids); err != nil {
+			return invalid
+		}
+		for _, gid := range gids {
+			u, ok := st.user(gid)
+			if !ok {
+				return invalid
+			}
+			f.PeopleValue = append(f.PeopleValue, *compactUser(u))
+		}
+
+	default:
+		var text string
+		if err := json.Unmarshal(raw, &text); err != nil {
+			return invalid
+		}
+		f.TextValue = &text
+	}
+
+	f.DisplayValue = f.String()
+	return nil
+}
+
+// compactProject is the {gid, name} form Asana embeds in other records
+func compactProject(p *project) asana.Project {
+	return asana.Project{GID: p.GID, Name: p.Name}
+}
+
+func compactUser(u *asana.User) *asana.User {
+	return &asana.User{GID: u.GID, Name: u.Name}
+}
+
+// Me returns the user the fake API treats as authenticated
+func (s *Server) Me() asana.User {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return *s.store.users[s.store.me]
+}
+
+// AddUser adds a user, assigning a GID if none is set
+func (s *Server) AddUser(u asana.User) asana.User {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return *s.store.addUser(u)
+}
+
+// AddWorkspace adds a workspace
+func (s *Server) AddWorkspace(name string) asana.Workspace {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ws := &asana.Workspace{GID: s.store.newGID(), Name: name}
+	s.store.workspaces = append(s.store.workspaces, ws)
+	return *ws
+}
+
+// AddTeam adds a team to a workspace
+func (s *Server) AddTeam(workspaceGID, name string) asana.Team {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t := &team{Team: asana.Team{GID: s.store.newGID(), Name: name}, workspace: workspaceGID}
+	s.store.teams = append(s.store.teams, t)
+	return t.Team
+}
+
+// AddTag adds a tag to a workspace
+func (s *Server) AddTag(workspaceGID, name string) asana.Tag {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tg := &tag{Tag: asana.Tag{GID: s.store.newGID(), Name: name}, workspace: workspaceGID}
+	s.store.tags[tg.GID] = tg
+	s.store.tagOrder = append(s.store.tagOrder, tg.GID)
+	return tg.Tag
+}
+
+// TagTask adds a tag to a task
+func (s *Server) TagTask(taskGID, tagGID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.store.tasks[taskGID]
+	tg, ok2 := s.store.tags[tagGID]
+	if ok && ok2 {
+		s.store.addTag(t, tg)
+	}
+}
+
+// Tags returns the tags in a workspace, in creation order
+func (s *Server) Tags(workspaceGID string) []asana.Tag {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var tags []asana.Tag
+	for _, gid := range s.store.tagOrder {
+		if tg := s.store.tags[gid]; tg.workspace == workspaceGID {
+			tags = append(tags, tg.Tag)
+		}
+	}
+	return tags
+}
+
+// Project returns a project as stored by the fake API
+func (s *Server) Project(gid string) (asana.Project, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.store.projects[gid]
+	if !ok {
+		return asana.Project{}, false
+	}
+	return p.Project, true
+}
+
+// AddProject adds a project to a workspace
+func (s *Server) AddProject(workspaceGID, name string) asana.Project {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().UTC()
+	p := &project{
+		Project: asana.Project{
+			GID:        s.store.newGID(),
+			Name:       name,
+			CreatedAt:  now,
+			ModifiedAt: now,
+		},
+		workspace: workspaceGID,
+	}
+	s.store.projects[p.GID] = p
+	s.store.projectOrder = append(s.store.projectOrder, p.GID)
+	return p.Project
+}
+
+// AddSection adds a section to the end of a project
+func (s *Server) AddSection(projectGID, name string) asana.Section {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sec := &section{
+		Section: asana.Section{GID: s.store.newGID(), Name: name, ProjectID: projectGID},
+		project: projectGID,
+	}
+	s.store.sections[sec.GID] = sec
+	if p, ok := s.store.projects[projectGID]; ok {
+		p.sections = append(p.sections, sec.GID)
+	}
+	return sec.Section
+}
+
+// MoveToSection places a task at the end of a section
+func (s *Server) MoveToSection(taskGID, sectionGID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.store.tasks[taskGID]
+	sec, ok2 := s.store.sections[sectionGID]
+	if ok && ok2 {
+		s.store.addToSection(t, sec, "", "")
+	}
+}
+
+// Sections returns a project's sections, in order
+func (s *Server) Sections(projectGID string) []asana.Section {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var sections []asana.Section
+	if p, ok := s.store.projects[projectGID]; ok {
+		for _, gid := range p.sections {
+			sections = append(sections, s.store.sections[gid].Section)
+		}
+	}
+	return sections
+}
+
+// SectionTasks returns the GIDs of the tasks in a section, in order
+func (s *Server) SectionTasks(sectionGID string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if sec, ok := s.store.sections[sectionGID]; ok {
+		return append([]string(nil), sec.tasks...)
+	}
+	return nil
+}
+
+// AddTask adds a task to a project. The task's GID and timestamps are
+// filled in when unset. An empty projectGID creates a task outside of any
+// project.
+func (s *Server) AddTask(projectGID string, t asana.Task) asana.Task {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if t.GID == "" {
+		t.GID = s.store.newGID()
+	}
+	now := time.Now().UTC()
+	if t.CreatedAt.IsZero() {
+		t.CreatedAt = now
+	}
+	if t.ModifiedAt.IsZero() {
+		t.ModifiedAt = now
+	}
+
+	rec := &task{Task: t}
+	if p, ok := s.store.projects[projectGID]; ok {
+		rec.Projects = append(rec.Projects, compactProject(p))
+		rec.workspace = p.workspace
+		s.store.addProjectFields(rec, p)
+		s.store.refreshMemberships(rec)
+	}
+
+	s.store.tasks[t.GID] = rec
+	s.store.taskOrder = append(s.store.taskOrder, t.GID)
+	return rec.Task
+}
+
+// AddCustomField defines a custom field and enables it on a project,
+// assigning GIDs to the field and its enum options when unset. Tasks
+// already in the project get an empty value for it.
+func (s *Server) AddCustomField(projectGID string, f asana.CustomField) asana.CustomField {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if f.GID == "" {
+		f.GID = s.store.newGID()
+	}
+	if f.Type == "" {
+		f.Type = asana.CustomFieldText
+	}
+	for i := range f.EnumOptions {
+		if f.EnumOptions[i].GID == "" {
+			f.EnumOptions[i].GID = s.store.newGID()
+		}
+		f.EnumOptions[i].Enabled = true
+	}
+	s.store.customFields[f.GID] = &f
+
+	if p, ok := s.store.projects[projectGID]; ok {
+		p.customFields = append(p.customFields, f.GID)
+		for _, t := range s.store.projectTasks(projectGID) {
+			s.store.addProjectFields(t, p)
+		}
+	}
+	return f
+}
+
+// AddAttachment attaches a file to a task. The content type is detected
+// from the name and data.
+func (s *Server) AddAttachment(taskGID, name string, data []byte) asana.Attachment {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addAttachment(taskGID, name, asana.DetectContentType(name, data), data)
+}
+
+// addAttachment stores a file; callers must hold s.mu
+func (s *Server) addAttachment(taskGID, name, contentType string, data []byte) asana.Attachment {
+	gid := s.store.newGID()
+	a := &attachment{
+		Attachment: asana.Attachment{
+			GID:          gid,
+			Name:         name,
+			Host:         "asana",
+			Size:         int64(len(data)),
+			DownloadURL:  s.URL + "/files/" + gid,
+			PermanentURL: s.URL + "/app/asana/-/get_asset?asset_id=" + gid,
+			ViewURL:      s.URL + "/files/" + gid,
+			CreatedAt:    time.Now().UTC(),
+		},
+		task:        taskGID,
+		contentType: contentType,
+		data:        data,
+	}
+	s.store.attachments[gid] = a
+	s.store.taskAttachments[taskGID] = append(s.store.taskAttachments[taskGID], gid)
+	return a.Attachment
+}
+
+// AttachmentData returns the stored contents and content type of an
+// attachment
+func (s *Server) AttachmentData(gid string) ([]byte, string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, ok := s.store.attachments[gid]
+	if !ok {
+		return nil, "", false
+	}
+	return a.data, a.contentType, true
+}
+
+// AddSubtask adds a task as the last subtask of parentGID. The subtask's
+// GID and timestamps are filled in when unset.
+func (s *Server) AddSubtask(parentGID string, t asana.Task) asana.Task {
+	sub := s.AddTask("", t)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec := s.store.tasks[sub.GID]
+	s.store.setParent(rec, parentGID, "", "")
+	return rec.Task
+}
+
+// Subtasks returns the stored subtasks of a task, in order
+func (s *Server) Subtasks(parentGID string) []asana.Task {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var tasks []asana.Task
+	if p, ok := s.store.tasks[parentGID]; ok {
+		for _, gid := range p.subtasks {
+			tasks = append(tasks, s.store.tasks[gid].Task)
+		}
+	}
+	return tasks
+}
+
+// AddDependency marks taskGID as blocked by dependencyGID
+func (s *Server) AddDependency(taskGID, dependencyGID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.store.tasks[taskGID]
+	if !ok {
+		return fmt.Errorf("task: Not a recognized ID: %s", taskGID)
+	}
+	dep, ok := s.store.tasks[dependencyGID]
+	if !ok {
+		return fmt.Errorf("dependencies: Not a recognized ID: %s", dependencyGID)
+	}
+	return s.store.addDependency(t, dep)
+}
+
+// Task returns the stored state of a task
+func (s *Server) Task(gid string) (asana.Task, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.store.tasks[gid]
+	if !ok {
+		return asana.Task{}, false
+	}
+	return t.Task, true
+}
+
+// Tasks returns the stored tasks in a project, in creation order
+func (s *Server) Tasks(projectGID string) []asana.Task {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var tasks []asana.Task
+	for _, t := range s.store.projectTasks(projectGID) {
+		tasks = append(tasks, t.Task)
+	}
+	return tasks
+}
+
+// AddStory appends a story to a task's activity feed. The GID, creation
+// time and author (the authenticated user) are filled in when unset.
+func (s *Server) AddStory(taskGID string, story asana.Story) asana.Story {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.store.addStory(taskGID, story)
+}
+
+func (st *store) addStory(taskGID string, story asana.Story) asana.Story {
+	if story.GID == "" {
+		story.GID = st.newGID()
+	}
+	if story.CreatedAt.IsZero() {
+		story.CreatedAt = time.Now().UTC()
+	}
+	if story.CreatedBy == nil {
+		story.CreatedBy = compactUser(st.users[st.me])
+	}
+	if story.Type == "" {
+		story.Type = "comment"
+		story.ResourceSubtype = "comment_added"
+	}
+	st.stories[taskGID] = append(st.stories[taskGID], story)
+	return story
+}
+
+// Stories returns a task's activity feed
+func (s *Server) Stories(taskGID string) []asana.Story {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]asana.Story(nil), s.store.stories[taskGID]...)
+}
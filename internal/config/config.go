@@ -19,6 +19,7 @@ type Config struct {
 	CurrentProject   string                    `json:"current_project"` // Name of active project
 	Projects         map[string]ProjectConfig  `json:"projects"`
 	DefaultWorkspace string                    `json:"default_workspace"`
+	Templates        map[string]string         `json:"templates,omitempty"` // Named --format templates
 }
 
 func GetConfigPath() string {
@@ -137,6 +138,30 @@ func (c *Config) ListProjects() []ProjectConfig {
 	return projects
 }
 
+func (c *Config) SetTemplate(name, text string) error {
+	if name == "" {
+		return fmt.Errorf("template name cannot be empty")
+	}
+	if text == "" {
+		return fmt.Errorf("template cannot be empty")
+	}
+
+	if c.Templates == nil {
+		c.Templates = make(map[string]string)
+	}
+	c.Templates[name] = text
+	return c.Save()
+}
+
+func (c *Config) RemoveTemplate(name string) error {
+	if _, exists := c.Templates[name]; !exists {
+		return fmt.Errorf("template '%s' not found", name)
+	}
+
+	delete(c.Templates, name)
+	return c.Save()
+}
+
 func GetAPIToken() string {
 	if token := os.Getenv("ASANA_TOKEN"); token != "" {
 		return token
@@ -149,4 +149,33 @@ func TestListProjects(t *testing.T) {
 	if len(projects) != 2 {
 		t.Errorf("expected 2 projects, got %d", len(projects))
 	}
-}
\ No newline at end of file
+}
+
+func TestTemplates(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	cfg := &Config{
+		Projects: make(map[string]ProjectConfig),
+	}
+
+	if err := cfg.SetTemplate("prompt", "{{.Name}}"); err != nil {
+		t.Fatalf("SetTemplate failed: %v", err)
+	}
+	if err := cfg.SetTemplate("", "{{.Name}}"); err == nil {
+		t.Error("expected error for an empty template name")
+	}
+
+	loaded, err := Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if loaded.Templates["prompt"] != "{{.Name}}" {
+		t.Errorf("template not saved: %v", loaded.Templates)
+	}
+
+	if err := loaded.RemoveTemplate("prompt"); err != nil {
+		t.Fatalf("RemoveTemplate failed: %v", err)
+	}
+	if err := loaded.RemoveTemplate("prompt"); err == nil {
+		t.Error("expected error when template not found")
+	}
+}
@@ -0,0 +1,888 @@
+package jq
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Run applies the query to a decoded JSON value (maps, slices, strings,
+// float64s, bools and nil, as from json.Unmarshal into interface{}) and
+// returns every result
+func (q *Query) Run(input interface{}) ([]interface{}, error) {
+	return q.root.eval(input)
+}
+
+// node is one step of a compiled query. Like jq filters, a node may
+// produce any number of results for one input.
+type node interface {
+	eval(in interface{}) ([]interface{}, error)
+}
+
+type identity struct{}
+
+func (identity) eval(in interface{}) ([]interface{}, error) {
+	return []interface{}{in}, nil
+}
+
+type literal struct{ value interface{} }
+
+func (l literal) eval(interface{}) ([]interface{}, error) {
+	return []interface{}{l.value}, nil
+}
+
+type pipe struct{ left, right node }
+
+func (n pipe) eval(in interface{}) ([]interface{}, error) {
+	lefts, err := n.left.eval(in)
+	if err != nil {
+		return nil, err
+	}
+	var out []interface{}
+	for _, l := range lefts {
+		rights, err := n.right.eval(l)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, rights...)
+	}
+	return out, nil
+}
+
+type comma struct{ left, right node }
+
+func (n comma) eval(in interface{}) ([]interface{}, error) {
+	lefts, err := n.left.eval(in)
+	if err != nil {
+		return nil, err
+	}
+	rights, err := n.right.eval(in)
+	if err != nil {
+		return nil, err
+	}
+	return append(lefts, rights...), nil
+}
+
+// alternative is a // b: the truthy results of a, or else those of b
+type alternative struct{ left, right node }
+
+func (n alternative) eval(in interface{}) ([]interface{}, error) {
+	lefts, err := n.left.eval(in)
+	if err == nil {
+		var out []interface{}
+		for _, l := range lefts {
+			if truthy(l) {
+				out = append(out, l)
+			}
+		}
+		if len(out) > 0 {
+			return out, nil
+		}
+	}
+	return n.right.eval(in)
+}
+
+type logical struct {
+	and         bool
+	left, right node
+}
+
+func (n logical) eval(in interface{}) ([]interface{}, error) {
+	lefts, err := n.left.eval(in)
+	if err != nil {
+		return nil, err
+	}
+	var out []interface{}
+	for _, l := range lefts {
+		// Short-circuit as jq does: false and x, true or x
+		if truthy(l) != n.and {
+			out = append(out, truthy(l))
+			continue
+		}
+		rights, err := n.right.eval(in)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range rights {
+			out = append(out, truthy(r))
+		}
+	}
+	return out, nil
+}
+
+type comparison struct {
+	op          string
+	left, right node
+}
+
+func (n comparison) eval(in interface{}) ([]interface{}, error) {
+	lefts, err := n.left.eval(in)
+	if err != nil {
+		return nil, err
+	}
+	rights, err := n.right.eval(in)
+	if err != nil {
+		return nil, err
+	}
+	var out []interface{}
+	for _, r := range rights {
+		for _, l := range lefts {
+			c := compare(l, r)
+			var result bool
+			switch n.op {
+			case "==":
+				result = c == 0
+			case "!=":
+				result = c != 0
+			case "<":
+				result = c < 0
+			case "<=":
+				result = c <= 0
+			case ">":
+				result = c > 0
+			case ">=":
+				result = c >= 0
+			}
+			out = append(out, result)
+		}
+	}
+	return out, nil
+}
+
+// index is .name, ."name" or .[expr]. The key is evaluated against the
+// same input as the target, so .[.i] works as in jq.
+type index struct{ target, key node }
+
+func (n index) eval(in interface{}) ([]interface{}, error) {
+	targets, err := n.target.eval(in)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := n.key.eval(in)
+	if err != nil {
+		return nil, err
+	}
+	var out []interface{}
+	for _, t := range targets {
+		for _, k := range keys {
+			v, err := lookup(t, k)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, v)
+		}
+	}
+	return out, nil
+}
+
+func lookup(v, key interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case nil:
+		return nil, nil
+	case map[string]interface{}:
+		if k, ok := key.(string); ok {
+			return v[k], nil
+		}
+	case []interface{}:
+		if f, ok := key.(float64); ok {
+			i := int(f)
+			if i < 0 {
+				i += len(v)
+			}
+			if i < 0 || i >= len(v) {
+				return nil, nil
+			}
+			return v[i], nil
+		}
+	}
+	return nil, fmt.Errorf("cannot index %s with %s", typeName(v), describe(key))
+}
+
+type iterate struct{ target node }
+
+func (n iterate) eval(in interface{}) ([]interface{}, error) {
+	targets, err := n.target.eval(in)
+	if err != nil {
+		return nil, err
+	}
+	var out []interface{}
+	for _, t := range targets {
+		values, err := elements(t)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, values...)
+	}
+	return out, nil
+}
+
+// elements returns the values of an array, or of an object in key order
+func elements(v interface{}) ([]interface{}, error) {
+	switch v := v.(type) {
+	case []interface{}:
+		return v, nil
+	case map[string]interface{}:
+		out := make([]interface{}, 0, len(v))
+		for _, k := range sortedKeys(v) {
+			out = append(out, v[k])
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("cannot iterate over %s", typeName(v))
+}
+
+type slice struct{ target, from, to node }
+
+func (n slice) eval(in interface{}) ([]interface{}, error) {
+	targets, err := n.target.eval(in)
+	if err != nil {
+		return nil, err
+	}
+	from, err := n.bound(in, n.from)
+	if err != nil {
+		return nil, err
+	}
+	to, err := n.bound(in, n.to)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []interface{}
+	for _, t := range targets {
+		var length int
+		switch t := t.(type) {
+		case nil:
+			out = append(out, nil)
+			continue
+		case []interface{}:
+			length = len(t)
+		case string:
+			length = len([]rune(t))
+		default:
+			return nil, fmt.Errorf("cannot slice %s", typeName(t))
+		}
+		i, j := clampRange(from, to, length)
+		switch t := t.(type) {
+		case []interface{}:
+			out = append(out, append([]interface{}{}, t[i:j]...))
+		case string:
+			out = append(out, string([]rune(t)[i:j]))
+		}
+	}
+	return out, nil
+}
+
+// bound evaluates a slice bound, which must be a single number if given
+func (n slice) bound(in interface{}, b node) (*int, error) {
+	if b == nil {
+		return nil, nil
+	}
+	values, err := b.eval(in)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) != 1 {
+		return nil, fmt.Errorf("slice bounds must be single numbers")
+	}
+	f, ok := values[0].(float64)
+	if !ok {
+		return nil, fmt.Errorf("slice bounds must be numbers, not %s", typeName(values[0]))
+	}
+	i := int(math.Floor(f))
+	return &i, nil
+}
+
+func clampRange(from, to *int, length int) (int, int) {
+	i, j := 0, length
+	if from != nil {
+		i = *from
+	}
+	if to != nil {
+		j = *to
+	}
+	if i < 0 {
+		i += length
+	}
+	if j < 0 {
+		j += length
+	}
+	i = max(0, min(i, length))
+	j = max(i, min(j, length))
+	return i, j
+}
+
+// try is expr?, which drops the errors of expr
+type try struct{ body node }
+
+func (n try) eval(in interface{}) ([]interface{}, error) {
+	out, err := n.body.eval(in)
+	if err != nil {
+		return nil, nil
+	}
+	return out, nil
+}
+
+// collect is [expr], gathering every result into an array
+type collect struct{ body node }
+
+func (n collect) eval(in interface{}) ([]interface{}, error) {
+	if n.body == nil {
+		return []interface{}{[]interface{}{}}, nil
+	}
+	values, err := n.body.eval(in)
+	if err != nil {
+		return nil, err
+	}
+	if values == nil {
+		values = []interface{}{}
+	}
+	return []interface{}{values}, nil
+}
+
+type entry struct{ key, value node }
+
+type object struct{ entries []entry }
+
+// eval builds one object per combination of the entries' results
+func (n object) eval(in interface{}) ([]interface{}, error) {
+	objects := []map[string]interface{}{{}}
+	for _, e := range n.entries {
+		keys, err := e.key.eval(in)
+		if err != nil {
+			return nil, err
+		}
+		values, err := e.value.eval(in)
+		if err != nil {
+			return nil, err
+		}
+		var next []map[string]interface{}
+		for _, obj := range objects {
+			for _, k := range keys {
+				name, ok := k.(string)
+				if !ok {
+					return nil, fmt.Errorf("object keys must be strings, not %s", typeName(k))
+				}
+				for _, v := range values {
+					o := make(map[string]interface{}, len(obj)+1)
+					for ek, ev := range obj {
+						o[ek] = ev
+					}
+					o[name] = v
+					next = append(next, o)
+				}
+			}
+		}
+		objects = next
+	}
+	out := make([]interface{}, len(objects))
+	for i, o := range objects {
+		out[i] = o
+	}
+	return out, nil
+}
+
+type call struct {
+	name string
+	args []node
+}
+
+func (n call) eval(in interface{}) ([]interface{}, error) {
+	one := func(v interface{}) ([]interface{}, error) { return []interface{}{v}, nil }
+
+	// Functions with arguments
+	switch n.name {
+	case "select":
+		conds, err := n.args[0].eval(in)
+		if err != nil {
+			return nil, err
+		}
+		var out []interface{}
+		for _, c := range conds {
+			if truthy(c) {
+				out = append(out, in)
+			}
+		}
+		return out, nil
+	case "map":
+		values, err := elements(in)
+		if err != nil {
+			return nil, err
+		}
+		out := []interface{}{}
+		for _, v := range values {
+			mapped, err := n.args[0].eval(v)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, mapped...)
+		}
+		return one(out)
+	case "sort_by", "group_by", "unique_by", "min_by", "max_by":
+		return n.byKey(in)
+	case "has", "test", "startswith", "endswith", "contains", "join", "split":
+		args, err := n.args[0].eval(in)
+		if err != nil {
+			return nil, err
+		}
+		var out []interface{}
+		for _, arg := range args {
+			v, err := stringFunc(n.name, in, arg)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, v)
+		}
+		return out, nil
+	}
+
+	switch n.name {
+	case "empty":
+		return nil, nil
+	case "not":
+		return one(!truthy(in))
+	case "length":
+		switch v := in.(type) {
+		case nil:
+			return one(0.0)
+		case float64:
+			return one(math.Abs(v))
+		case string:
+			return one(float64(len([]rune(v))))
+		case []interface{}:
+			return one(float64(len(v)))
+		case map[string]interface{}:
+			return one(float64(len(v)))
+		}
+	case "keys":
+		switch v := in.(type) {
+		case map[string]interface{}:
+			keys := []interface{}{}
+			for _, k := range sortedKeys(v) {
+				keys = append(keys, k)
+			}
+			return one(keys)
+		case []interface{}:
+			keys := make([]interface{}, len(v))
+			for i := range v {
+				keys[i] = float64(i)
+			}
+			return one(keys)
+		}
+	case "values":
+		if in == nil {
+			return nil, nil
+		}
+		return one(in)
+	case "first", "last":
+		if a, ok := in.([]interface{}); ok || in == nil {
+			if len(a) == 0 {
+				return one(nil)
+			}
+			if n.name == "first" {
+				return one(a[0])
+			}
+			return one(a[len(a)-1])
+		}
+	case "type":
+		return one(typeName(in))
+	case "tostring":
+		if s, ok := in.(string); ok {
+			return one(s)
+		}
+		b, err := json.Marshal(in)
+		return []interface{}{string(b)}, err
+	case "tonumber":
+		switch v := in.(type) {
+		case float64:
+			return one(v)
+		case string:
+			f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			if err != nil {
+				return nil, fmt.Errorf("cannot parse %q as a number", v)
+			}
+			return one(f)
+		}
+	case "ascii_downcase", "ascii_upcase":
+		if s, ok := in.(string); ok {
+			if n.name == "ascii_downcase" {
+				return one(strings.ToLower(s))
+			}
+			return one(strings.ToUpper(s))
+		}
+	case "to_entries":
+		if m, ok := in.(map[string]interface{}); ok {
+			out := []interface{}{}
+			for _, k := range sortedKeys(m) {
+				out = append(out, map[string]interface{}{"key": k, "value": m[k]})
+			}
+			return one(out)
+		}
+	case "sort", "reverse", "unique", "add", "min", "max", "any", "all":
+		if a, ok := in.([]interface{}); ok || in == nil {
+			return one(arrayFunc(n.name, a))
+		}
+	}
+	return nil, fmt.Errorf("%s cannot be applied to %s", n.name, typeName(in))
+}
+
+// byKey implements the *_by functions, which compare elements by the
+// results of an expression
+func (n call) byKey(in interface{}) ([]interface{}, error) {
+	a, ok := in.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s cannot be applied to %s", n.name, typeName(in))
+	}
+	type keyed struct {
+		key   interface{}
+		value interface{}
+	}
+	items := make([]keyed, len(a))
+	for i, v := range a {
+		keys, err := n.args[0].eval(v)
+		if err != nil {
+			return nil, err
+		}
+		items[i] = keyed{key: keys, value: v}
+		if len(keys) == 1 {
+			items[i].key = keys[0]
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool { return compare(items[i].key, items[j].key) < 0 })
+
+	switch n.name {
+	case "min_by", "max_by":
+		if len(items) == 0 {
+			return []interface{}{nil}, nil
+		}
+		if n.name == "min_by" {
+			return []interface{}{items[0].value}, nil
+		}
+		return []interface{}{items[len(items)-1].value}, nil
+	case "group_by", "unique_by":
+		out := []interface{}{}
+		for i, it := range items {
+			if i > 0 && compare(items[i-1].key, it.key) == 0 {
+				if n.name == "group_by" {
+					group := out[len(out)-1].([]interface{})
+					out[len(out)-1] = append(group, it.value)
+				}
+				continue
+			}
+			if n.name == "group_by" {
+				out = append(out, []interface{}{it.value})
+			} else {
+				out = append(out, it.value)
+			}
+		}
+		return []interface{}{out}, nil
+	}
+	out := make([]interface{}, len(items))
+	for i, it := range items {
+		out[i] = it.value
+	}
+	return []interface{}{out}, nil
+}
+
+func arrayFunc(name string, a []interface{}) interface{} {
+	switch name {
+	case "sort", "unique":
+		sorted := append([]interface{}{}, a...)
+		sort.SliceStable(sorted, func(i, j int) bool { return compare(sorted[i], sorted[j]) < 0 })
+		if name == "sort" {
+			return sorted
+		}
+		out := []interface{}{}
+		for i, v := range sorted {
+			if i == 0 || compare(sorted[i-1], v) != 0 {
+				out = append(out, v)
+			}
+		}
+		return out
+	case "reverse":
+		out := make([]interface{}, len(a))
+		for i, v := range a {
+			out[len(a)-1-i] = v
+		}
+		return out
+	case "min", "max":
+		if len(a) == 0 {
+			return nil
+		}
+		best := a[0]
+		for _, v := range a[1:] {
+			if c := compare(v, best); (name == "min" && c < 0) || (name == "max" && c > 0) {
+				best = v
+			}
+		}
+		return best
+	case "any", "all":
+		for _, v := range a {
+			if truthy(v) == (name == "any") {
+				return name == "any"
+			}
+		}
+		return name == "all"
+	}
+	return add(a)
+}
+
+// add sums numbers, or concatenates strings, arrays or objects; nulls
+// are skipped
+func add(a []interface{}) interface{} {
+	var sum interface{}
+	for _, v := range a {
+		switch v := v.(type) {
+		case nil:
+		case float64:
+			if s, ok := sum.(float64); ok {
+				sum = s + v
+			} else {
+				sum = v
+			}
+		case string:
+			if s, ok := sum.(string); ok {
+				sum = s + v
+			} else {
+				sum = v
+			}
+		case []interface{}:
+			if s, ok := sum.([]interface{}); ok {
+				sum = append(s, v...)
+			} else {
+				sum = append([]interface{}{}, v...)
+			}
+		case map[string]interface{}:
+			merged := map[string]interface{}{}
+			if s, ok := sum.(map[string]interface{}); ok {
+				for k, x := range s {
+					merged[k] = x
+				}
+			}
+			for k, x := range v {
+				merged[k] = x
+			}
+			sum = merged
+		}
+	}
+	return sum
+}
+
+func stringFunc(name string, in, arg interface{}) (interface{}, error) {
+	switch name {
+	case "has":
+		switch v := in.(type) {
+		case map[string]interface{}:
+			if k, ok := arg.(string); ok {
+				_, found := v[k]
+				return found, nil
+			}
+		case []interface{}:
+			if i, ok := arg.(float64); ok {
+				return i >= 0 && int(i) < len(v), nil
+			}
+		}
+		return nil, fmt.Errorf("cannot check whether %s has %s", typeName(in), describe(arg))
+	case "contains":
+		return contains(in, arg), nil
+	case "join":
+		a, ok := in.([]interface{})
+		sep, sepOK := arg.(string)
+		if !ok || !sepOK {
+			return nil, fmt.Errorf("join needs an array and a string separator")
+		}
+		parts := make([]string, 0, len(a))
+		for _, v := range a {
+			switch v := v.(type) {
+			case nil:
+				parts = append(parts, "")
+			case string:
+				parts = append(parts, v)
+			case float64, bool:
+				b, _ := json.Marshal(v)
+				parts = append(parts, string(b))
+			default:
+				return nil, fmt.Errorf("cannot join %s", typeName(v))
+			}
+		}
+		return strings.Join(parts, sep), nil
+	}
+
+	s, ok := in.(string)
+	a, argOK := arg.(string)
+	if !ok || !argOK {
+		return nil, fmt.Errorf("%s needs strings, not %s and %s", name, typeName(in), typeName(arg))
+	}
+	switch name {
+	case "test":
+		re, err := regexp.Compile(a)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression %q: %v", a, err)
+		}
+		return re.MatchString(s), nil
+	case "startswith":
+		return strings.HasPrefix(s, a), nil
+	case "endswith":
+		return strings.HasSuffix(s, a), nil
+	}
+	parts := strings.Split(s, a)
+	out := make([]interface{}, len(parts))
+	for i, p := range parts {
+		out[i] = p
+	}
+	return out, nil
+}
+
+// contains follows jq: substrings for strings, and for arrays and objects
+// every element of b must be contained in some element of a
+func contains(a, b interface{}) bool {
+	switch a := a.(type) {
+	case string:
+		s, ok := b.(string)
+		return ok && strings.Contains(a, s)
+	case []interface{}:
+		bs, ok := b.([]interface{})
+		if !ok {
+			return false
+		}
+		for _, x := range bs {
+			found := false
+			for _, y := range a {
+				if contains(y, x) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+		return true
+	case map[string]interface{}:
+		bm, ok := b.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for k, x := range bm {
+			y, found := a[k]
+			if !found || !contains(y, x) {
+				return false
+			}
+		}
+		return true
+	}
+	return compare(a, b) == 0
+}
+
+func truthy(v interface{}) bool {
+	return v != nil && v != false
+}
+
+// rank orders the JSON types as jq does: null, false, true, numbers,
+// strings, arrays, objects
+func rank(v interface{}) int {
+	switch v := v.(type) {
+	case nil:
+		return 0
+	case bool:
+		if v {
+			return 2
+		}
+		return 1
+	case float64:
+		return 3
+	case string:
+		return 4
+	case []interface{}:
+		return 5
+	}
+	return 6
+}
+
+func compare(a, b interface{}) int {
+	if ra, rb := rank(a), rank(b); ra != rb {
+		return ra - rb
+	}
+	switch a := a.(type) {
+	case float64:
+		b := b.(float64)
+		switch {
+		case a < b:
+			return -1
+		case a > b:
+			return 1
+		}
+		return 0
+	case string:
+		return strings.Compare(a, b.(string))
+	case []interface{}:
+		b := b.([]interface{})
+		for i := 0; i < len(a) && i < len(b); i++ {
+			if c := compare(a[i], b[i]); c != 0 {
+				return c
+			}
+		}
+		return len(a) - len(b)
+	case map[string]interface{}:
+		b := b.(map[string]interface{})
+		ka, kb := sortedKeys(a), sortedKeys(b)
+		keysA := make([]interface{}, len(ka))
+		keysB := make([]interface{}, len(kb))
+		for i, k := range ka {
+			keysA[i] = k
+		}
+		for i, k := range kb {
+			keysB[i] = k
+		}
+		if c := compare(keysA, keysB); c != 0 {
+			return c
+		}
+		for _, k := range ka {
+			if c := compare(a[k], b[k]); c != 0 {
+				return c
+			}
+		}
+	}
+	return 0
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func typeName(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+// describe names a value in an error message
+func describe(v interface{}) string {
+	switch v.(type) {
+	case string, float64, bool, nil:
+		b, _ := json.Marshal(v)
+		return fmt.Sprintf("%s %s", typeName(v), b)
+	}
+	return typeName(v)
+}
@@ -0,0 +1,304 @@
+package jq
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const doc = `{
+	"success": true,
+	"data": [
+		{"gid": "1", "name": "Fix login", "completed": false, "assignee": {"name": "Ada"}, "tags": [{"name": "bug"}], "points": 3},
+		{"gid": "2", "name": "Write docs", "completed": true, "assignee": null, "tags": [], "points": 1},
+		{"gid": "3", "name": "Ship it", "completed": false, "assignee": {"name": "Grace"}, "tags": [{"name": "bug"}, {"name": "urgent"}], "points": 5}
+	],
+	"meta": {"count": 3, "schema_version": 1}
+}`
+
+func TestRun(t *testing.T) {
+	var input interface{}
+	if err := json.Unmarshal([]byte(doc), &input); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range []struct {
+		query string
+		want  string // the results as JSON, one per line
+	}{
+		{`.meta.count`, `3`},
+		{`.data[].gid`, "\"1\"\n\"2\"\n\"3\""},
+		{`.data[] | select(.completed | not) | .gid`, "\"1\"\n\"3\""},
+		{`.data[] | select(.points >= 3 and .assignee.name == "Ada") | .name`, `"Fix login"`},
+		{`.data[] | .assignee.name // "-"`, "\"Ada\"\n\"-\"\n\"Grace\""},
+		{`[.data[] | .gid] | join(",")`, `"1,2,3"`},
+		{`.data | map(.points) | add`, `9`},
+		{`.data | length`, `3`},
+		{`.data[-1].name`, `"Ship it"`},
+		{`.data[1:].[0].gid`, `"2"`},
+		{`.data[:2] | map(.gid)`, `["1","2"]`},
+		{`.data | first | {gid, who: .assignee.name}`, `{"gid":"1","who":"Ada"}`},
+		{`.data[] | select(.tags | map(.name) | contains(["urgent"])) | .gid`, `"3"`},
+		{`.data | sort_by(.points) | reverse | map(.name)`, `["Ship it","Fix login","Write docs"]`},
+		{`.data | group_by(.completed) | map(length)`, `[2,1]`},
+		{`.data | max_by(.points) | .gid`, `"3"`},
+		{`.data[] | select(.name | test("^(Fix|Ship)")) | .gid`, "\"1\"\n\"3\""},
+		{`.data[] | select(.name | ascii_downcase | startswith("write")) | .gid`, `"2"`},
+		{`.meta | keys`, `["count","schema_version"]`},
+		{`.meta | has("count"), has("nope")`, "true\nfalse"},
+		{`.data[0].name | split(" ")`, `["Fix","login"]`},
+		{`.data[0] | ."name"`, `"Fix login"`},
+		{`.data | map(.tags[].name) | unique`, `["bug","urgent"]`},
+		{`[.data[].points] | min, max`, "1\n5"},
+		{`.data | map(.completed) | any, all`, "true\nfalse"},
+		{`.meta.count | tostring`, `"3"`},
+		{`"42" | tonumber`, `42`},
+		{`.missing.deeper`, `null`},
+		{`.data[0].name.x?`, ``},
+		{`.data[] | .gid, .name | select(. == "2")`, `"2"`},
+		{`[.data[] | select(.points > 10)]`, `[]`},
+		{`.success | type`, `"boolean"`},
+		{`{(.data[0].gid): .data[0].name}`, `{"1":"Fix login"}`},
+		{`.meta | to_entries | map(.key)`, `["count","schema_version"]`},
+	} {
+		q, err := Parse(c.query)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", c.query, err)
+			continue
+		}
+		results, err := q.Run(input)
+		if err != nil {
+			t.Errorf("Run(%q): %v", c.query, err)
+			continue
+		}
+		lines := make([]string, len(results))
+		for i, r := range results {
+			b, _ := json.Marshal(r)
+			lines[i] = string(b)
+		}
+		if got := strings.Join(lines, "\n"); got != c.want {
+			t.Errorf("%s =\n%s\nwant\n%s", c.query, got, c.want)
+		}
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	var input interface{}
+	json.Unmarshal([]byte(doc), &input)
+
+	for _, c := range []struct {
+		query string
+		want  string
+	}{
+		{`.data.name`, `cannot index array with string "name"`},
+		{`.meta.count[]`, `cannot iterate over number`},
+		{`.data | join(",")`, `cannot join object`},
+		{`.success | length`, `length cannot be applied to boolean`},
+	} {
+		q, err := Parse(c.query)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", c.query, err)
+		}
+		if _, err := q.Run(input); err == nil || err.Error() != c.want {
+			t.Errorf("Run(%q) error = %v, want %q", c.query, err, c.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, c := range []struct {
+		query string
+		pos   int
+		msg   string
+	}{
+		{``, 0, "empty query"},
+		{`.data[] | selct(.x)`, 10, "unknown function selct"},
+		{`.a = 1`, 3, "use == to compare"},
+		{`.data[`, 6, "unexpected end of query"},
+		{`select(.a; .b)`, 0, "select takes 1 argument(s), not 2"},
+		{`{a: 1`, 5, `expected "," or "}", found end of query`},
+		{`.a 1`, 3, `unexpected "1"`},
+		{`"unterminated`, 0, "unterminated string"},
+		{`..a`, 0, "recursive descent (..) is not supported"},
+		{`..`, 0, `recursive descent (..) is not supported`},
+		{`.a | ..`, 5, `recursive descent (..) is not supported`},
+		{`.a as $x | $x`, 6, `variables ($name) are not supported`},
+		{`$ENV`, 0, `variables ($name) are not supported`},
+		{`@csv`, 0, `formats such as @csv are not supported`},
+		{`"a\(.b)"`, 0, `string interpolation is not supported`},
+		{`"\q"`, 0, `invalid string escape`},
+		{`1e`, 0, `invalid number "1e"`},
+		{`if . then 1 else 2 end`, 0, `unknown function if`},
+		{`. + 1`, 2, `unexpected character '+'`},
+		{`map`, 0, `map takes 1 argument(s), not 0`},
+		{`length(.)`, 0, `length takes 0 argument(s), not 1`},
+		{`.a and`, 6, `unexpected end of query`},
+		{`and`, 0, `unexpected "and"`},
+		{`{(.a) 1}`, 6, `expected ":" after a computed key`},
+		{`{1: 2}`, 1, `expected an object key, found "1"`},
+		{`(.a`, 3, `expected ")", found end of query`},
+	} {
+		_, err := Parse(c.query)
+		se, ok := err.(*SyntaxError)
+		if !ok {
+			t.Errorf("Parse(%q) = %v, want a syntax error", c.query, err)
+			continue
+		}
+		if se.Pos != c.pos || se.Msg != c.msg {
+			t.Errorf("Parse(%q) error at %d %q, want %d %q", c.query, se.Pos, se.Msg, c.pos, c.msg)
+		}
+	}
+}
+
+// TestBuiltins pins the documented subset: each path form, operator and
+// built-in function, on small inputs. The expected results match jq 1.6,
+// except that decoded objects have no key order, so to_entries comes out
+// sorted by key.
+func TestBuiltins(t *testing.T) {
+	for _, c := range []struct {
+		query, input string
+		want         string // the results as JSON, one per line
+	}{
+		{`.a.b`, `{"a":{"b":1}}`, "1"},
+		{`."a b"`, `{"a b":2}`, "2"},
+		{`.[0]`, `[1,2,3]`, "1"},
+		{`.[-1]`, `[1,2,3]`, "3"},
+		{`.[-5]`, `[1,2,3]`, "null"},
+		{`.[5]`, `[1,2,3]`, "null"},
+		{`.[1:]`, `[1,2,3]`, "[2,3]"},
+		{`.[:-1]`, `[1,2,3]`, "[1,2]"},
+		{`.[0:-1]`, `[1,2,3]`, "[1,2]"},
+		{`.[-2:]`, `[1,2,3]`, "[2,3]"},
+		{`.[2:1]`, `[1,2,3]`, "[]"},
+		{`.[1:3]`, `"abcd"`, "\"bc\""},
+		{`.["a"]`, `{"a":1}`, "1"},
+		{`.[]`, `{"a":1,"b":2}`, "1\n2"},
+		{`.[]?`, `3`, ""},
+		{`.a?`, `[1]`, ""},
+		{`.a`, `null`, "null"},
+		{`.[0]`, `null`, "null"},
+		{`.a // "d"`, `{"a":null}`, "\"d\""},
+		{`.a // "d"`, `{"a":false}`, "\"d\""},
+		{`.a // "d"`, `{"a":0}`, "0"},
+		{`(.[] | select(. > 5)) // "none"`, `[1,2]`, "\"none\""},
+		{`.[] // 9`, `[null,1,false,2]`, "1\n2"},
+		{`. == 1, . != 1, . < 2, . <= 1, . > 0, . >= 2`, `1`, "true\nfalse\ntrue\ntrue\ntrue\nfalse"},
+		{`null < false, false < 0, 0 < "a", "a" < [], [] < {}`, `null`, "true\ntrue\ntrue\ntrue\ntrue"},
+		{`true and null, false or 1`, `null`, "false\ntrue"},
+		{`.[] | (. , .)`, `[1]`, "1\n1"},
+		{`[.[] | . > 1]`, `[1,2]`, "[false,true]"},
+		{`{a, "b": 2, (.k): 3}`, `{"a":1,"k":"c"}`, "{\"a\":1,\"b\":2,\"c\":3}"},
+		{`{a: (1, 2)}`, `null`, "{\"a\":1}\n{\"a\":2}"},
+		{`[]`, `null`, "[]"},
+		{`{}`, `null`, "{}"},
+		{`empty`, `1`, ""},
+		{`[.[] | not]`, `[null,false,0,""]`, "[true,true,false,false]"},
+		{`map(length)`, `[null,-3,"héllo",[1,2],{"a":1}]`, "[0,3,5,2,1]"},
+		{`keys`, `{"b":1,"a":2}`, "[\"a\",\"b\"]"},
+		{`keys`, `["x","y"]`, "[0,1]"},
+		{`[.[] | values]`, `[1,null,2]`, "[1,2]"},
+		{`first, last`, `[1,2,3]`, "1\n3"},
+		{`first`, `[]`, "null"},
+		{`map(type)`, `[null,true,1,"s",[],{}]`, "[\"null\",\"boolean\",\"number\",\"string\",\"array\",\"object\"]"},
+		{`map(tostring)`, `[1,"s",[1],null]`, "[\"1\",\"s\",\"[1]\",\"null\"]"},
+		{`map(tonumber)`, `["1.5"," 2 ",3]`, "[1.5,2,3]"},
+		{`ascii_downcase, ascii_upcase`, `"MiXed"`, "\"mixed\"\n\"MIXED\""},
+		{`sort`, `[3,"a",null,1,true]`, "[null,true,1,3,\"a\"]"},
+		{`reverse`, `[1,2,3]`, "[3,2,1]"},
+		{`unique`, `[2,1,2,1]`, "[1,2]"},
+		{`add`, `[1,2,3]`, "6"},
+		{`add`, `["a","b"]`, "\"ab\""},
+		{`add`, `[[1],[2]]`, "[1,2]"},
+		{`add`, `[{"a":1},{"b":2}]`, "{\"a\":1,\"b\":2}"},
+		{`add`, `[]`, "null"},
+		{`min, max`, `[3,1,2]`, "1\n3"},
+		{`min`, `[]`, "null"},
+		{`any, all`, `[true,false]`, "true\nfalse"},
+		{`any, all`, `[]`, "false\ntrue"},
+		{`to_entries`, `{"b":1,"a":2}`, "[{\"key\":\"a\",\"value\":2},{\"key\":\"b\",\"value\":1}]"},
+		{`map(select(. > 1))`, `[1,2,3]`, "[2,3]"},
+		{`map(.a)`, `[{"a":1},{"a":2}]`, "[1,2]"},
+		{`map(.[])`, `[[1,2],[3]]`, "[1,2,3]"},
+		{`has("a"), has("z")`, `{"a":null}`, "true\nfalse"},
+		{`has(0), has(2)`, `[1,2]`, "true\nfalse"},
+		{`test("^a.c$")`, `"abc"`, "true"},
+		{`startswith("ab"), endswith("bc")`, `"abc"`, "true\ntrue"},
+		{`contains("bc")`, `"abcd"`, "true"},
+		{`contains({a: [1]})`, `{"a":[1,2],"b":3}`, "true"},
+		{`contains([4])`, `[1,2]`, "false"},
+		{`join("-")`, `["a",1,null,true]`, "\"a-1--true\""},
+		{`split(", ")`, `"a, b, c"`, "[\"a\",\"b\",\"c\"]"},
+		{`sort_by(.n) | map(.k)`, `[{"n":2,"k":"b"},{"n":1,"k":"a"},{"n":2,"k":"c"}]`, "[\"a\",\"b\",\"c\"]"},
+		{`sort_by(.n, .k) | map(.k)`, `[{"n":1,"k":"b"},{"n":1,"k":"a"}]`, "[\"a\",\"b\"]"},
+		{`group_by(.n) | map(map(.k))`, `[{"n":2,"k":"b"},{"n":1,"k":"a"},{"n":2,"k":"c"}]`, "[[\"a\"],[\"b\",\"c\"]]"},
+		{`group_by(.n)`, `[]`, "[]"},
+		{`unique_by(length)`, `["a","bb","c"]`, "[\"a\",\"bb\"]"},
+		{`min_by(.n).k, max_by(.n).k`, `[{"n":2,"k":"b"},{"n":1,"k":"a"}]`, "\"a\"\n\"b\""},
+		{`max_by(.n)`, `[]`, "null"},
+	} {
+		if got := runQuery(t, c.query, c.input); got != c.want {
+			t.Errorf("%s on %s =\n%s\nwant\n%s", c.query, c.input, got, c.want)
+		}
+	}
+}
+
+func TestBuiltinErrors(t *testing.T) {
+	for _, c := range []struct {
+		query, input string
+		want         string
+	}{
+		{`to_entries`, `[1]`, `to_entries cannot be applied to array`},
+		{`to_entries`, `"s"`, `to_entries cannot be applied to string`},
+		{`keys`, `1`, `keys cannot be applied to number`},
+		{`first`, `{}`, `first cannot be applied to object`},
+		{`tonumber`, `"x"`, `cannot parse "x" as a number`},
+		{`tonumber`, `true`, `tonumber cannot be applied to boolean`},
+		{`ascii_upcase`, `1`, `ascii_upcase cannot be applied to number`},
+		{`sort`, `{}`, `sort cannot be applied to object`},
+		{`sort_by(.a)`, `{}`, `sort_by cannot be applied to object`},
+		{`map(.)`, `1`, `cannot iterate over number`},
+		{`has("a")`, `[1]`, `cannot check whether array has string "a"`},
+		{`test("(")`, `"a"`, "invalid regular expression \"(\": error parsing regexp: missing closing ): `(`"},
+		{`startswith(1)`, `"a"`, `startswith needs strings, not string and number`},
+		{`join(1)`, `[]`, `join needs an array and a string separator`},
+		{`.[0]`, `{}`, `cannot index object with number 0`},
+	} {
+		q, err := Parse(c.query)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", c.query, err)
+		}
+		var input interface{}
+		if err := json.Unmarshal([]byte(c.input), &input); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := q.Run(input); err == nil || err.Error() != c.want {
+			t.Errorf("%s on %s: error = %v, want %q", c.query, c.input, err, c.want)
+		}
+	}
+}
+
+// runQuery runs query on the JSON input and returns the results as JSON,
+// one per line
+func runQuery(t *testing.T, query, input string) string {
+	t.Helper()
+	q, err := Parse(query)
+	if err != nil {
+		t.Errorf("Parse(%q): %v", query, err)
+		return ""
+	}
+	var v interface{}
+	if err := json.Unmarshal([]byte(input), &v); err != nil {
+		t.Fatal(err)
+	}
+	results, err := q.Run(v)
+	if err != nil {
+		t.Errorf("Run(%q): %v", query, err)
+		return ""
+	}
+	lines := make([]string, len(results))
+	for i, r := range results {
+		b, _ := json.Marshal(r)
+		lines[i] = string(b)
+	}
+	return strings.Join(lines, "\n")
+}
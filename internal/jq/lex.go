@@ -0,0 +1,149 @@
+package jq
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type tokenType int
+
+const (
+	tokEOF   tokenType = iota
+	tokDot             // a lone "."
+	tokField           // ".name"
+	tokIdent           // a function name, keyword or literal such as true
+	tokString
+	tokNumber
+	tokPunct // | , ( ) [ ] { } : ; ? and the comparison operators
+)
+
+type token struct {
+	typ  tokenType
+	text string // the field name, unquoted string, or the token as written
+	pos  int
+	len  int
+}
+
+func (t token) String() string {
+	switch t.typ {
+	case tokEOF:
+		return "end of query"
+	case tokField:
+		return fmt.Sprintf("%q", "."+t.text)
+	case tokString:
+		return strconv.Quote(t.text)
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+func (t token) is(punct string) bool {
+	return t.typ == tokPunct && t.text == punct
+}
+
+func (t token) isIdent(name string) bool {
+	return t.typ == tokIdent && t.text == name
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentChar(c byte) bool {
+	return isIdentStart(c) || (c >= '0' && c <= '9')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// lex splits a query into tokens
+func lex(src string) ([]token, error) {
+	var toks []token
+	i := 0
+	for i < len(src) {
+		c := src[i]
+		start := i
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+			continue
+		case c == '.':
+			i++
+			if i < len(src) && src[i] == '.' {
+				return nil, &SyntaxError{Query: src, Pos: start, Len: 2, Msg: "recursive descent (..) is not supported"}
+			}
+			if i < len(src) && isIdentStart(src[i]) {
+				for i < len(src) && isIdentChar(src[i]) {
+					i++
+				}
+				toks = append(toks, token{tokField, src[start+1 : i], start, i - start})
+				continue
+			}
+			toks = append(toks, token{tokDot, ".", start, 1})
+			continue
+		case isIdentStart(c):
+			for i < len(src) && isIdentChar(src[i]) {
+				i++
+			}
+			toks = append(toks, token{tokIdent, src[start:i], start, i - start})
+			continue
+		case isDigit(c) || (c == '-' && i+1 < len(src) && isDigit(src[i+1])):
+			i++
+			for i < len(src) && (isDigit(src[i]) || src[i] == '.' || src[i] == 'e' || src[i] == 'E' ||
+				((src[i] == '+' || src[i] == '-') && (src[i-1] == 'e' || src[i-1] == 'E'))) {
+				i++
+			}
+			if _, err := strconv.ParseFloat(src[start:i], 64); err != nil {
+				return nil, &SyntaxError{Query: src, Pos: start, Len: i - start, Msg: fmt.Sprintf("invalid number %q", src[start:i])}
+			}
+			toks = append(toks, token{tokNumber, src[start:i], start, i - start})
+			continue
+		case c == '"':
+			i++
+			for i < len(src) && src[i] != '"' {
+				if src[i] == '\\' {
+					i++
+				}
+				i++
+			}
+			if i >= len(src) {
+				return nil, &SyntaxError{Query: src, Pos: start, Len: len(src) - start, Msg: "unterminated string"}
+			}
+			i++
+			if strings.Contains(src[start:i], `\(`) {
+				return nil, &SyntaxError{Query: src, Pos: start, Len: i - start, Msg: "string interpolation is not supported"}
+			}
+			s, err := strconv.Unquote(src[start:i])
+			if err != nil {
+				return nil, &SyntaxError{Query: src, Pos: start, Len: i - start, Msg: "invalid string escape"}
+			}
+			toks = append(toks, token{tokString, s, start, i - start})
+			continue
+		}
+
+		if i+1 < len(src) {
+			switch two := src[i : i+2]; two {
+			case "==", "!=", "<=", ">=", "//":
+				toks = append(toks, token{tokPunct, two, start, 2})
+				i += 2
+				continue
+			}
+		}
+		if strings.IndexByte("|,()[]{}:;?<>", c) < 0 {
+			msg := fmt.Sprintf("unexpected character %q", c)
+			switch c {
+			case '=':
+				msg = "use == to compare"
+			case '$':
+				msg = "variables ($name) are not supported"
+			case '@':
+				msg = "formats such as @csv are not supported"
+			}
+			return nil, &SyntaxError{Query: src, Pos: start, Len: 1, Msg: msg}
+		}
+		toks = append(toks, token{tokPunct, string(c), start, 1})
+		i++
+	}
+	return append(toks, token{tokEOF, "", len(src), 1}), nil
+}
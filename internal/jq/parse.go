@@ -0,0 +1,428 @@
+// Package jq implements the subset of the jq language accepted by --query,
+// so scripts can pick values out of the --json envelope without jq
+// installed.
+//
+// Grammar, loosest binding first:
+//
+//	pipe    = comma { "|" comma }
+//	comma   = alt { "," alt }
+//	alt     = or { "//" or }
+//	or      = and { "or" and }
+//	and     = compare { "and" compare }
+//	compare = postfix [ ("==" | "!=" | "<" | "<=" | ">" | ">=") postfix ]
+//	postfix = term { "." name | "." string | ["."] "[" "]" | ["."] "[" pipe "]"
+//	                | ["."] "[" [pipe] ":" [pipe] "]" | "?" }
+//	term    = "." | "." name | "." string | number | string
+//	        | "true" | "false" | "null" | "(" pipe ")"
+//	        | "[" [pipe] "]" | "{" [entry { "," entry }] "}"
+//	        | function [ "(" pipe { ";" pipe } ")" ]
+//	entry   = name | string | (name | string | "(" pipe ")") ":" alt
+//
+// The functions are listed in Functions.
+package jq
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// builtins maps each function to the number of arguments it takes
+var builtins = map[string]int{
+	"empty": 0, "not": 0, "length": 0, "keys": 0, "first": 0, "last": 0,
+	"type": 0, "tostring": 0, "tonumber": 0, "ascii_downcase": 0, "ascii_upcase": 0,
+	"sort": 0, "reverse": 0, "unique": 0, "add": 0, "min": 0, "max": 0,
+	"any": 0, "all": 0, "to_entries": 0, "values": 0,
+	"select": 1, "map": 1, "has": 1, "test": 1, "startswith": 1, "endswith": 1,
+	"contains": 1, "join": 1, "split": 1, "sort_by": 1, "group_by": 1, "unique_by": 1,
+	"min_by": 1, "max_by": 1,
+}
+
+// Functions lists the built-in functions, with their arity, for help text
+func Functions() []string {
+	names := make([]string, 0, len(builtins))
+	for name, arity := range builtins {
+		if arity > 0 {
+			name = fmt.Sprintf("%s(%s)", name, strings.TrimSuffix(strings.Repeat("f; ", arity), "; "))
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Query is a compiled query
+type Query struct {
+	src  string
+	root node
+}
+
+// String returns the query as written
+func (q *Query) String() string {
+	return q.src
+}
+
+// SyntaxError reports where a query failed to parse
+type SyntaxError struct {
+	Query string
+	Pos   int // byte offset of the offending token
+	Len   int // length of the offending token, at least 1
+	Msg   string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("invalid --query at column %d: %s", e.Pos+1, e.Msg)
+}
+
+// Pointer shows the query with the offending token underlined
+func (e *SyntaxError) Pointer() string {
+	return "  " + e.Query + "\n  " + strings.Repeat(" ", e.Pos) + strings.Repeat("^", e.Len)
+}
+
+// Parse compiles a query
+func Parse(src string) (*Query, error) {
+	toks, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{src: src, toks: toks}
+	if p.peek().typ == tokEOF {
+		return nil, p.errorf(p.peek(), "empty query")
+	}
+	root, err := p.parsePipe()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.typ != tokEOF {
+		return nil, p.errorf(t, "unexpected %s", t)
+	}
+	return &Query{src: src, root: root}, nil
+}
+
+type parser struct {
+	src  string
+	toks []token
+	pos  int
+}
+
+func (p *parser) peek() token {
+	return p.toks[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.toks[p.pos]
+	if t.typ != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) errorf(t token, format string, args ...interface{}) error {
+	return &SyntaxError{Query: p.src, Pos: t.pos, Len: t.len, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) expect(punct string) error {
+	if t := p.next(); !t.is(punct) {
+		return p.errorf(t, "expected %q, found %s", punct, t)
+	}
+	return nil
+}
+
+func (p *parser) parsePipe() (node, error) {
+	left, err := p.parseComma()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().is("|") {
+		p.next()
+		right, err := p.parseComma()
+		if err != nil {
+			return nil, err
+		}
+		left = pipe{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseComma() (node, error) {
+	left, err := p.parseAlt()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().is(",") {
+		p.next()
+		right, err := p.parseAlt()
+		if err != nil {
+			return nil, err
+		}
+		left = comma{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAlt() (node, error) {
+	left, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().is("//") {
+		p.next()
+		right, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		left = alternative{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().isIdent("or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = logical{and: false, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseCompare()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().isIdent("and") {
+		p.next()
+		right, err := p.parseCompare()
+		if err != nil {
+			return nil, err
+		}
+		left = logical{and: true, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseCompare() (node, error) {
+	left, err := p.parsePostfix()
+	if err != nil {
+		return nil, err
+	}
+	switch t := p.peek(); {
+	case t.is("=="), t.is("!="), t.is("<"), t.is("<="), t.is(">"), t.is(">="):
+		p.next()
+		right, err := p.parsePostfix()
+		if err != nil {
+			return nil, err
+		}
+		return comparison{op: t.text, left: left, right: right}, nil
+	}
+	return left, nil
+}
+
+func (p *parser) parsePostfix() (node, error) {
+	n, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		switch {
+		case t.typ == tokField:
+			p.next()
+			n = index{target: n, key: literal{t.text}}
+		case t.typ == tokDot && p.toks[p.pos+1].typ == tokString:
+			p.next()
+			n = index{target: n, key: literal{p.next().text}}
+		case t.typ == tokDot && p.toks[p.pos+1].is("["):
+			// .a.[0] is the same as .a[0]
+			p.next()
+		case t.is("["):
+			if n, err = p.parseBracket(n); err != nil {
+				return nil, err
+			}
+		case t.is("?"):
+			p.next()
+			n = try{n}
+		default:
+			return n, nil
+		}
+	}
+}
+
+// parseBracket parses [], [i], ["key"] and [from:to] after target
+func (p *parser) parseBracket(target node) (node, error) {
+	p.next()
+	if p.peek().is("]") {
+		p.next()
+		return iterate{target}, nil
+	}
+
+	var from, to node
+	var err error
+	if !p.peek().is(":") {
+		if from, err = p.parsePipe(); err != nil {
+			return nil, err
+		}
+		if p.peek().is("]") {
+			p.next()
+			return index{target: target, key: from}, nil
+		}
+	}
+	if err := p.expect(":"); err != nil {
+		return nil, err
+	}
+	if !p.peek().is("]") {
+		if to, err = p.parsePipe(); err != nil {
+			return nil, err
+		}
+	}
+	if err := p.expect("]"); err != nil {
+		return nil, err
+	}
+	return slice{target: target, from: from, to: to}, nil
+}
+
+func (p *parser) parseTerm() (node, error) {
+	t := p.next()
+	switch t.typ {
+	case tokDot:
+		if p.peek().typ == tokString {
+			return index{target: identity{}, key: literal{p.next().text}}, nil
+		}
+		return identity{}, nil
+	case tokField:
+		return index{target: identity{}, key: literal{t.text}}, nil
+	case tokString:
+		return literal{t.text}, nil
+	case tokNumber:
+		f, _ := strconv.ParseFloat(t.text, 64)
+		return literal{f}, nil
+	case tokIdent:
+		switch t.text {
+		case "true":
+			return literal{true}, nil
+		case "false":
+			return literal{false}, nil
+		case "null":
+			return literal{nil}, nil
+		case "and", "or":
+			return nil, p.errorf(t, "unexpected %s", t)
+		}
+		return p.parseCall(t)
+	case tokPunct:
+		switch t.text {
+		case "(":
+			n, err := p.parsePipe()
+			if err != nil {
+				return nil, err
+			}
+			return n, p.expect(")")
+		case "[":
+			if p.peek().is("]") {
+				p.next()
+				return collect{}, nil
+			}
+			n, err := p.parsePipe()
+			if err != nil {
+				return nil, err
+			}
+			return collect{n}, p.expect("]")
+		case "{":
+			return p.parseObject()
+		}
+	}
+	if t.typ == tokEOF {
+		return nil, p.errorf(t, "unexpected end of query")
+	}
+	return nil, p.errorf(t, "unexpected %s", t)
+}
+
+func (p *parser) parseCall(name token) (node, error) {
+	var args []node
+	if p.peek().is("(") {
+		p.next()
+		for {
+			arg, err := p.parsePipe()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			if !p.peek().is(";") {
+				break
+			}
+			p.next()
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+	}
+
+	arity, ok := builtins[name.text]
+	if !ok {
+		return nil, p.errorf(name, "unknown function %s", name.text)
+	}
+	if len(args) != arity {
+		return nil, p.errorf(name, "%s takes %d argument(s), not %d", name.text, arity, len(args))
+	}
+	return call{name: name.text, args: args}, nil
+}
+
+func (p *parser) parseObject() (node, error) {
+	var obj object
+	if p.peek().is("}") {
+		p.next()
+		return obj, nil
+	}
+	for {
+		var e entry
+		t := p.next()
+		switch {
+		case t.typ == tokIdent || t.typ == tokString:
+			e.key = literal{t.text}
+		case t.typ == tokField:
+			// {.name} is short for {name: .name}
+			e.key = literal{t.text}
+			e.value = index{target: identity{}, key: literal{t.text}}
+		case t.is("("):
+			key, err := p.parsePipe()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			e.key = key
+		default:
+			return nil, p.errorf(t, "expected an object key, found %s", t)
+		}
+
+		if e.value == nil {
+			if p.peek().is(":") {
+				p.next()
+				value, err := p.parseAlt()
+				if err != nil {
+					return nil, err
+				}
+				e.value = value
+			} else if lit, ok := e.key.(literal); ok {
+				e.value = index{target: identity{}, key: lit}
+			} else {
+				return nil, p.errorf(p.peek(), "expected \":\" after a computed key")
+			}
+		}
+		obj.entries = append(obj.entries, e)
+
+		if t := p.next(); t.is("}") {
+			return obj, nil
+		} else if !t.is(",") {
+			return nil, p.errorf(t, "expected \",\" or \"}\", found %s", t)
+		}
+	}
+}
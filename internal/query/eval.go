@@ -0,0 +1,403 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TheCoolRobot/asana-cli/internal/asana"
+)
+
+// Env holds what an expression is evaluated against besides the task
+type Env struct {
+	// Me is the GID of the current user, matched by "assignee = me"
+	Me string
+	// Now is the time relative dates such as today and +7d count from
+	Now time.Time
+}
+
+// Match reports whether the task satisfies the expression
+func (q *Query) Match(t *asana.Task, env Env) bool {
+	if env.Now.IsZero() {
+		env.Now = time.Now()
+	}
+	return q.root.eval(t, &env)
+}
+
+// Filter returns the tasks that satisfy the expression, reusing the
+// slice's backing array
+func (q *Query) Filter(tasks []asana.Task, env Env) []asana.Task {
+	kept := tasks[:0]
+	for i := range tasks {
+		if q.Match(&tasks[i], env) {
+			kept = append(kept, tasks[i])
+		}
+	}
+	return kept
+}
+
+type node interface {
+	eval(t *asana.Task, env *Env) bool
+}
+
+type andNode struct{ left, right node }
+type orNode struct{ left, right node }
+type notNode struct{ inner node }
+
+func (n *andNode) eval(t *asana.Task, env *Env) bool {
+	return n.left.eval(t, env) && n.right.eval(t, env)
+}
+
+func (n *orNode) eval(t *asana.Task, env *Env) bool {
+	return n.left.eval(t, env) || n.right.eval(t, env)
+}
+
+func (n *notNode) eval(t *asana.Task, env *Env) bool {
+	return !n.inner.eval(t, env)
+}
+
+// walk calls fn for every test in the expression
+func walk(n node, fn func(*test)) {
+	switch n := n.(type) {
+	case *andNode:
+		walk(n.left, fn)
+		walk(n.right, fn)
+	case *orNode:
+		walk(n.left, fn)
+		walk(n.right, fn)
+	case *notNode:
+		walk(n.inner, fn)
+	case *test:
+		fn(n)
+	}
+}
+
+// test compares one field with one or more values. With no operator it
+// checks that the field is set.
+type test struct {
+	field  string
+	custom string // custom field name, for field "cf"
+	kind   kind
+	op     string
+	values []value
+	negate bool // "not in"
+}
+
+func (t *test) optFields() []string {
+	if t.kind == kindCustom {
+		var out []string
+		for _, f := range asana.DefaultTaskFields {
+			if strings.HasPrefix(f, "custom_fields.") {
+				out = append(out, f)
+			}
+		}
+		return out
+	}
+	return fields[t.field].optFields
+}
+
+// check validates a value against the field's type when the expression is
+// parsed, so mistakes are reported before any task is fetched
+func (t *test) check(v *value) error {
+	if v.isNone() {
+		if t.kind == kindBool || (t.op != "=" && t.op != "!=" && t.op != "in") {
+			return fmt.Errorf("none can only be used with = and != on fields that can be unset")
+		}
+		return nil
+	}
+	switch t.kind {
+	case kindBool:
+		b, err := parseBool(v.text)
+		if err != nil {
+			return err
+		}
+		if b {
+			v.number = 1
+		}
+	case kindDate:
+		d, err := parseDateValue(v.text)
+		if err != nil {
+			return err
+		}
+		v.date = d
+	case kindNumber:
+		n, err := strconv.ParseFloat(v.text, 64)
+		if err != nil {
+			return fmt.Errorf("%s is a number field, %q is not a number", t.field, v.text)
+		}
+		v.number = n
+	case kindCustom:
+		// The field's type is only known per task; remember whichever
+		// readings of the value make sense
+		if n, err := strconv.ParseFloat(v.text, 64); err == nil {
+			v.number = n
+		}
+		if d, err := parseDateValue(v.text); err == nil {
+			v.date = d
+		}
+		if (t.op == "<" || t.op == "<=" || t.op == ">" || t.op == ">=") && v.date == nil {
+			if _, err := strconv.ParseFloat(v.text, 64); err != nil {
+				return fmt.Errorf("%s needs a number or a date, found %q", t.op, v.text)
+			}
+		}
+	}
+	return nil
+}
+
+func parseBool(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "true", "yes":
+		return true, nil
+	case "false", "no":
+		return false, nil
+	}
+	return false, fmt.Errorf("expected true or false, found %q", s)
+}
+
+func (t *test) eval(task *asana.Task, env *Env) bool {
+	if t.op == "" {
+		return t.isSet(task, env)
+	}
+	switch t.op {
+	case "!=":
+		return !t.equals(task, env, t.values[0])
+	case "!~":
+		return !t.contains(task, t.values[0])
+	case "=":
+		return t.equals(task, env, t.values[0])
+	case "~":
+		return t.contains(task, t.values[0])
+	case "in":
+		for _, v := range t.values {
+			if t.equals(task, env, v) {
+				return !t.negate
+			}
+		}
+		return t.negate
+	}
+	return t.order(task, env, t.values[0])
+}
+
+// isSet is the meaning of a field on its own
+func (t *test) isSet(task *asana.Task, env *Env) bool {
+	switch t.kind {
+	case kindBool:
+		return t.boolean(task, env)
+	case kindNumber:
+		return t.num(task) != 0
+	case kindCustom:
+		f := task.CustomField(t.custom)
+		return f != nil && f.IsSet()
+	case kindDate:
+		return t.date(task) != ""
+	}
+	return len(t.strings(task)) > 0
+}
+
+func (t *test) equals(task *asana.Task, env *Env, v value) bool {
+	if v.isNone() {
+		return !t.isSet(task, env)
+	}
+	switch t.kind {
+	case kindBool:
+		return t.boolean(task, env) == (v.number == 1)
+	case kindNumber:
+		return t.num(task) == v.number
+	case kindDate:
+		return t.date(task) == v.date.resolve(env.Now)
+	case kindCustom:
+		f := task.CustomField(t.custom)
+		if f == nil {
+			return false
+		}
+		if f.DateValue != nil && v.date != nil {
+			return customDate(f) == v.date.resolve(env.Now)
+		}
+		return f.Matches(v.text)
+	case kindUser:
+		if t.field == "assignee" && !v.quoted && strings.EqualFold(v.text, "me") {
+			return task.Assignee != nil && task.Assignee.GID == env.Me
+		}
+	}
+	for _, s := range t.strings(task) {
+		if strings.EqualFold(s, v.text) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *test) contains(task *asana.Task, v value) bool {
+	want := strings.ToLower(v.text)
+	if t.kind == kindCustom {
+		f := task.CustomField(t.custom)
+		return f != nil && strings.Contains(strings.ToLower(f.String()), want)
+	}
+	for _, s := range t.strings(task) {
+		if strings.Contains(strings.ToLower(s), want) {
+			return true
+		}
+	}
+	return false
+}
+
+// order handles <, <=, > and >=. Unset values are neither before nor
+// after anything.
+func (t *test) order(task *asana.Task, env *Env, v value) bool {
+	var cmp int
+	switch t.kind {
+	case kindNumber:
+		cmp = compareFloats(t.num(task), v.number)
+	case kindDate:
+		have := t.date(task)
+		if have == "" {
+			return false
+		}
+		cmp = strings.Compare(have, v.date.resolve(env.Now))
+	case kindCustom:
+		f := task.CustomField(t.custom)
+		switch {
+		case f == nil:
+			return false
+		case f.NumberValue != nil:
+			if _, err := strconv.ParseFloat(v.text, 64); err != nil {
+				return false
+			}
+			cmp = compareFloats(*f.NumberValue, v.number)
+		case f.DateValue != nil && v.date != nil:
+			cmp = strings.Compare(customDate(f), v.date.resolve(env.Now))
+		default:
+			return false
+		}
+	}
+	switch t.op {
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	}
+	return cmp >= 0
+}
+
+func compareFloats(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func customDate(f *asana.CustomFieldValue) string {
+	if f.DateValue.Date != "" {
+		return f.DateValue.Date
+	}
+	if len(f.DateValue.DateTime) >= len(DateLayout) {
+		return f.DateValue.DateTime[:len(DateLayout)]
+	}
+	return ""
+}
+
+func (t *test) boolean(task *asana.Task, env *Env) bool {
+	switch t.field {
+	case "completed":
+		return task.Completed
+	case "subtask":
+		return task.Parent != nil
+	case "overdue":
+		due := dueDate(task)
+		return !task.Completed && due != "" && due < env.Now.Format(DateLayout)
+	}
+	return false
+}
+
+func (t *test) num(task *asana.Task) float64 {
+	return float64(task.NumSubtasks)
+}
+
+func (t *test) date(task *asana.Task) string {
+	switch t.field {
+	case "due":
+		return dueDate(task)
+	case "created":
+		return formatTime(task.CreatedAt)
+	case "modified":
+		return formatTime(task.ModifiedAt)
+	}
+	return ""
+}
+
+func dueDate(task *asana.Task) string {
+	if task.DueDate != nil && !task.DueDate.IsZero() {
+		return task.DueDate.Format(DateLayout)
+	}
+	if task.DueAt != nil && !task.DueAt.IsZero() {
+		return task.DueAt.Local().Format(DateLayout)
+	}
+	return ""
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Local().Format(DateLayout)
+}
+
+// strings returns the names (and GIDs) a text, user or set field is
+// matched against
+func (t *test) strings(task *asana.Task) []string {
+	switch t.field {
+	case "gid":
+		return nonEmpty(task.GID)
+	case "name":
+		return nonEmpty(task.Name)
+	case "notes":
+		return nonEmpty(task.Description)
+	case "assignee":
+		if task.Assignee == nil {
+			return nil
+		}
+		return nonEmpty(task.Assignee.GID, task.Assignee.Name, task.Assignee.Email)
+	case "parent":
+		if task.Parent == nil {
+			return nil
+		}
+		return nonEmpty(task.Parent.GID, task.Parent.Name)
+	case "tag":
+		var out []string
+		for _, tag := range task.Tags {
+			out = append(out, nonEmpty(tag.GID, tag.Name)...)
+		}
+		return out
+	case "project":
+		var out []string
+		for _, p := range task.Projects {
+			out = append(out, nonEmpty(p.GID, p.Name)...)
+		}
+		return out
+	case "section":
+		var out []string
+		for _, m := range task.Memberships {
+			if m.Section != nil {
+				out = append(out, nonEmpty(m.Section.GID, m.Section.Name)...)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+func nonEmpty(values ...string) []string {
+	out := values[:0]
+	for _, v := range values {
+		if v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,102 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenType int
+
+const (
+	tokEOF tokenType = iota
+	tokWord
+	tokString
+	tokOp
+	tokLParen
+	tokRParen
+	tokComma
+)
+
+type token struct {
+	typ  tokenType
+	text string
+	pos  int
+}
+
+func (t token) String() string {
+	if t.typ == tokEOF {
+		return "end of expression"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+func (t token) isKeyword(kw string) bool {
+	return t.typ == tokWord && strings.EqualFold(t.text, kw)
+}
+
+var keywords = []string{"and", "or", "not", "in"}
+
+func isKeyword(s string) bool {
+	for _, kw := range keywords {
+		if strings.EqualFold(s, kw) {
+			return true
+		}
+	}
+	return false
+}
+
+// lex splits an expression into tokens. Words run until whitespace, a
+// parenthesis, a comma, a quote or an operator character, so dates,
+// e-mail addresses and relative dates such as +7d are single words.
+func lex(src string) ([]token, error) {
+	var toks []token
+	i := 0
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case unicode.IsSpace(rune(c)):
+			i++
+		case c == '(':
+			toks = append(toks, token{tokLParen, "(", i})
+			i++
+		case c == ')':
+			toks = append(toks, token{tokRParen, ")", i})
+			i++
+		case c == ',':
+			toks = append(toks, token{tokComma, ",", i})
+			i++
+		case c == '"' || c == '\'':
+			end := strings.IndexByte(src[i+1:], c)
+			if end < 0 {
+				return nil, &SyntaxError{Query: src, Pos: i, Len: len(src) - i, Msg: "unterminated string"}
+			}
+			toks = append(toks, token{tokString, src[i+1 : i+1+end], i})
+			i += end + 2
+		case strings.IndexByte("=!<>~", c) >= 0:
+			op := string(c)
+			if i+1 < len(src) {
+				switch two := src[i : i+2]; two {
+				case "!=", "<=", ">=", "!~", "==":
+					op = two
+				}
+			}
+			if op == "!" {
+				return nil, &SyntaxError{Query: src, Pos: i, Len: 1, Msg: `unexpected "!", use not or !=`}
+			}
+			text := op
+			if op == "==" {
+				text = "="
+			}
+			toks = append(toks, token{tokOp, text, i})
+			i += len(op)
+		default:
+			start := i
+			for i < len(src) && !unicode.IsSpace(rune(src[i])) && strings.IndexByte("()\"',=!<>~", src[i]) < 0 {
+				i++
+			}
+			toks = append(toks, token{tokWord, src[start:i], start})
+		}
+	}
+	return append(toks, token{tokEOF, "", len(src)}), nil
+}
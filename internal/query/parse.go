@@ -0,0 +1,433 @@
+// Package query implements the --where expression language, a small filter
+// language evaluated against tasks on the client side:
+//
+//	due < today and assignee = me and not completed and tag in (backend, infra)
+//
+// Grammar, with keywords matched case-insensitively:
+//
+//	expr    = and { "or" and }
+//	and     = unary { "and" unary }
+//	unary   = "not" unary | "(" expr ")" | test
+//	test    = field [ op value | [ "not" ] "in" "(" value { "," value } ")" ]
+//	op      = "=" | "!=" | "<" | "<=" | ">" | ">=" | "~" | "!~"
+//	field   = name | "cf." name | "cf." string
+//	value   = word | string
+//
+// A field on its own is true when it is set (or, for yes/no fields, true),
+// so "not assignee" matches unassigned tasks. ~ is a case-insensitive
+// substring match. The bare word none stands for an unset value, as in
+// "due = none". Dates are YYYY-MM-DD, today, tomorrow, yesterday or a
+// number of days from today such as +7d or -30d.
+package query
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// kind is the type of a task field, which decides the operators it accepts
+type kind int
+
+const (
+	kindText kind = iota
+	kindBool
+	kindDate
+	kindNumber
+	kindUser
+	kindSet
+	kindCustom
+)
+
+var kindOps = map[kind][]string{
+	kindText:   {"=", "!=", "~", "!~", "in"},
+	kindBool:   {"=", "!="},
+	kindDate:   {"=", "!=", "<", "<=", ">", ">="},
+	kindNumber: {"=", "!=", "<", "<=", ">", ">="},
+	kindUser:   {"=", "!=", "~", "!~", "in"},
+	kindSet:    {"=", "!=", "~", "!~", "in"},
+	kindCustom: {"=", "!=", "<", "<=", ">", ">=", "~", "!~", "in"},
+}
+
+// field describes a task attribute the language can test, and the
+// opt_fields it needs to be fetched
+type field struct {
+	kind      kind
+	optFields []string
+}
+
+var fields = map[string]field{
+	"gid":       {kindText, nil},
+	"name":      {kindText, []string{"name"}},
+	"notes":     {kindText, []string{"notes"}},
+	"completed": {kindBool, []string{"completed"}},
+	"overdue":   {kindBool, []string{"completed", "due_on", "due_at"}},
+	"subtask":   {kindBool, []string{"parent.name"}},
+	"assignee":  {kindUser, []string{"assignee.name", "assignee.email"}},
+	"parent":    {kindUser, []string{"parent.name"}},
+	"due":       {kindDate, []string{"due_on", "due_at"}},
+	"created":   {kindDate, []string{"created_at"}},
+	"modified":  {kindDate, []string{"modified_at"}},
+	"subtasks":  {kindNumber, []string{"num_subtasks"}},
+	"tag":       {kindSet, []string{"tags.name"}},
+	"project":   {kindSet, []string{"projects.name"}},
+	"section":   {kindSet, []string{"memberships.section.name"}},
+}
+
+// Alternative spellings of field names
+var aliases = map[string]string{
+	"due_on":      "due",
+	"created_at":  "created",
+	"modified_at": "modified",
+	"description": "notes",
+	"tags":        "tag",
+	"projects":    "project",
+	"sections":    "section",
+	"done":        "completed",
+}
+
+// Fields lists the names of the built-in fields
+func Fields() []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Query is a parsed --where expression
+type Query struct {
+	src  string
+	root node
+}
+
+// String returns the expression as written
+func (q *Query) String() string {
+	return q.src
+}
+
+// SyntaxError reports an invalid expression and where in it the problem is
+type SyntaxError struct {
+	Query string
+	Pos   int // byte offset of the offending token
+	Len   int // length of the offending token, at least 1
+	Msg   string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("invalid --where expression at column %d: %s", e.Pos+1, e.Msg)
+}
+
+// Pointer shows the expression with the offending token underlined
+func (e *SyntaxError) Pointer() string {
+	return "  " + e.Query + "\n  " + strings.Repeat(" ", e.Pos) + strings.Repeat("^", e.Len)
+}
+
+// Parse compiles an expression
+func Parse(src string) (*Query, error) {
+	toks, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{src: src, toks: toks}
+	if p.peek().typ == tokEOF {
+		return nil, p.errorf(p.peek(), "empty expression")
+	}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.typ != tokEOF {
+		return nil, p.errorf(t, "unexpected %s, expected and, or or the end of the expression", t)
+	}
+	return &Query{src: src, root: root}, nil
+}
+
+// OptFields returns the opt_fields a task needs for the expression to be
+// evaluated, for callers that narrow the fields they request
+func (q *Query) OptFields() []string {
+	seen := make(map[string]bool)
+	var out []string
+	walk(q.root, func(t *test) {
+		for _, f := range t.optFields() {
+			if !seen[f] {
+				seen[f] = true
+				out = append(out, f)
+			}
+		}
+	})
+	return out
+}
+
+// UsesMe reports whether the expression refers to the current user, who
+// must then be given in Env.Me
+func (q *Query) UsesMe() bool {
+	uses := false
+	walk(q.root, func(t *test) {
+		for _, v := range t.values {
+			if t.field == "assignee" && !v.quoted && strings.EqualFold(v.text, "me") {
+				uses = true
+			}
+		}
+	})
+	return uses
+}
+
+type parser struct {
+	src  string
+	toks []token
+	pos  int
+}
+
+func (p *parser) peek() token {
+	return p.toks[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.toks[p.pos]
+	if t.typ != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) errorf(t token, format string, args ...interface{}) *SyntaxError {
+	n := len(t.text)
+	if t.typ == tokString {
+		n += 2
+	}
+	if n == 0 {
+		n = 1
+	}
+	return &SyntaxError{Query: p.src, Pos: t.pos, Len: n, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().isKeyword("or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &orNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().isKeyword("and") {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &andNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	t := p.peek()
+	switch {
+	case t.isKeyword("not"):
+		p.next()
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{inner}, nil
+	case t.typ == tokLParen:
+		p.next()
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if c := p.next(); c.typ != tokRParen {
+			return nil, p.errorf(c, "expected ) to close the ( at column %d, found %s", t.pos+1, c)
+		}
+		return inner, nil
+	}
+	return p.parseTest()
+}
+
+func (p *parser) parseTest() (node, error) {
+	t := p.next()
+	if t.typ != tokWord || isKeyword(t.text) {
+		return nil, p.errorf(t, "expected a field name, found %s", t)
+	}
+
+	tst := &test{}
+	name := strings.ToLower(t.text)
+	switch {
+	case name == "cf." && p.peek().typ == tokString:
+		tst.field, tst.custom, tst.kind = "cf", p.next().text, kindCustom
+	case strings.HasPrefix(name, "cf.") && len(name) > 3:
+		tst.field, tst.custom, tst.kind = "cf", t.text[3:], kindCustom
+	default:
+		if alias, ok := aliases[name]; ok {
+			name = alias
+		}
+		f, ok := fields[name]
+		if !ok {
+			return nil, p.errorf(t, "unknown field %q (fields: %s; custom fields as cf.Name)", t.text, strings.Join(Fields(), ", "))
+		}
+		tst.field, tst.kind = name, f.kind
+	}
+
+	op := p.peek()
+	switch {
+	case op.typ == tokOp:
+		p.next()
+		tst.op = op.text
+	case op.isKeyword("in"):
+		p.next()
+		tst.op = "in"
+	case op.isKeyword("not") && p.pos+1 < len(p.toks) && p.toks[p.pos+1].isKeyword("in"):
+		p.next()
+		p.next()
+		tst.op, tst.negate = "in", true
+	default:
+		// A bare field tests whether it is set
+		return tst, nil
+	}
+
+	if !containsOp(kindOps[tst.kind], tst.op) {
+		return nil, p.errorf(op, "%s can't be compared with %s (operators: %s)", t.text, tst.op, strings.Join(kindOps[tst.kind], " "))
+	}
+
+	if tst.op == "in" {
+		values, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		tst.values = values
+	} else {
+		v := p.next()
+		if v.typ != tokWord && v.typ != tokString {
+			return nil, p.errorf(v, "expected a value after %s, found %s", tst.op, v)
+		}
+		tst.values = []value{newValue(v)}
+	}
+
+	for i := range tst.values {
+		if err := tst.check(&tst.values[i]); err != nil {
+			return nil, p.errorf(tst.values[i].tok, "%s", err)
+		}
+	}
+	return tst, nil
+}
+
+// parseList reads a parenthesised, comma-separated list of values
+func (p *parser) parseList() ([]value, error) {
+	open := p.next()
+	if open.typ != tokLParen {
+		return nil, p.errorf(open, "expected ( after in, found %s", open)
+	}
+	var values []value
+	for {
+		v := p.next()
+		if v.typ != tokWord && v.typ != tokString {
+			return nil, p.errorf(v, "expected a value in the list, found %s", v)
+		}
+		values = append(values, newValue(v))
+
+		sep := p.next()
+		if sep.typ == tokRParen {
+			break
+		}
+		if sep.typ != tokComma {
+			return nil, p.errorf(sep, "expected , or ) in the list, found %s", sep)
+		}
+	}
+	return values, nil
+}
+
+func containsOp(ops []string, op string) bool {
+	for _, o := range ops {
+		if o == op {
+			return true
+		}
+	}
+	return false
+}
+
+// value is an operand as written, with quoted strings never taken as
+// keywords such as none or me
+type value struct {
+	text   string
+	quoted bool
+	tok    token
+
+	date   *dateValue
+	number float64
+}
+
+func newValue(t token) value {
+	return value{text: t.text, quoted: t.typ == tokString, tok: t}
+}
+
+func (v value) isNone() bool {
+	return !v.quoted && strings.EqualFold(v.text, "none")
+}
+
+// dateValue is an absolute date or an offset in days from today, resolved
+// when the expression is evaluated
+type dateValue struct {
+	date   string
+	offset int
+	named  string
+}
+
+func (d *dateValue) resolve(now time.Time) string {
+	switch d.named {
+	case "today":
+		return now.Format(DateLayout)
+	case "tomorrow":
+		return now.AddDate(0, 0, 1).Format(DateLayout)
+	case "yesterday":
+		return now.AddDate(0, 0, -1).Format(DateLayout)
+	case "offset":
+		return now.AddDate(0, 0, d.offset).Format(DateLayout)
+	}
+	return d.date
+}
+
+// DateLayout is the format of the dates the language compares
+const DateLayout = "2006-01-02"
+
+// ParseDate accepts YYYY-MM-DD, today, tomorrow, yesterday or an offset in
+// days such as +7d or -30d, and returns a YYYY-MM-DD date
+func ParseDate(s string, now time.Time) (string, error) {
+	d, err := parseDateValue(s)
+	if err != nil {
+		return "", err
+	}
+	return d.resolve(now), nil
+}
+
+func parseDateValue(s string) (*dateValue, error) {
+	switch lower := strings.ToLower(s); lower {
+	case "today", "tomorrow", "yesterday":
+		return &dateValue{named: lower}, nil
+	}
+	if strings.HasSuffix(s, "d") && (strings.HasPrefix(s, "+") || strings.HasPrefix(s, "-")) {
+		if n, err := strconv.Atoi(s[:len(s)-1]); err == nil {
+			return &dateValue{named: "offset", offset: n}, nil
+		}
+	}
+	if _, err := time.Parse(DateLayout, s); err != nil {
+		return nil, fmt.Errorf("invalid date %q, expected YYYY-MM-DD, today, tomorrow, yesterday or ±Nd", s)
+	}
+	return &dateValue{date: s}, nil
+}
@@ -0,0 +1,160 @@
+package query
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TheCoolRobot/asana-cli/internal/asana"
+)
+
+func date(s string) *asana.CustomTime {
+	t, _ := time.Parse(DateLayout, s)
+	return &asana.CustomTime{Time: t}
+}
+
+func TestMatch(t *testing.T) {
+	points := 5.0
+	high := asana.EnumOption{GID: "e1", Name: "High"}
+	env := Env{Me: "u1", Now: time.Date(2026, 3, 10, 12, 0, 0, 0, time.Local)}
+
+	tasks := map[string]*asana.Task{
+		"overdue": {
+			GID:      "1",
+			Name:     "Fix login bug",
+			DueDate:  date("2026-03-01"),
+			Assignee: &asana.User{GID: "u1", Name: "Ada", Email: "ada@example.com"},
+			Tags:     []asana.Tag{{GID: "t1", Name: "backend"}},
+			CustomFields: []asana.CustomFieldValue{
+				{CustomField: asana.CustomField{GID: "cf1", Name: "Story Points", Type: asana.CustomFieldNumber}, NumberValue: &points},
+				{CustomField: asana.CustomField{GID: "cf2", Name: "Priority", Type: asana.CustomFieldEnum}, EnumValue: &high},
+			},
+		},
+		"unassigned": {
+			GID:         "2",
+			Name:        "Write docs",
+			DueDate:     date("2026-03-20"),
+			Tags:        []asana.Tag{{GID: "t2", Name: "infra"}},
+			Memberships: []asana.Membership{{Section: &asana.Section{GID: "s1", Name: "Doing"}}},
+			NumSubtasks: 2,
+		},
+		"done": {
+			GID:       "3",
+			Name:      "Ship it",
+			Completed: true,
+			DueDate:   date("2026-02-01"),
+			Assignee:  &asana.User{GID: "u2", Name: "Bob"},
+			Parent:    &asana.Task{GID: "1", Name: "Fix login bug"},
+			CreatedAt: time.Date(2026, 1, 5, 9, 0, 0, 0, time.Local),
+		},
+	}
+
+	for _, c := range []struct {
+		expr string
+		want string
+	}{
+		{"due < today and assignee = me and not completed and tag in (backend, infra)", "overdue"},
+		{"not assignee", "unassigned"},
+		{"assignee = none", "unassigned"},
+		{"assignee != none and not completed", "overdue"},
+		{"overdue", "overdue"},
+		{"due <= 2026-03-01", "done,overdue"},
+		{"due > +7d", "unassigned"},
+		{"due = 2026-03-20", "unassigned"},
+		{"tag = BACKEND or tag = infra", "overdue,unassigned"},
+		{"tag not in (backend)", "done,unassigned"},
+		{"tags ~ fra", "unassigned"},
+		{"name ~ 'login'", "overdue"},
+		{"name !~ login", "done,unassigned"},
+		{"assignee = 'ada@example.com'", "overdue"},
+		{"assignee in (Bob, u1)", "done,overdue"},
+		{`cf."Story Points" > 3`, "overdue"},
+		{"cf.Priority = high", "overdue"},
+		{"cf.Priority = none", "done,unassigned"},
+		{"not cf.Priority", "done,unassigned"},
+		{"section = Doing", "unassigned"},
+		{"subtasks >= 2", "unassigned"},
+		{"subtask and parent = 'Fix login bug'", "done"},
+		{"created < 2026-02-01", "done"},
+		{"completed = false and (due < today or tag = infra)", "overdue,unassigned"},
+		{"NOT (completed OR overdue)", "unassigned"},
+		{"gid == 2", "unassigned"},
+	} {
+		q, err := Parse(c.expr)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", c.expr, err)
+			continue
+		}
+		var got []string
+		for _, name := range []string{"done", "overdue", "unassigned"} {
+			if q.Match(tasks[name], env) {
+				got = append(got, name)
+			}
+		}
+		if strings.Join(got, ",") != c.want {
+			t.Errorf("%q matched %v, want %s", c.expr, got, c.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, c := range []struct {
+		expr  string
+		col   int
+		len   int
+		inMsg string
+	}{
+		{"dew < today", 1, 3, `unknown field "dew"`},
+		{"due < tomorow", 7, 7, `invalid date "tomorow"`},
+		{"completed and and tag = x", 15, 3, `expected a field name, found "and"`},
+		{"tag in (a, b", 13, 1, "expected , or ) in the list, found end of expression"},
+		{"(due < today", 13, 1, "expected ) to close the ( at column 1"},
+		{"name < x", 6, 1, "name can't be compared with <"},
+		{"subtasks = many", 12, 4, "not a number"},
+		{"name = 'unterminated", 8, 13, "unterminated string"},
+		{"completed = maybe", 13, 5, "expected true or false"},
+		{"due < none", 7, 4, "none can only be used"},
+		{"tag = x tag = y", 9, 3, `unexpected "tag"`},
+		{"", 1, 1, "empty expression"},
+		{"! completed", 1, 1, "use not or !="},
+	} {
+		_, err := Parse(c.expr)
+		var syn *SyntaxError
+		if !errors.As(err, &syn) {
+			t.Errorf("Parse(%q) = %v, want a SyntaxError", c.expr, err)
+			continue
+		}
+		if syn.Pos+1 != c.col || syn.Len != c.len || !strings.Contains(syn.Msg, c.inMsg) {
+			t.Errorf("Parse(%q) error %q at column %d (length %d), want %q at column %d (length %d)",
+				c.expr, syn.Msg, syn.Pos+1, syn.Len, c.inMsg, c.col, c.len)
+		}
+	}
+
+	_, err := Parse("due < tomorow")
+	want := "  due < tomorow\n        ^^^^^^^"
+	if got := err.(*SyntaxError).Pointer(); got != want {
+		t.Errorf("Pointer() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestOptFieldsAndUsesMe(t *testing.T) {
+	q, err := Parse("assignee = me and tag = x and cf.Sprint = 3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !q.UsesMe() {
+		t.Error("UsesMe() = false, want true")
+	}
+	fields := strings.Join(q.OptFields(), ",")
+	for _, want := range []string{"assignee.name", "tags.name", "custom_fields.number_value"} {
+		if !strings.Contains(fields, want) {
+			t.Errorf("OptFields() = %s, missing %s", fields, want)
+		}
+	}
+
+	q, _ = Parse("assignee = 'me'")
+	if q.UsesMe() {
+		t.Error("a quoted 'me' is a name, not the current user")
+	}
+}
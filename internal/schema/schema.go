@@ -0,0 +1,167 @@
+// Package schema derives JSON Schema documents from the Go types the CLI
+// prints, following the same rules as encoding/json: field names come from
+// json tags, embedded structs are inlined, omitempty fields are optional and
+// types with their own MarshalJSON (such as times) are strings.
+package schema
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Draft is the JSON Schema dialect of the generated documents
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema node
+type Schema map[string]interface{}
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textType      = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// Generate returns a document describing the JSON encoding of v's type.
+// Named structs are collected under $defs and referred to by name, which
+// also covers recursive types such as a task's parent task.
+func Generate(title string, v interface{}) Schema {
+	g := &generator{defs: Schema{}}
+	doc := g.schema(reflect.TypeOf(v))
+	doc["$schema"] = Draft
+	doc["title"] = title
+	if len(g.defs) > 0 {
+		doc["$defs"] = g.defs
+	}
+	return doc
+}
+
+type generator struct {
+	defs Schema
+}
+
+func (g *generator) schema(t reflect.Type) Schema {
+	if t == nil {
+		return Schema{}
+	}
+	if t.Kind() == reflect.Pointer {
+		return g.schema(t.Elem())
+	}
+	if t == timeType || t.Implements(marshalerType) || reflect.PointerTo(t).Implements(marshalerType) {
+		if isTime(t) {
+			return Schema{"type": "string", "format": "date-time"}
+		}
+		return Schema{}
+	}
+	if t.Implements(textType) || reflect.PointerTo(t).Implements(textType) {
+		return Schema{"type": "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return Schema{"type": "string"}
+	case reflect.Bool:
+		return Schema{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Schema{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return Schema{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return Schema{"type": "array", "items": g.schema(t.Elem())}
+	case reflect.Map:
+		return Schema{"type": "object", "additionalProperties": g.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.object(t)
+		}
+		if _, ok := g.defs[t.Name()]; !ok {
+			// Reserve the name first so recursive references resolve
+			g.defs[t.Name()] = Schema{}
+			g.defs[t.Name()] = g.object(t)
+		}
+		return Schema{"$ref": "#/$defs/" + t.Name()}
+	}
+	// interface{} and anything else may hold any value
+	return Schema{}
+}
+
+// object describes a struct's fields, inlining embedded structs
+func (g *generator) object(t reflect.Type) Schema {
+	properties := Schema{}
+	required := []string{}
+	g.fields(t, properties, &required)
+
+	s := Schema{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	return s
+}
+
+func (g *generator) fields(t reflect.Type, properties Schema, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		ft := f.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct && !isTime(ft) {
+			g.fields(ft, properties, required)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		s := g.schema(f.Type)
+		omitempty := strings.Contains(opts, "omitempty")
+		if !omitempty {
+			*required = append(*required, name)
+			if f.Type.Kind() == reflect.Pointer {
+				s = nullable(s)
+			}
+		}
+		properties[name] = s
+	}
+}
+
+// nullable lets a schema also match null, as a nil pointer encodes
+func nullable(s Schema) Schema {
+	if typ, ok := s["type"].(string); ok {
+		s["type"] = []string{typ, "null"}
+		return s
+	}
+	if len(s) == 0 {
+		return s
+	}
+	return Schema{"anyOf": []Schema{s, {"type": "null"}}}
+}
+
+// isTime reports whether t is time.Time or a struct wrapping it, such as
+// asana.CustomTime
+func isTime(t reflect.Type) bool {
+	if t == timeType {
+		return true
+	}
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if f := t.Field(i); f.Anonymous && f.Type == timeType {
+			return true
+		}
+	}
+	return false
+}
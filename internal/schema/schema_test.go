@@ -0,0 +1,67 @@
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type stamp struct {
+	time.Time
+}
+
+type base struct {
+	ID string `json:"id"`
+}
+
+type node struct {
+	base
+	Name     string            `json:"name"`
+	Count    int               `json:"count,omitempty"`
+	Score    *float64          `json:"score"`
+	Parent   *node             `json:"parent,omitempty"`
+	Children []node            `json:"children,omitempty"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Due      *stamp            `json:"due,omitempty"`
+	Seen     time.Time         `json:"seen"`
+	Extra    interface{}       `json:"extra,omitempty"`
+	Skipped  string            `json:"-"`
+	hidden   string
+}
+
+func TestGenerate(t *testing.T) {
+	doc := Generate("node", node{})
+	if doc["$ref"] != "#/$defs/node" || doc["$schema"] != Draft || doc["title"] != "node" {
+		t.Fatalf("unexpected document header: %v", doc)
+	}
+
+	// Round-trip through JSON to compare plain values
+	b, err := json.Marshal(doc["$defs"].(Schema)["node"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]interface{}
+	json.Unmarshal(b, &got)
+
+	want := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"id":       map[string]interface{}{"type": "string"},
+			"name":     map[string]interface{}{"type": "string"},
+			"count":    map[string]interface{}{"type": "integer"},
+			"score":    map[string]interface{}{"type": []interface{}{"number", "null"}},
+			"parent":   map[string]interface{}{"$ref": "#/$defs/node"},
+			"children": map[string]interface{}{"type": "array", "items": map[string]interface{}{"$ref": "#/$defs/node"}},
+			"labels":   map[string]interface{}{"type": "object", "additionalProperties": map[string]interface{}{"type": "string"}},
+			"due":      map[string]interface{}{"type": "string", "format": "date-time"},
+			"seen":     map[string]interface{}{"type": "string", "format": "date-time"},
+			"extra":    map[string]interface{}{},
+		},
+		"required": []interface{}{"id", "name", "score", "seen"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		g, _ := json.MarshalIndent(got, "", "  ")
+		t.Errorf("node schema =\n%s", g)
+	}
+}
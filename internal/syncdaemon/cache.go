@@ -1,8 +1,10 @@
 package syncdaemon
 
 import (
+	"bufio"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"time"
@@ -21,29 +23,64 @@ type CacheFile struct {
 	Tasks    []asana.Task  `json:"tasks"`
 }
 
-func cacheTasks(projectID string, tasks []asana.Task) error {
+// cacheTasks streams every task from the iterator into the project's cache
+// file, so large projects never have to be held in memory in full. The file
+// is written to a temporary path and renamed into place once complete.
+func cacheTasks(projectID string, it *asana.Iterator[asana.Task]) (int, error) {
 	cacheDir := getCacheDir()
 	if err := os.MkdirAll(cacheDir, 0755); err != nil {
-		return err
+		return 0, err
 	}
 
-	cacheFile := CacheFile{
-		Metadata: CacheMetadata{
-			ProjectID: projectID,
-			SyncedAt:  time.Now(),
-			TaskCount: len(tasks),
-		},
-		Tasks: tasks,
+	filePath := filepath.Join(cacheDir, fmt.Sprintf("project-%s.json", projectID))
+	tmpPath := filePath + ".tmp"
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return 0, err
 	}
 
-	filePath := filepath.Join(cacheDir, fmt.Sprintf("project-%s.json", projectID))
-	f, err := os.Create(filePath)
+	count, err := writeCache(f, projectID, it)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
 	if err != nil {
-		return err
+		os.Remove(tmpPath)
+		return 0, err
 	}
-	defer f.Close()
 
-	return json.NewEncoder(f).Encode(cacheFile)
+	return count, os.Rename(tmpPath, filePath)
+}
+
+// writeCache encodes a CacheFile incrementally. Tasks are written before
+// the metadata since the task count is only known once the iterator is done.
+func writeCache(w io.Writer, projectID string, it *asana.Iterator[asana.Task]) (int, error) {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+
+	bw.WriteString(`{"tasks":[`)
+	for it.Next() {
+		if it.Count() > 1 {
+			bw.WriteString(",")
+		}
+		if err := enc.Encode(it.Value()); err != nil {
+			return 0, err
+		}
+	}
+	if err := it.Err(); err != nil {
+		return 0, err
+	}
+
+	bw.WriteString(`],"metadata":`)
+	if err := enc.Encode(CacheMetadata{
+		ProjectID: projectID,
+		SyncedAt:  time.Now(),
+		TaskCount: it.Count(),
+	}); err != nil {
+		return 0, err
+	}
+	bw.WriteString("}\n")
+
+	return it.Count(), bw.Flush()
 }
 
 func LoadCachedTasks(projectID string) ([]asana.Task, *CacheMetadata, error) {
@@ -1,6 +1,7 @@
 package syncdaemon
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -19,16 +20,18 @@ type Daemon struct {
 	lastSync    map[string]time.Time
 }
 
-func NewDaemon(apiToken string, projectIDs []string) *Daemon {
+func NewDaemon(client *asana.Client, projectIDs []string) *Daemon {
 	return &Daemon{
-		client:     asana.NewClient(apiToken),
+		client:     client,
 		projectIDs: projectIDs,
 		done:       make(chan bool),
 		lastSync:   make(map[string]time.Time),
 	}
 }
 
-func (d *Daemon) Start() {
+// Start syncs every project immediately and then on each SyncInterval tick
+// until ctx is cancelled or Stop is called
+func (d *Daemon) Start(ctx context.Context) {
 	d.syncTicker = time.NewTicker(SyncInterval)
 	defer d.syncTicker.Stop()
 
@@ -37,15 +40,19 @@ func (d *Daemon) Start() {
 	fmt.Printf("[sync-daemon] Cache dir: %s\n", getCacheDir())
 
 	// Initial sync
-	d.syncAll()
+	d.syncAll(ctx)
 
 	for {
 		select {
+		case <-ctx.Done():
+			fmt.Printf("\n[sync-daemon] Stopping: %v\n", ctx.Err())
+			fmt.Println("[sync-daemon] Exiting...")
+			return
 		case <-d.done:
 			fmt.Println("[sync-daemon] Exiting...")
 			return
 		case <-d.syncTicker.C:
-			d.syncAll()
+			d.syncAll(ctx)
 		}
 	}
 }
@@ -54,26 +61,25 @@ func (d *Daemon) Stop() {
 	d.done <- true
 }
 
-func (d *Daemon) syncAll() {
+func (d *Daemon) syncAll(ctx context.Context) {
 	for _, projectID := range d.projectIDs {
-		if err := d.syncProject(projectID); err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := d.syncProject(ctx, projectID); err != nil {
 			fmt.Printf("[sync-daemon] Error syncing project %s: %v\n", projectID, err)
 		}
 	}
 }
 
-func (d *Daemon) syncProject(projectID string) error {
-	tasks, err := d.client.GetTasks(projectID, nil)
+func (d *Daemon) syncProject(ctx context.Context, projectID string) error {
+	count, err := cacheTasks(projectID, d.client.IterTasks(ctx, projectID, nil))
 	if err != nil {
 		return err
 	}
 
-	if err := cacheTasks(projectID, tasks); err != nil {
-		return err
-	}
-
 	d.lastSync[projectID] = time.Now()
-	fmt.Printf("[sync-daemon] ✓ Synced %d tasks for project %s\n", len(tasks), projectID)
+	fmt.Printf("[sync-daemon] ✓ Synced %d tasks for project %s\n", count, projectID)
 	return nil
 }
 
@@ -0,0 +1,70 @@
+package syncdaemon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/TheCoolRobot/asana-cli/internal/asana"
+	"github.com/TheCoolRobot/asana-cli/internal/asanatest"
+)
+
+func TestSyncProjectWritesCache(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	srv := asanatest.NewServer(t)
+	ws := srv.AddWorkspace("Acme")
+	proj := srv.AddProject(ws.GID, "Big")
+	for i := 0; i < 150; i++ {
+		srv.AddTask(proj.GID, asana.Task{Name: fmt.Sprintf("Task %d", i)})
+	}
+
+	d := NewDaemon(srv.Client(), []string{proj.GID})
+	if err := d.syncProject(context.Background(), proj.GID); err != nil {
+		t.Fatalf("syncProject failed: %v", err)
+	}
+
+	tasks, meta, err := LoadCachedTasks(proj.GID)
+	if err != nil {
+		t.Fatalf("LoadCachedTasks failed: %v", err)
+	}
+	if len(tasks) != 150 || meta.TaskCount != 150 {
+		t.Errorf("expected 150 cached tasks, got %d (metadata %d)", len(tasks), meta.TaskCount)
+	}
+	if tasks[149].Name != "Task 149" || meta.ProjectID != proj.GID {
+		t.Errorf("unexpected cache contents: %+v %+v", tasks[149], meta)
+	}
+	if _, ok := d.lastSync[proj.GID]; !ok {
+		t.Error("lastSync not recorded")
+	}
+}
+
+func TestSyncProjectFailureKeepsPreviousCache(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	srv := asanatest.NewServer(t)
+	ws := srv.AddWorkspace("Acme")
+	proj := srv.AddProject(ws.GID, "Roadmap")
+	srv.AddTask(proj.GID, asana.Task{Name: "Cached"})
+
+	d := NewDaemon(srv.Client(), []string{proj.GID})
+	if err := d.syncProject(context.Background(), proj.GID); err != nil {
+		t.Fatalf("initial sync failed: %v", err)
+	}
+
+	srv.Fail(asanatest.Failure{Path: "/projects/*", Status: http.StatusNotFound})
+	if err := d.syncProject(context.Background(), proj.GID); !asana.IsNotFound(err) {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+
+	tasks, _, err := LoadCachedTasks(proj.GID)
+	if err != nil || len(tasks) != 1 {
+		t.Fatalf("previous cache lost: %v, %d tasks", err, len(tasks))
+	}
+	if _, err := os.Stat(filepath.Join(getCacheDir(), "project-"+proj.GID+".json.tmp")); !os.IsNotExist(err) {
+		t.Error("temporary cache file left behind")
+	}
+}
@@ -2,41 +2,179 @@ package ui
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+
+	"github.com/TheCoolRobot/asana-cli/internal/asana"
+	"github.com/TheCoolRobot/asana-cli/internal/jq"
 )
 
+// SchemaVersion is reported as meta.schema_version in every --json
+// envelope. It changes only when a field is removed, renamed or changes
+// type; new fields may appear without a bump.
+const SchemaVersion = 1
+
+// JSONOutput is the envelope every --json command prints. Its shape and
+// that of the records in Data are published by "asana-cli schema".
 type JSONOutput struct {
-	Success bool        `json:"success"`
-	Data    interface{} `json:"data,omitempty"`
-	Error   string      `json:"error,omitempty"`
+	Success bool                   `json:"success"`
+	Data    interface{}            `json:"data,omitempty"`
+	Error   string                 `json:"error,omitempty"`
+	Code    string                 `json:"code,omitempty"`
+	Details *asana.APIError        `json:"details,omitempty"`
 	Meta    map[string]interface{} `json:"meta,omitempty"`
+	DryRun  *DryRun                `json:"dry_run,omitempty"`
+}
+
+// DryRun lists the requests --dry-run kept from being sent
+type DryRun struct {
+	Requests []asana.PlannedRequest `json:"requests"`
+}
+
+// setError fills in the error fields of the envelope, including the
+// machine-readable code and, for API failures, the status and messages
+func (o *JSONOutput) setError(err error) {
+	o.Error = err.Error()
+	o.Code = asana.ErrorCode(err)
+	var apiErr *asana.APIError
+	if errors.As(err, &apiErr) {
+		o.Details = apiErr
+	}
+}
+
+var (
+	// jsonQuery, set by --query, picks values out of every successful
+	// envelope before it is printed
+	jsonQuery    *jq.Query
+	jsonQueryErr error
+)
+
+// dryRunRequests, set by --dry-run, returns the requests skipped so far
+var dryRunRequests func() []asana.PlannedRequest
+
+// SetDryRun makes the JSON printers add the requests f returns to every
+// envelope, or stops them if f is nil
+func SetDryRun(f func() []asana.PlannedRequest) {
+	dryRunRequests = f
+}
+
+// dryRunReport returns the dry_run field of an envelope
+func dryRunReport() *DryRun {
+	if dryRunRequests == nil {
+		return nil
+	}
+	requests := dryRunRequests()
+	if requests == nil {
+		requests = []asana.PlannedRequest{}
+	}
+	return &DryRun{Requests: requests}
+}
+
+// SetQuery makes the JSON printers apply q to the envelopes of successful
+// commands, or print them whole again if q is nil
+func SetQuery(q *jq.Query) {
+	jsonQuery = q
+	jsonQueryErr = nil
+}
+
+// QueryError returns the error of a --query that failed on an envelope
+// printed by PrintJSON or PrintJSONWithMeta, which cannot return it
+func QueryError() error {
+	return jsonQueryErr
+}
+
+// writeEnvelope prints an envelope as indented JSON, or the results of
+// --query: strings raw and other scalars as JSON, one per line, so they
+// compose with xargs, and objects and arrays indented. Failed envelopes are
+// printed whole unless they carry partial results. A successful dry run
+// that skipped requests has no data, since it changed nothing to report.
+func writeEnvelope(w io.Writer, output JSONOutput) error {
+	if output.Success && output.DryRun != nil && len(output.DryRun.Requests) > 0 {
+		output.Data = nil
+	}
+	if jsonQuery == nil || (!output.Success && output.Data == nil) {
+		b, err := json.MarshalIndent(output, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%s\n", b)
+		return err
+	}
+
+	raw, err := json.Marshal(output)
+	if err != nil {
+		return err
+	}
+	var doc interface{}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return err
+	}
+	results, err := jsonQuery.Run(doc)
+	if err != nil {
+		return fmt.Errorf("--query %s: %w", jsonQuery, err)
+	}
+	for _, r := range results {
+		var line []byte
+		switch r := r.(type) {
+		case string:
+			line = []byte(r)
+		case map[string]interface{}, []interface{}:
+			line, err = json.MarshalIndent(r, "", "  ")
+		default:
+			line, err = json.Marshal(r)
+		}
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "%s\n", line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// versionedMeta returns meta with the schema version added, leaving the
+// caller's map untouched
+func versionedMeta(meta map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(meta)+1)
+	for k, v := range meta {
+		out[k] = v
+	}
+	out["schema_version"] = SchemaVersion
+	return out
 }
 
 func PrintJSON(data interface{}, err error) {
-	output := JSONOutput{Success: err == nil}
+	output := JSONOutput{Success: err == nil, Meta: versionedMeta(nil), DryRun: dryRunReport()}
 
 	if err != nil {
-		output.Error = err.Error()
+		output.setError(err)
 	} else {
 		output.Data = data
 	}
 
-	jsonBytes, _ := json.MarshalIndent(output, "", "  ")
-	fmt.Println(string(jsonBytes))
+	if err := writeEnvelope(os.Stdout, output); err != nil {
+		jsonQueryErr = err
+	}
 }
 
+// PrintJSONWithMeta prints an envelope with meta. A command that failed
+// part way may pass both its partial results and the error.
 func PrintJSONWithMeta(data interface{}, meta map[string]interface{}, err error) {
 	output := JSONOutput{
 		Success: err == nil,
-		Meta:    meta,
+		Data:    data,
+		Meta:    versionedMeta(meta),
+		DryRun:  dryRunReport(),
 	}
 
 	if err != nil {
-		output.Error = err.Error()
-	} else {
-		output.Data = data
+		output.setError(err)
 	}
 
-	jsonBytes, _ := json.MarshalIndent(output, "", "  ")
-	fmt.Println(string(jsonBytes))
-}
\ No newline at end of file
+	if err := writeEnvelope(os.Stdout, output); err != nil {
+		jsonQueryErr = err
+	}
+}
@@ -0,0 +1,484 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Output formats accepted by --output
+var OutputFormats = []string{"table", "csv", "tsv", "yaml", "markdown", "ndjson", "json"}
+
+// maxCellWidth caps the width of a table cell; longer values are cut
+// short with an ellipsis
+const maxCellWidth = 60
+
+// Column is an output column: the name used by --columns and in headers,
+// and the dotted path of the JSON field it shows
+type Column struct {
+	Name string
+	Path string
+	// Date shows only the date part of a timestamp
+	Date bool
+}
+
+// Columns describes how a kind of record is shown: the columns that can
+// be picked by name and those shown when --columns isn't given. Any
+// dotted JSON path, such as assignee.gid, can be picked as well.
+type Columns struct {
+	Available []Column
+	Default   []string
+}
+
+var TaskColumns = Columns{
+	Available: []Column{
+		{Name: "gid", Path: "gid"},
+		{Name: "name", Path: "name"},
+		{Name: "completed", Path: "completed"},
+		{Name: "due", Path: "due_on", Date: true},
+		{Name: "due_at", Path: "due_at"},
+		{Name: "assignee", Path: "assignee.name"},
+		{Name: "assignee_email", Path: "assignee.email"},
+		{Name: "notes", Path: "notes"},
+		{Name: "projects", Path: "projects.name"},
+		{Name: "tags", Path: "tags.name"},
+		{Name: "sections", Path: "memberships.section.name"},
+		{Name: "parent", Path: "parent.name"},
+		{Name: "subtasks", Path: "num_subtasks"},
+		{Name: "created", Path: "created_at"},
+		{Name: "modified", Path: "modified_at"},
+	},
+	Default: []string{"gid", "name", "due", "assignee", "completed"},
+}
+
+var UserColumns = Columns{
+	Available: []Column{
+		{Name: "gid", Path: "gid"},
+		{Name: "name", Path: "name"},
+		{Name: "email", Path: "email"},
+	},
+	Default: []string{"gid", "name", "email"},
+}
+
+var ProjectColumns = Columns{
+	Available: []Column{
+		{Name: "gid", Path: "gid"},
+		{Name: "name", Path: "name"},
+		{Name: "team", Path: "team.name"},
+		{Name: "owner", Path: "owner.name"},
+		{Name: "color", Path: "color"},
+		{Name: "archived", Path: "archived"},
+		{Name: "public", Path: "public"},
+		{Name: "due", Path: "due_on", Date: true},
+		{Name: "view", Path: "default_view"},
+		{Name: "notes", Path: "notes"},
+		{Name: "created", Path: "created_at"},
+		{Name: "modified", Path: "modified_at"},
+	},
+	Default: []string{"gid", "name", "team", "archived"},
+}
+
+// ProjectConfigColumns show the projects saved in the config, as listed
+// by "config project list"
+var ProjectConfigColumns = Columns{
+	Available: []Column{
+		{Name: "current", Path: "current"},
+		{Name: "name", Path: "name"},
+		{Name: "project_id", Path: "project_id"},
+		{Name: "workspace_id", Path: "workspace_id"},
+		{Name: "description", Path: "description"},
+	},
+	Default: []string{"current", "name", "project_id", "description"},
+}
+
+// PrintOutput writes data, a record or a slice of records, to w in one of
+// OutputFormats. selected picks columns by name or dotted JSON path. With
+// no selection the tabular formats show the default columns and yaml,
+// ndjson and json show every field. json keeps the usual envelope and meta.
+func PrintOutput(w io.Writer, format string, data interface{}, meta map[string]interface{}, columns Columns, selected []string) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	decoded, err := decodeOrdered(raw)
+	if err != nil {
+		return err
+	}
+	records, single := decoded.([]interface{})
+	single = !single
+	if single {
+		records = []interface{}{decoded}
+	}
+
+	names := selected
+	if len(names) == 0 {
+		names = columns.Default
+	}
+	cols, err := resolveColumns(columns, names, records)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case "table":
+		return writeTable(w, cols, records, single)
+	case "markdown":
+		return writeMarkdown(w, cols, records, single)
+	case "csv", "tsv":
+		return writeDelimited(w, cols, records, format == "tsv")
+	}
+
+	// The structured formats keep whole records unless columns were picked
+	if len(selected) > 0 {
+		for i, r := range records {
+			records[i] = selectColumns(r, cols)
+		}
+		decoded = records
+		if single {
+			decoded = records[0]
+		}
+	}
+
+	switch format {
+	case "yaml":
+		var b bytes.Buffer
+		writeYAML(&b, decoded)
+		_, err = w.Write(b.Bytes())
+		return err
+	case "ndjson":
+		for _, r := range records {
+			if err := writeNDJSON(w, r); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "json":
+		output := JSONOutput{Success: true, Data: decoded, Meta: versionedMeta(meta), DryRun: dryRunReport()}
+		if len(selected) > 0 {
+			output.Meta["columns"] = selected
+		}
+		return writeEnvelope(w, output)
+	}
+	return fmt.Errorf("unknown output format %q (formats: %s)", format, strings.Join(OutputFormats, ", "))
+}
+
+// NDJSONWriter writes records one per line as they arrive, so a listing
+// can stream page by page. Selected columns are resolved against the first
+// record.
+type NDJSONWriter struct {
+	w        io.Writer
+	columns  Columns
+	selected []string
+	cols     []Column
+}
+
+func NewNDJSONWriter(w io.Writer, columns Columns, selected []string) *NDJSONWriter {
+	return &NDJSONWriter{w: w, columns: columns, selected: selected}
+}
+
+// Write prints one record
+func (n *NDJSONWriter) Write(record interface{}) error {
+	raw, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	decoded, err := decodeOrdered(raw)
+	if err != nil {
+		return err
+	}
+	if len(n.selected) > 0 {
+		if n.cols == nil {
+			if n.cols, err = resolveColumns(n.columns, n.selected, []interface{}{decoded}); err != nil {
+				return err
+			}
+		}
+		decoded = selectColumns(decoded, n.cols)
+	}
+	return writeNDJSON(n.w, decoded)
+}
+
+func writeNDJSON(w io.Writer, record interface{}) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", line)
+	return err
+}
+
+// resolveColumns looks up column names, accepting any dotted JSON path
+// that at least one record has
+func resolveColumns(columns Columns, names []string, records []interface{}) ([]Column, error) {
+	cols := make([]Column, 0, len(names))
+	for _, name := range names {
+		col, ok := findColumn(columns, name)
+		if !ok {
+			col = Column{Name: name, Path: name}
+			if !anyHasPath(records, col.Path) && len(records) > 0 {
+				return nil, fmt.Errorf("unknown column %q (columns: %s; or a JSON field such as assignee.gid)", name, columnNames(columns))
+			}
+		}
+		cols = append(cols, col)
+	}
+	return cols, nil
+}
+
+func findColumn(columns Columns, name string) (Column, bool) {
+	for _, c := range columns.Available {
+		if strings.EqualFold(c.Name, name) {
+			return c, true
+		}
+	}
+	return Column{}, false
+}
+
+func columnNames(columns Columns) string {
+	names := make([]string, len(columns.Available))
+	for i, c := range columns.Available {
+		names[i] = c.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+func anyHasPath(records []interface{}, path string) bool {
+	for _, r := range records {
+		if hasPath(r, strings.Split(path, ".")) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasPath(v interface{}, path []string) bool {
+	if len(path) == 0 {
+		return true
+	}
+	switch v := v.(type) {
+	case *orderedMap:
+		child, ok := v.values[path[0]]
+		return ok && hasPath(child, path[1:])
+	case []interface{}:
+		for _, e := range v {
+			if hasPath(e, path) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// lookup follows a dotted path through a record. Arrays along the way are
+// mapped over, so tags.name gives every tag's name.
+func lookup(v interface{}, path []string) interface{} {
+	if len(path) == 0 {
+		return v
+	}
+	switch v := v.(type) {
+	case *orderedMap:
+		return lookup(v.values[path[0]], path[1:])
+	case []interface{}:
+		out := []interface{}{}
+		for _, e := range v {
+			switch found := lookup(e, path).(type) {
+			case nil:
+			case []interface{}:
+				out = append(out, found...)
+			default:
+				out = append(out, found)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+func selectColumns(record interface{}, cols []Column) interface{} {
+	m := newOrderedMap()
+	for _, c := range cols {
+		m.set(c.Name, lookup(record, strings.Split(c.Path, ".")))
+	}
+	return m
+}
+
+// cellText renders a value for the tabular formats
+func cellText(v interface{}, col Column) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		if col.Date {
+			if t, err := time.Parse(time.RFC3339, v); err == nil {
+				return t.Format("2006-01-02")
+			}
+		}
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		if v {
+			return "true"
+		}
+		return "false"
+	case []interface{}:
+		parts := make([]string, 0, len(v))
+		for _, e := range v {
+			if s := cellText(e, col); s != "" {
+				parts = append(parts, s)
+			}
+		}
+		return strings.Join(parts, ", ")
+	case *orderedMap:
+		// A nested record is shown by its name, as the API's compact form
+		for _, key := range []string{"name", "gid"} {
+			if s, ok := v.values[key].(string); ok && s != "" {
+				return s
+			}
+		}
+		b, _ := json.Marshal(v)
+		return string(b)
+	}
+	return fmt.Sprint(v)
+}
+
+// rows renders the records' cells, one line per record
+func rows(cols []Column, records []interface{}) [][]string {
+	out := make([][]string, len(records))
+	for i, r := range records {
+		out[i] = make([]string, len(cols))
+		for j, c := range cols {
+			text := cellText(lookup(r, strings.Split(c.Path, ".")), c)
+			out[i][j] = strings.Join(strings.Fields(text), " ")
+		}
+	}
+	return out
+}
+
+// writeTable aligns the records in columns under upper-case headers. A
+// single record is shown as one field per line instead.
+func writeTable(w io.Writer, cols []Column, records []interface{}, single bool) error {
+	header := make([]string, len(cols))
+	for i, c := range cols {
+		header[i] = strings.ToUpper(c.Name)
+	}
+	cells := rows(cols, records)
+
+	if single {
+		var b strings.Builder
+		width := 0
+		for _, h := range header {
+			width = max(width, lipgloss.Width(h))
+		}
+		for i, h := range header {
+			fmt.Fprintf(&b, "%s%s  %s\n", h, strings.Repeat(" ", width-lipgloss.Width(h)), cells[0][i])
+		}
+		_, err := io.WriteString(w, b.String())
+		return err
+	}
+
+	all := append([][]string{header}, cells...)
+	widths := make([]int, len(cols))
+	for _, row := range all {
+		for i, cell := range row {
+			row[i] = truncate(cell, maxCellWidth)
+			widths[i] = max(widths[i], lipgloss.Width(row[i]))
+		}
+	}
+
+	var b strings.Builder
+	for _, row := range all {
+		for i, cell := range row {
+			if i == len(row)-1 {
+				b.WriteString(cell)
+				break
+			}
+			b.WriteString(cell + strings.Repeat(" ", widths[i]-lipgloss.Width(cell)+2))
+		}
+		b.WriteString("\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func truncate(s string, width int) string {
+	if lipgloss.Width(s) <= width {
+		return s
+	}
+	var b strings.Builder
+	w := 0
+	for _, r := range s {
+		rw := lipgloss.Width(string(r))
+		if w+rw > width-1 {
+			break
+		}
+		b.WriteRune(r)
+		w += rw
+	}
+	return b.String() + "…"
+}
+
+// writeMarkdown writes a GitHub-flavoured table; a single record becomes
+// a field/value table
+func writeMarkdown(w io.Writer, cols []Column, records []interface{}, single bool) error {
+	escape := func(s string) string {
+		return strings.ReplaceAll(s, "|", `\|`)
+	}
+	cells := rows(cols, records)
+
+	var b strings.Builder
+	if single {
+		b.WriteString("| Field | Value |\n| --- | --- |\n")
+		for i, c := range cols {
+			fmt.Fprintf(&b, "| %s | %s |\n", escape(c.Name), escape(cells[0][i]))
+		}
+	} else {
+		names := make([]string, len(cols))
+		rule := make([]string, len(cols))
+		for i, c := range cols {
+			names[i] = escape(c.Name)
+			rule[i] = "---"
+		}
+		fmt.Fprintf(&b, "| %s |\n| %s |\n", strings.Join(names, " | "), strings.Join(rule, " | "))
+		for _, row := range cells {
+			for i := range row {
+				row[i] = escape(row[i])
+			}
+			fmt.Fprintf(&b, "| %s |\n", strings.Join(row, " | "))
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeDelimited writes CSV, quoted as needed, or TSV, where tabs in
+// values are replaced by spaces
+func writeDelimited(w io.Writer, cols []Column, records []interface{}, tabs bool) error {
+	header := make([]string, len(cols))
+	for i, c := range cols {
+		header[i] = c.Name
+	}
+	all := append([][]string{header}, rows(cols, records)...)
+
+	if tabs {
+		var b strings.Builder
+		for _, row := range all {
+			for i := range row {
+				row[i] = strings.ReplaceAll(row[i], "\t", " ")
+			}
+			b.WriteString(strings.Join(row, "\t") + "\n")
+		}
+		_, err := io.WriteString(w, b.String())
+		return err
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.WriteAll(all); err != nil {
+		return err
+	}
+	return cw.Error()
+}
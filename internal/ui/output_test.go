@@ -0,0 +1,121 @@
+package ui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TheCoolRobot/asana-cli/internal/asana"
+)
+
+func TestYAML(t *testing.T) {
+	v, err := decodeOrdered([]byte(`{
+		"name": "Plan: phase 2",
+		"plain": "Write docs",
+		"gid": "1201",
+		"flag": "yes",
+		"count": 3,
+		"done": false,
+		"empty": "",
+		"notes": "line one\nline two",
+		"owner": {"name": "Ada", "email": null},
+		"tags": [{"name": "bug", "color": "red"}, {"name": "- dash"}],
+		"nested": [[1, 2], []],
+		"none": {}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var b bytes.Buffer
+	writeYAML(&b, v)
+	want := strings.Join([]string{
+		`name: "Plan: phase 2"`,
+		`plain: Write docs`,
+		`gid: "1201"`,
+		`flag: "yes"`,
+		`count: 3`,
+		`done: false`,
+		`empty: ""`,
+		`notes: "line one\nline two"`,
+		`owner:`,
+		`  name: Ada`,
+		`  email: null`,
+		`tags:`,
+		`  - name: bug`,
+		`    color: red`,
+		`  - name: "- dash"`,
+		`nested:`,
+		`  -`,
+		`    - 1`,
+		`    - 2`,
+		`  - []`,
+		`none: {}`,
+		``,
+	}, "\n")
+	if b.String() != want {
+		t.Errorf("YAML =\n%s\nwant\n%s", b.String(), want)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	if got := truncate("short", 10); got != "short" {
+		t.Errorf("truncate kept %q", got)
+	}
+	if got := truncate("a long task name", 8); got != "a long …" {
+		t.Errorf("truncate = %q", got)
+	}
+	if got := truncate("日本語のタスク", 7); got != "日本語…" {
+		t.Errorf("truncate of wide runes = %q", got)
+	}
+}
+
+func TestRelativeTime(t *testing.T) {
+	now := time.Date(2026, 3, 10, 15, 30, 0, 0, time.UTC)
+	for _, c := range []struct {
+		t    time.Time
+		want string
+	}{
+		{time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC), "today"},
+		{time.Date(2026, 3, 11, 0, 0, 0, 0, time.UTC), "tomorrow"},
+		{time.Date(2026, 3, 7, 0, 0, 0, 0, time.UTC), "3 days ago"},
+		{now.Add(-90 * time.Second), "1 minute ago"},
+		{now.Add(5 * time.Hour), "in 5 hours"},
+		{now.Add(-20 * time.Second), "just now"},
+	} {
+		if got := relativeTime(c.t, now); got != c.want {
+			t.Errorf("relativeTime(%v) = %q, want %q", c.t, got, c.want)
+		}
+	}
+}
+
+func TestTemplateNames(t *testing.T) {
+	tags := []asana.Tag{{Name: "bug"}, {Name: "urgent"}}
+	if got := strings.Join(names(tags), ","); got != "bug,urgent" {
+		t.Errorf("names(tags) = %q", got)
+	}
+	if got := names([]string{"a", "b"}); len(got) != 2 || got[1] != "b" {
+		t.Errorf("names(strings) = %v", got)
+	}
+	if got := names((*asana.User)(nil)); got != nil {
+		t.Errorf("names(nil user) = %v", got)
+	}
+}
+
+func TestNoColor(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("TERM", "xterm-256color")
+	if NoColor() {
+		t.Error("NoColor with neither NO_COLOR nor TERM=dumb")
+	}
+	t.Setenv("NO_COLOR", "1")
+	if !NoColor() {
+		t.Error("NoColor ignored NO_COLOR")
+	}
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("TERM", "dumb")
+	if !NoColor() || !DumbTerminal() {
+		t.Error("NoColor ignored TERM=dumb")
+	}
+}
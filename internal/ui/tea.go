@@ -1,6 +1,8 @@
 package ui
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -9,6 +11,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/TheCoolRobot/asana-cli/internal/asana"
 	"github.com/TheCoolRobot/asana-cli/internal/config"
+	"github.com/TheCoolRobot/asana-cli/internal/query"
 )
 
 type TaskListItem struct {
@@ -37,10 +40,11 @@ type Model struct {
 	height          int
 	loading         bool
 	message         string
-	mode            string // "tasks", "projects", "confirm", or "add"
+	mode            string // "tasks", "projects", "confirm", "add" or "filter"
 	projects        []config.ProjectConfig
 	projectCursor   int
 	currentProject  string
+	ctx             context.Context
 	client          *asana.Client
 	projectGID      string
 	confirmAction   string // "delete", "complete"
@@ -50,9 +54,14 @@ type Model struct {
 	// Add form state
 	addFields      [addFieldCount]string
 	addFocusField  addFormField
+
+	// --where filter typed at the / prompt
+	where      *query.Query
+	whereEnv   query.Env
+	whereInput string
 }
 
-func NewModel(tasks []*asana.Task, client *asana.Client, projectGID string) Model {
+func NewModel(ctx context.Context, tasks []*asana.Task, client *asana.Client, projectGID string) Model {
 	cfg, _ := config.Load()
 	items := make([]TaskListItem, len(tasks))
 	for i, t := range tasks {
@@ -69,6 +78,7 @@ func NewModel(tasks []*asana.Task, client *asana.Client, projectGID string) Mode
 		projects:       cfg.ListProjects(),
 		projectCursor:  0,
 		currentProject: cfg.CurrentProject,
+		ctx:            ctx,
 		client:         client,
 		projectGID:     projectGID,
 	}
@@ -91,6 +101,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.updateProjectMode(msg)
 		} else if m.mode == "add" {
 			return m.updateAddMode(msg)
+		} else if m.mode == "filter" {
+			return m.updateFilterMode(msg)
 		}
 		return m.updateTaskMode(msg)
 	}
@@ -103,13 +115,19 @@ func (m Model) updateTaskMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, tea.Quit
 
 	case "up", "k":
-		if m.cursor > 0 {
-			m.cursor--
+		for i := m.cursor - 1; i >= 0; i-- {
+			if m.visible(m.items[i]) {
+				m.cursor = i
+				break
+			}
 		}
 
 	case "down", "j":
-		if m.cursor < len(m.items)-1 {
-			m.cursor++
+		for i := m.cursor + 1; i < len(m.items); i++ {
+			if m.visible(m.items[i]) {
+				m.cursor = i
+				break
+			}
 		}
 
 	case " ":
@@ -161,7 +179,12 @@ func (m Model) updateTaskMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 
 	case "/":
-		m.message = "Enter search query (not implemented in TUI demo)"
+		m.whereInput = ""
+		if m.where != nil {
+			m.whereInput = m.where.String()
+		}
+		m.mode = "filter"
+		m.message = ""
 
 	case "enter":
 		if m.cursor < len(m.items) {
@@ -176,7 +199,7 @@ func (m Model) updateConfirmMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "y":
 		m.loading = true
 		if m.confirmAction == "complete" {
-			_, err := m.client.CompleteTask(m.confirmTaskGID)
+			_, err := m.client.CompleteTask(m.ctx, m.confirmTaskGID)
 			if err != nil {
 				m.message = fmt.Sprintf("❌ Error completing task: %v", err)
 			} else {
@@ -190,7 +213,7 @@ func (m Model) updateConfirmMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				m.message = fmt.Sprintf("✓ Completed: %s", m.confirmTaskName)
 			}
 		} else if m.confirmAction == "delete" {
-			err := m.client.DeleteTask(m.confirmTaskGID)
+			err := m.client.DeleteTask(m.ctx, m.confirmTaskGID)
 			if err != nil {
 				m.message = fmt.Sprintf("❌ Error deleting task: %v", err)
 			} else {
@@ -285,10 +308,22 @@ func (m Model) updateAddMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			Description: strings.TrimSpace(m.addFields[addFieldDescription]),
 			Projects:    []string{m.projectGID},
 			DueOn:       strings.TrimSpace(m.addFields[addFieldDueDate]),
-			Priority:    strings.TrimSpace(m.addFields[addFieldPriority]),
 		}
 
-		task, err := m.client.CreateTask(req)
+		// Priority is the project's "Priority" custom field
+		if priority := strings.TrimSpace(m.addFields[addFieldPriority]); priority != "" {
+			defs, err := m.client.GetCustomFieldSettings(m.ctx, m.projectGID)
+			if err == nil {
+				req.CustomFields, err = asana.ResolveCustomFields(defs, []string{"Priority=" + priority})
+			}
+			if err != nil {
+				m.loading = false
+				m.message = fmt.Sprintf("❌ Error setting priority: %v", err)
+				return m, nil
+			}
+		}
+
+		task, err := m.client.CreateTask(m.ctx, req)
 		m.loading = false
 
 		if err != nil {
@@ -322,6 +357,77 @@ func (m Model) updateAddMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+func (m Model) updateFilterMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.mode = "tasks"
+		m.message = "Cancelled"
+
+	case "enter":
+		expr := strings.TrimSpace(m.whereInput)
+		if expr == "" {
+			m.where = nil
+			m.mode = "tasks"
+			m.message = "Showing: All tasks"
+			return m, nil
+		}
+
+		q, err := query.Parse(expr)
+		if err != nil {
+			m.message = "❌ " + err.Error()
+			var syntaxErr *query.SyntaxError
+			if errors.As(err, &syntaxErr) {
+				m.message += "\n" + syntaxErr.Pointer()
+			}
+			return m, nil
+		}
+
+		m.whereEnv = query.Env{Me: m.whereEnv.Me, Now: time.Now()}
+		if q.UsesMe() && m.whereEnv.Me == "" {
+			me, err := m.client.GetMe(m.ctx)
+			if err != nil {
+				m.message = fmt.Sprintf("❌ Error looking up the current user: %v", err)
+				return m, nil
+			}
+			m.whereEnv.Me = me.GID
+		}
+
+		m.where = q
+		m.mode = "tasks"
+		m.cursor = m.firstVisible()
+		m.message = fmt.Sprintf("Filtering: %s", expr)
+
+	case "backspace":
+		if len(m.whereInput) > 0 {
+			m.whereInput = m.whereInput[:len(m.whereInput)-1]
+		}
+
+	default:
+		if key := msg.String(); len(key) == 1 {
+			m.whereInput += key
+		}
+	}
+	return m, nil
+}
+
+// visible reports whether an item passes the completed toggle and the
+// --where filter
+func (m Model) visible(item TaskListItem) bool {
+	if m.filterCompleted && item.Task.Completed {
+		return false
+	}
+	return m.where == nil || m.where.Match(item.Task, m.whereEnv)
+}
+
+func (m Model) firstVisible() int {
+	for i, item := range m.items {
+		if m.visible(item) {
+			return i
+		}
+	}
+	return 0
+}
+
 func (m Model) toggleSort() {
 	switch m.sortBy {
 	case "name":
@@ -344,6 +450,7 @@ func (m Model) View() string {
 	} else if m.mode == "add" {
 		return m.viewAddForm()
 	}
+	// The filter prompt is drawn below the task list
 	return m.viewTasks()
 }
 
@@ -449,16 +556,25 @@ func (m Model) viewTasks() string {
 	sb.WriteString(StyleTitle.Render(title) + "\n\n")
 
 	// Status line
-	statusLine := fmt.Sprintf("[%d/%d tasks]", len(m.items), len(m.items))
+	shown := 0
+	for _, item := range m.items {
+		if m.visible(item) {
+			shown++
+		}
+	}
+	statusLine := fmt.Sprintf("[%d/%d tasks]", shown, len(m.items))
 	if m.filterCompleted {
 		statusLine += " [filtered]"
 	}
+	if m.where != nil {
+		statusLine += fmt.Sprintf(" [where: %s]", m.where)
+	}
 	statusLine += fmt.Sprintf(" [sort: %s]", m.sortBy)
 	sb.WriteString(StyleDim.Render(statusLine) + "\n\n")
 
 	// Task list
 	for i, item := range m.items {
-		if m.filterCompleted && item.Task.Completed {
+		if !m.visible(item) {
 			continue
 		}
 
@@ -487,10 +603,10 @@ func (m Model) viewTasks() string {
 
 		// Priority indicator
 		priority := ""
-		if item.Task.Priority != "" {
-			if strings.Contains(strings.ToLower(item.Task.Priority), "high") {
+		if p := strings.ToLower(item.Task.Priority()); p != "" {
+			if strings.Contains(p, "high") {
 				priority = " " + StyleHighPriority.Render("!!!")
-			} else if strings.Contains(strings.ToLower(item.Task.Priority), "medium") {
+			} else if strings.Contains(p, "medium") {
 				priority = " " + StyleMediumPriority.Render("!!")
 			}
 		}
@@ -510,23 +626,48 @@ func (m Model) viewTasks() string {
 
 		line := fmt.Sprintf("%s[%s] %s %s%s%s\n", cursor, selected, checkmark, taskName, priority, dueDate)
 		sb.WriteString(line)
+
+		// Custom fields of the highlighted task
+		if m.cursor == i {
+			if fields := customFieldSummary(item.Task); fields != "" {
+				sb.WriteString("         " + StyleDim.Render(fields) + "\n")
+			}
+		}
 	}
 
 	// Footer
 	sb.WriteString("\n" + StyleDim.Render(strings.Repeat("─", m.width)) + "\n")
-	helpText := "[↑↓] navigate  [space] select  [a] add  [c] complete  [d] delete  [f] filter  [s] sort  [p] projects  [q] quit"
-	if m.width < len(helpText) {
-		helpText = "[↑↓] nav  [a] add  [c] done  [d] del  [p] proj  [q] quit"
+	if m.mode == "filter" {
+		sb.WriteString(StyleSelected.Render("where> ") + m.whereInput + "█\n")
+		sb.WriteString(StyleDim.Render("[enter] apply (empty clears)  [esc] cancel  e.g. due < today and not assignee") + "\n")
+	} else {
+		helpText := "[↑↓] navigate  [space] select  [a] add  [c] complete  [d] delete  [f] filter  [/] where  [s] sort  [p] projects  [q] quit"
+		if m.width < len(helpText) {
+			helpText = "[↑↓] nav  [a] add  [c] done  [d] del  [/] where  [p] proj  [q] quit"
+		}
+		sb.WriteString(StyleDim.Render(helpText) + "\n")
 	}
-	sb.WriteString(StyleDim.Render(helpText) + "\n")
 
-	if m.message != "" {
+	if strings.HasPrefix(m.message, "❌") {
+		sb.WriteString("\n" + StyleError.Render(m.message) + "\n")
+	} else if m.message != "" {
 		sb.WriteString("\n" + StyleSuccess.Render(m.message) + "\n")
 	}
 
 	return sb.String()
 }
 
+// customFieldSummary lists a task's set custom fields on one line
+func customFieldSummary(t *asana.Task) string {
+	var parts []string
+	for i := range t.CustomFields {
+		if f := &t.CustomFields[i]; f.IsSet() {
+			parts = append(parts, fmt.Sprintf("%s: %s", f.Name, f.String()))
+		}
+	}
+	return strings.Join(parts, " · ")
+}
+
 func (m Model) viewProjects() string {
 	if len(m.projects) == 0 {
 		return StyleError.Render("No projects configured")
@@ -571,9 +712,9 @@ func (m Model) viewProjects() string {
 	return sb.String()
 }
 
-func StartTUI(tasks []*asana.Task, client *asana.Client, projectGID string) {
-	m := NewModel(tasks, client, projectGID)
-	p := tea.NewProgram(m, tea.WithAltScreen())
+func StartTUI(ctx context.Context, tasks []*asana.Task, client *asana.Client, projectGID string) {
+	m := NewModel(ctx, tasks, client, projectGID)
+	p := tea.NewProgram(m, tea.WithAltScreen(), tea.WithContext(ctx))
 	if _, err := p.Run(); err != nil {
 		fmt.Println("Error:", err)
 	}
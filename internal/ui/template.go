@@ -0,0 +1,264 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/TheCoolRobot/asana-cli/internal/asana"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// TemplateHelp summarises the functions available to --format templates
+const TemplateHelp = `Functions:
+  date LAYOUT T      format a date or time with a Go layout, e.g. date "Jan 2"
+  ago T              relative time: "today", "in 3 days", "2 hours ago"
+  truncate N S       cut S to N columns, ending in …
+  pad N S            pad S with spaces to N columns
+  join SEP LIST      join strings, or the names of tags, projects or users
+  names LIST         the names of tags, projects or users
+  color NAME S       color S (red, green, yellow, blue, magenta, cyan, gray or a number/hex)
+  bold S, dim S      style S
+  upper S, lower S   change case
+  default D V        D when V is empty, else V (records by name)
+  json V             V as JSON`
+
+// ansiColors maps color names to the basic terminal palette
+var ansiColors = map[string]string{
+	"black":   "0",
+	"red":     "1",
+	"green":   "2",
+	"yellow":  "3",
+	"blue":    "4",
+	"magenta": "5",
+	"cyan":    "6",
+	"white":   "7",
+	"gray":    "8",
+	"grey":    "8",
+}
+
+// TemplateFuncs returns the helper functions available to --format
+// templates. Arguments are ordered so that the value can be piped in,
+// as in {{.DueDate | date "Jan 2"}}.
+func TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"date": func(layout string, v interface{}) string {
+			t, ok := toTime(v)
+			if !ok {
+				return ""
+			}
+			return t.Format(layout)
+		},
+		"ago": func(v interface{}) string {
+			t, ok := toTime(v)
+			if !ok {
+				return ""
+			}
+			return relativeTime(t, time.Now())
+		},
+		"truncate": func(n int, s string) string {
+			return truncate(s, n)
+		},
+		"pad": func(n int, s string) string {
+			if w := lipgloss.Width(s); w < n {
+				return s + strings.Repeat(" ", n-w)
+			}
+			return s
+		},
+		"join": func(sep string, v interface{}) string {
+			return strings.Join(names(v), sep)
+		},
+		"names": names,
+		"color": func(name, s string) string {
+			if c, ok := ansiColors[strings.ToLower(name)]; ok {
+				name = c
+			}
+			return lipgloss.NewStyle().Foreground(lipgloss.Color(name)).Render(s)
+		},
+		"bold": func(s string) string {
+			return lipgloss.NewStyle().Bold(true).Render(s)
+		},
+		"dim": func(s string) string {
+			return StyleDim.Render(s)
+		},
+		"upper": strings.ToUpper,
+		"lower": strings.ToLower,
+		"default": func(def string, v interface{}) interface{} {
+			if isEmpty(v) {
+				return def
+			}
+			// Records such as an assignee show by name
+			switch reflect.Indirect(reflect.ValueOf(v)).Kind() {
+			case reflect.Struct:
+				if _, ok := toTime(v); !ok {
+					return nameOf(reflect.ValueOf(v))
+				}
+			case reflect.Slice, reflect.Array:
+				return strings.Join(names(v), ", ")
+			}
+			return v
+		},
+		"json": func(v interface{}) (string, error) {
+			b, err := json.Marshal(v)
+			return string(b), err
+		},
+	}
+}
+
+// ParseTemplate compiles a --format template with the helper functions
+func ParseTemplate(text string) (*template.Template, error) {
+	return template.New("format").Funcs(TemplateFuncs()).Parse(text)
+}
+
+// PrintTemplate executes the template for data, or for each element if
+// data is a slice, ending each result with a newline if it has none
+func PrintTemplate(w io.Writer, tmpl *template.Template, data interface{}) error {
+	items := []interface{}{data}
+	if v := reflect.ValueOf(data); v.Kind() == reflect.Slice {
+		items = make([]interface{}, v.Len())
+		for i := range items {
+			items[i] = v.Index(i).Interface()
+		}
+	}
+
+	var b bytes.Buffer
+	for _, item := range items {
+		if err := tmpl.Execute(&b, item); err != nil {
+			return err
+		}
+		if b.Len() > 0 && b.Bytes()[b.Len()-1] != '\n' {
+			b.WriteByte('\n')
+		}
+	}
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+func toTime(v interface{}) (time.Time, bool) {
+	var t time.Time
+	switch v := v.(type) {
+	case time.Time:
+		t = v
+	case *time.Time:
+		if v != nil {
+			t = *v
+		}
+	case asana.CustomTime:
+		t = v.Time
+	case *asana.CustomTime:
+		if v != nil {
+			t = v.Time
+		}
+	case string:
+		for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+			if parsed, err := time.Parse(layout, v); err == nil {
+				t = parsed
+				break
+			}
+		}
+	}
+	return t, !t.IsZero()
+}
+
+// relativeTime describes t relative to now. Dates without a time of day,
+// such as due dates, count in whole days.
+func relativeTime(t, now time.Time) string {
+	if h, m, s := t.Clock(); h == 0 && m == 0 && s == 0 && t.Location() == time.UTC {
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		days := int(t.Sub(today).Hours() / 24)
+		switch {
+		case days == 0:
+			return "today"
+		case days == 1:
+			return "tomorrow"
+		case days == -1:
+			return "yesterday"
+		case days > 0:
+			return fmt.Sprintf("in %d days", days)
+		}
+		return fmt.Sprintf("%d days ago", -days)
+	}
+
+	d := t.Sub(now)
+	future := d > 0
+	if !future {
+		d = -d
+	}
+	var amount string
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		amount = plural(int(d/time.Minute), "minute")
+	case d < 24*time.Hour:
+		amount = plural(int(d/time.Hour), "hour")
+	default:
+		amount = plural(int(d/(24*time.Hour)), "day")
+	}
+	if future {
+		return "in " + amount
+	}
+	return amount + " ago"
+}
+
+func plural(n int, unit string) string {
+	if n == 1 {
+		return "1 " + unit
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
+
+// names lists the elements of a slice as strings, using the Name of
+// records such as tags, projects and users
+func names(v interface{}) []string {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		if isEmpty(v) {
+			return nil
+		}
+		return []string{nameOf(rv)}
+	}
+	out := make([]string, 0, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		out = append(out, nameOf(rv.Index(i)))
+	}
+	return out
+}
+
+func nameOf(v reflect.Value) string {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.Struct {
+		if name := v.FieldByName("Name"); name.IsValid() && name.Kind() == reflect.String {
+			return name.String()
+		}
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+func isEmpty(v interface{}) bool {
+	switch v.(type) {
+	case nil:
+		return true
+	case time.Time, *time.Time, asana.CustomTime, *asana.CustomTime:
+		_, ok := toTime(v)
+		return !ok
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice:
+		if rv.IsNil() {
+			return true
+		}
+	}
+	return rv.IsZero() || (rv.Kind() == reflect.Slice && rv.Len() == 0)
+}
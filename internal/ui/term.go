@@ -0,0 +1,40 @@
+package ui
+
+import (
+	"os"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-isatty"
+	"github.com/muesli/termenv"
+)
+
+// IsTerminal reports whether f is an interactive terminal
+func IsTerminal(f *os.File) bool {
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
+
+// DumbTerminal reports whether TERM=dumb, which can show neither colors nor
+// the TUI
+func DumbTerminal() bool {
+	return os.Getenv("TERM") == "dumb"
+}
+
+// NoColor reports whether the environment asks for plain output, through
+// NO_COLOR (https://no-color.org) or TERM=dumb
+func NoColor() bool {
+	return os.Getenv("NO_COLOR") != "" || DumbTerminal()
+}
+
+// Interactive reports whether the TUI can run: stdin and stdout are both
+// terminals that can draw it
+func Interactive() bool {
+	return IsTerminal(os.Stdin) && IsTerminal(os.Stdout) && !DumbTerminal()
+}
+
+// SetupColor turns off every style, not only colors, when NoColor says so.
+// lipgloss already drops colors when stdout is not a terminal.
+func SetupColor() {
+	if NoColor() {
+		lipgloss.SetColorProfile(termenv.Ascii)
+	}
+}
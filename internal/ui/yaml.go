@@ -0,0 +1,208 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// orderedMap is a JSON object that remembers the order of its keys, so
+// records print with their fields in struct order
+type orderedMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func newOrderedMap() *orderedMap {
+	return &orderedMap{values: map[string]interface{}{}}
+}
+
+func (m *orderedMap) set(key string, value interface{}) {
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+func (m *orderedMap) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, key := range m.keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		v, err := json.Marshal(m.values[key])
+		if err != nil {
+			return nil, err
+		}
+		b.Write(k)
+		b.WriteByte(':')
+		b.Write(v)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// decodeOrdered decodes JSON into *orderedMap, []interface{}, string,
+// json.Number, bool and nil values
+func decodeOrdered(data []byte) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return decodeValue(dec)
+}
+
+func decodeValue(dec *json.Decoder) (interface{}, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch tok {
+	case json.Delim('{'):
+		m := newOrderedMap()
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			m.set(key.(string), value)
+		}
+		_, err = dec.Token()
+		return m, err
+	case json.Delim('['):
+		list := []interface{}{}
+		for dec.More() {
+			value, err := decodeValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+		}
+		_, err = dec.Token()
+		return list, err
+	}
+	return tok, nil
+}
+
+// writeYAML writes a decoded value as a YAML document. Strings that YAML
+// would read as something else are double-quoted.
+func writeYAML(b *bytes.Buffer, v interface{}) {
+	switch v := v.(type) {
+	case *orderedMap:
+		if len(v.keys) > 0 {
+			writeYAMLMap(b, v, "", false)
+			return
+		}
+		b.WriteString("{}\n")
+	case []interface{}:
+		if len(v) > 0 {
+			writeYAMLList(b, v, "")
+			return
+		}
+		b.WriteString("[]\n")
+	default:
+		b.WriteString(yamlScalar(v) + "\n")
+	}
+}
+
+// writeYAMLValue finishes a line started by a key or a list dash: scalars
+// and empty collections follow on the same line, anything else is nested
+// below at indent
+func writeYAMLValue(b *bytes.Buffer, v interface{}, indent string) {
+	switch v := v.(type) {
+	case *orderedMap:
+		if len(v.keys) > 0 {
+			b.WriteString("\n")
+			writeYAMLMap(b, v, indent, false)
+			return
+		}
+		b.WriteString(" {}\n")
+	case []interface{}:
+		if len(v) > 0 {
+			b.WriteString("\n")
+			writeYAMLList(b, v, indent)
+			return
+		}
+		b.WriteString(" []\n")
+	default:
+		b.WriteString(" " + yamlScalar(v) + "\n")
+	}
+}
+
+// writeYAMLMap writes a mapping at indent. In a list, the first key goes
+// on the dash's line.
+func writeYAMLMap(b *bytes.Buffer, m *orderedMap, indent string, afterDash bool) {
+	for i, key := range m.keys {
+		if i > 0 || !afterDash {
+			b.WriteString(indent)
+		}
+		b.WriteString(yamlScalar(key) + ":")
+		writeYAMLValue(b, m.values[key], indent+"  ")
+	}
+}
+
+func writeYAMLList(b *bytes.Buffer, list []interface{}, indent string) {
+	for _, item := range list {
+		if m, ok := item.(*orderedMap); ok && len(m.keys) > 0 {
+			b.WriteString(indent + "- ")
+			writeYAMLMap(b, m, indent+"  ", true)
+			continue
+		}
+		b.WriteString(indent + "-")
+		writeYAMLValue(b, item, indent+"  ")
+	}
+}
+
+func yamlScalar(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return strconv.FormatBool(v)
+	case json.Number:
+		return v.String()
+	case string:
+		if yamlPlain(v) {
+			return v
+		}
+		var b bytes.Buffer
+		enc := json.NewEncoder(&b)
+		enc.SetEscapeHTML(false)
+		enc.Encode(v)
+		return strings.TrimSuffix(b.String(), "\n")
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+// yamlPlain reports whether s can be written unquoted and still read back
+// as the same string
+func yamlPlain(s string) bool {
+	if s == "" || s != strings.TrimSpace(s) {
+		return false
+	}
+	switch strings.ToLower(s) {
+	case "null", "~", "true", "false", "yes", "no", "on", "off", "y", "n":
+		return false
+	}
+	if strings.ContainsAny(s[:1], "-?:,[]{}#&*!|>'\"%@`.+0123456789") {
+		return false
+	}
+	if strings.Contains(s, ": ") || strings.Contains(s, " #") || strings.HasSuffix(s, ":") {
+		return false
+	}
+	for _, r := range s {
+		if r < ' ' {
+			return false
+		}
+	}
+	return true
+}